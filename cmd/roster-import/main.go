@@ -0,0 +1,53 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"lesson-management/internal/modules/roster"
+	"lesson-management/pkg/common"
+
+	"github.com/joho/godotenv"
+)
+
+// roster-import enrolls students from a CSV file with email, name and lesson_id columns.
+// It prints the validation report as JSON and exits non-zero when the roster is rejected.
+func main() {
+	file := flag.String("file", "", "CSV file to import (reads standard input when empty)")
+	dryRun := flag.Bool("dry-run", false, "validate the roster without importing it")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	var input io.Reader = os.Stdin
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("❌ Failed to open roster: %v", err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	common.InitDB()
+
+	service := roster.NewRosterService(roster.NewRosterRepository())
+	report, err := service.Import(input, *dryRun)
+	if err != nil && !errors.Is(err, roster.ErrInvalidRoster) {
+		log.Fatalf("❌ Failed to import roster: %v", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("❌ Failed to write report: %v", err)
+	}
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
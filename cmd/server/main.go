@@ -21,14 +21,62 @@ func main() {
 	}
 
 	common.InitDB()
+	// lesson_students carries enrollment status, so register it before migrating
+	if err := common.DB.SetupJoinTable(&entities.Lesson{}, "Students", &entities.Enrollment{}); err != nil {
+		log.Fatalf("❌ Failed to set up enrollment join table: %v", err)
+	}
+
 	// Migrate all entities including new ones
 	common.DB.AutoMigrate(
 		&entities.Admin{},
 		&entities.Teacher{},
 		&entities.Student{},
+		&entities.Guardian{},
+		&entities.AcademicYear{},
+		&entities.Term{},
+		&entities.Subject{},
+		&entities.Tag{},
 		&entities.Lesson{},
+		&entities.LessonTeacher{},
+		&entities.LessonSession{},
+		&entities.AttendanceRecord{},
+		&entities.CheckInWindow{},
+		&entities.GradingScale{},
+		&entities.GradingScaleBand{},
+		&entities.GradeCategory{},
+		&entities.GradeItem{},
+		&entities.Grade{},
+		&entities.Rubric{},
+		&entities.RubricCriterion{},
+		&entities.RubricLevel{},
+		&entities.Assignment{},
+		&entities.AssignmentSubmission{},
+		&entities.RubricAssessment{},
+		&entities.RubricCriterionScore{},
+		&entities.MaterialSection{},
+		&entities.Material{},
+		&entities.MaterialDownload{},
+		&entities.Quiz{},
+		&entities.QuizQuestion{},
+		&entities.QuizOption{},
+		&entities.QuizAttempt{},
+		&entities.QuizResponse{},
+		&entities.ProgressRecord{},
+		&entities.EnrollmentRequest{},
+		&entities.EnrollmentEvent{},
+		&entities.PrerequisiteOverride{},
+		&entities.Course{},
+		&entities.Module{},
+		&entities.ModuleLesson{},
+		&entities.CourseEnrollment{},
+		&entities.LessonTemplate{},
+		&entities.CalendarToken{},
 	)
 
+	if err := common.RunMigrations(); err != nil {
+		log.Fatalf("❌ Failed to run migrations: %v", err)
+	}
+
 	api := InitRoutes()
 	fmt.Println("✅ Server running on port:", port)
 	log.Fatal(http.ListenAndServe(":"+port, api))
@@ -1,9 +1,28 @@
 package main
 
 import (
+	"log"
+	"time"
+
+	"lesson-management/internal/modules/assignments"
+	"lesson-management/internal/modules/attendance"
 	"lesson-management/internal/modules/auth"
+	"lesson-management/internal/modules/calendar"
+	"lesson-management/internal/modules/courses"
+	"lesson-management/internal/modules/files"
+	"lesson-management/internal/modules/gradebook"
 	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/materials"
+	"lesson-management/internal/modules/progress"
+	"lesson-management/internal/modules/quizzes"
+	"lesson-management/internal/modules/roster"
+	"lesson-management/internal/modules/rubrics"
+	"lesson-management/internal/modules/sessions"
 	"lesson-management/internal/modules/students"
+	"lesson-management/internal/modules/subjects"
+	"lesson-management/internal/modules/templates"
+	"lesson-management/internal/modules/terms"
+	"lesson-management/pkg/storage"
 
 	"github.com/gorilla/mux"
 )
@@ -17,11 +36,20 @@ func InitRoutes() *mux.Router {
 	authHandler := auth.NewAuthHandler(authService)
 	auth.InitRoutes(router, authHandler)
 
+	// Initialize File storage and signed downloads
+	uploads, err := storage.NewUploads()
+	if err != nil {
+		log.Fatalf("❌ Failed to set up file storage: %v", err)
+	}
+	fileHandler := files.NewFileHandler(uploads)
+	files.InitRoutes(router, fileHandler)
+
 	// Initialize Lessons
 	lessonRepo := lessons.NewLessonRepository()
-	lessonService := lessons.NewLessonService(lessonRepo)
+	lessonService := lessons.NewLessonService(lessonRepo, uploads.Storage)
 	lessonHandler := lessons.NewLessonHandler(lessonService)
 	lessons.InitRoutes(router, lessonHandler, authService)
+	lessons.StartScheduler(lessonService, time.Minute)
 
 	// Initialize Students
 	studentRepo := students.NewStudentRepository()
@@ -29,5 +57,89 @@ func InitRoutes() *mux.Router {
 	studentHandler := students.NewStudentHandler(studentService)
 	students.InitRoutes(router, studentHandler, authService)
 
+	// Initialize Courses
+	courseRepo := courses.NewCourseRepository()
+	courseService := courses.NewCourseService(courseRepo, lessonService)
+	courseHandler := courses.NewCourseHandler(courseService)
+	courses.InitRoutes(router, courseHandler, authService)
+
+	// Initialize Terms
+	termRepo := terms.NewTermRepository()
+	termService := terms.NewTermService(termRepo, lessonService)
+	termHandler := terms.NewTermHandler(termService)
+	terms.InitRoutes(router, termHandler, authService)
+
+	// Initialize Lesson Templates
+	templateRepo := templates.NewTemplateRepository()
+	templateService := templates.NewTemplateService(templateRepo, lessonService)
+	templateHandler := templates.NewTemplateHandler(templateService)
+	templates.InitRoutes(router, templateHandler, authService)
+
+	// Initialize Subjects and Tags
+	subjectRepo := subjects.NewSubjectRepository()
+	subjectService := subjects.NewSubjectService(subjectRepo)
+	subjectHandler := subjects.NewSubjectHandler(subjectService)
+	subjects.InitRoutes(router, subjectHandler, authService)
+
+	// Initialize Sessions
+	sessionRepo := sessions.NewSessionRepository()
+	sessionService := sessions.NewSessionService(sessionRepo, lessonService)
+	sessionHandler := sessions.NewSessionHandler(sessionService)
+	sessions.InitRoutes(router, sessionHandler, authService)
+
+	// Initialize Attendance
+	attendanceRepo := attendance.NewAttendanceRepository()
+	attendanceService := attendance.NewAttendanceService(attendanceRepo, lessonService, sessionService)
+	attendanceHandler := attendance.NewAttendanceHandler(attendanceService)
+	attendance.InitRoutes(router, attendanceHandler, authService)
+
+	// Initialize Gradebook
+	gradebookRepo := gradebook.NewGradebookRepository()
+	gradebookService := gradebook.NewGradebookService(gradebookRepo, lessonService)
+	gradebookHandler := gradebook.NewGradebookHandler(gradebookService)
+	gradebook.InitRoutes(router, gradebookHandler, authService)
+
+	// Initialize Progress tracking
+	progressRepo := progress.NewProgressRepository()
+	progressService := progress.NewProgressService(progressRepo, lessonService)
+	progressHandler := progress.NewProgressHandler(progressService)
+	progress.InitRoutes(router, progressHandler, authService)
+
+	// Initialize Rubrics
+	rubricRepo := rubrics.NewRubricRepository()
+	rubricService := rubrics.NewRubricService(rubricRepo)
+	rubricHandler := rubrics.NewRubricHandler(rubricService)
+	rubrics.InitRoutes(router, rubricHandler, authService)
+
+	// Initialize Assignments
+	assignmentRepo := assignments.NewAssignmentRepository()
+	assignmentService := assignments.NewAssignmentService(assignmentRepo, lessonService, gradebookService, rubricService, progressService, uploads)
+	assignmentHandler := assignments.NewAssignmentHandler(assignmentService, uploads.MaxUploadBytes)
+	assignments.InitRoutes(router, assignmentHandler, authService)
+
+	// Initialize Lesson materials
+	materialRepo := materials.NewMaterialRepository()
+	materialService := materials.NewMaterialService(materialRepo, lessonService, progressService, uploads)
+	materialHandler := materials.NewMaterialHandler(materialService, uploads.MaxUploadBytes)
+	materials.InitRoutes(router, materialHandler, authService)
+
+	// Initialize Quizzes
+	quizRepo := quizzes.NewQuizRepository()
+	quizService := quizzes.NewQuizService(quizRepo, lessonService, gradebookService, progressService)
+	quizHandler := quizzes.NewQuizHandler(quizService)
+	quizzes.InitRoutes(router, quizHandler, authService)
+
+	// Initialize Calendar feeds
+	calendarRepo := calendar.NewCalendarRepository()
+	calendarService := calendar.NewCalendarService(calendarRepo, lessonService, sessionService)
+	calendarHandler := calendar.NewCalendarHandler(calendarService)
+	calendar.InitRoutes(router, calendarHandler, authService)
+
+	// Initialize Roster import
+	rosterRepo := roster.NewRosterRepository()
+	rosterService := roster.NewRosterService(rosterRepo)
+	rosterHandler := roster.NewRosterHandler(rosterService)
+	roster.InitRoutes(router, rosterHandler, authService)
+
 	return router
 }
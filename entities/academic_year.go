@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+type AcademicYear struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null;uniqueIndex" json:"name"`
+	StartsOn  time.Time `gorm:"not null" json:"starts_on"`
+	EndsOn    time.Time `gorm:"not null" json:"ends_on"`
+	Terms     []Term    `gorm:"foreignKey:AcademicYearID" json:"terms,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
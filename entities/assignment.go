@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// Late policies. LatePolicyAllow accepts late work, up to LateUntil when it is set, and flags
+// it as late; LatePolicyDeny refuses submissions after the due date
+const (
+	LatePolicyAllow = "allow"
+	LatePolicyDeny  = "deny"
+)
+
+// Assignment is homework handed out in a lesson and collected as file submissions. With a rubric
+// attached, teachers mark it criterion by criterion, and with a grade item linked the marks go
+// into the gradebook
+type Assignment struct {
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	LessonID           uint       `gorm:"not null;index" json:"lesson_id"`
+	Title              string     `gorm:"not null" json:"title"`
+	Instructions       string     `json:"instructions"`
+	DueAt              time.Time  `gorm:"not null" json:"due_at"`
+	LatePolicy         string     `gorm:"type:varchar(10);not null;default:'allow'" json:"late_policy"`
+	LateUntil          *time.Time `json:"late_until,omitempty"`
+	LatePenaltyPercent float64    `gorm:"not null;default:0" json:"late_penalty_percent"`
+	RubricID           *uint      `gorm:"index" json:"rubric_id,omitempty"`
+	GradeItemID        *uint      `gorm:"index" json:"grade_item_id,omitempty"`
+	CreatedBy          uint       `json:"created_by"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// IsLate reports whether a submission made at the given time is past the due date
+func (a *Assignment) IsLate(at time.Time) bool {
+	return at.After(a.DueAt)
+}
+
+// AcceptsSubmission reports whether the late policy still lets students submit at the given time
+func (a *Assignment) AcceptsSubmission(at time.Time) bool {
+	if !a.IsLate(at) {
+		return true
+	}
+	if a.LatePolicy == LatePolicyDeny {
+		return false
+	}
+	return a.LateUntil == nil || !at.After(*a.LateUntil)
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+// AssignmentSubmission is one uploaded version of a student's work. Resubmitting adds a
+// new version and keeps the earlier ones
+type AssignmentSubmission struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	LessonID     uint      `gorm:"not null;index" json:"lesson_id"`
+	AssignmentID uint      `gorm:"not null;uniqueIndex:idx_submission_version" json:"assignment_id"`
+	StudentID    uint      `gorm:"not null;uniqueIndex:idx_submission_version;index" json:"student_id"`
+	Version      int       `gorm:"not null;uniqueIndex:idx_submission_version" json:"version"`
+	FileName     string    `gorm:"not null" json:"file_name"`
+	ContentType  string    `gorm:"not null" json:"content_type"`
+	Size         int64     `gorm:"not null" json:"size"`
+	Checksum     string    `gorm:"not null;default:''" json:"checksum"`
+	StorageKey   string    `gorm:"not null" json:"-"`
+	Late         bool      `gorm:"not null;default:false" json:"late"`
+	SubmittedAt  time.Time `gorm:"not null" json:"submitted_at"`
+}
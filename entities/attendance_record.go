@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceLate    = "late"
+	AttendanceExcused = "excused"
+)
+
+// How an attendance record was taken
+const (
+	AttendanceByTeacher = "teacher"
+	AttendanceByCheckIn = "check_in"
+)
+
+// AttendanceRecord is a student's attendance at one lesson session. LessonID mirrors the
+// session's lesson so records can be listed and purged per lesson
+type AttendanceRecord struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	LessonID   uint           `gorm:"not null;index" json:"lesson_id"`
+	SessionID  uint           `gorm:"not null;uniqueIndex:idx_attendance_session_student" json:"session_id"`
+	Session    *LessonSession `gorm:"foreignKey:SessionID" json:"session,omitempty"`
+	StudentID  uint           `gorm:"not null;uniqueIndex:idx_attendance_session_student;index" json:"student_id"`
+	Status     string         `gorm:"not null" json:"status"`
+	Note       string         `json:"note,omitempty"`
+	Method     string         `gorm:"not null;default:'teacher'" json:"method"`
+	RecordedBy uint           `json:"recorded_by,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// CalendarToken is the secret in a user's calendar feed URL. Teachers and students
+// live in separate tables, so the owner is identified by role and ID together
+type CalendarToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	OwnerRole string    `gorm:"not null;uniqueIndex:idx_calendar_token_owner" json:"owner_role"`
+	OwnerID   uint      `gorm:"not null;uniqueIndex:idx_calendar_token_owner" json:"owner_id"`
+	Token     string    `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
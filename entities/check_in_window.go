@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// CheckInWindow lets students mark themselves present for a session with a rotating code.
+// Reopening a session's window replaces the secret, invalidating every earlier code
+type CheckInWindow struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	LessonID        uint      `gorm:"not null;index" json:"lesson_id"`
+	SessionID       uint      `gorm:"not null;uniqueIndex" json:"session_id"`
+	Secret          []byte    `gorm:"not null" json:"-"`
+	OpensAt         time.Time `gorm:"not null" json:"opens_at"`
+	ClosesAt        time.Time `gorm:"not null" json:"closes_at"`
+	RotationSeconds int       `gorm:"not null" json:"rotation_seconds"`
+	OpenedBy        uint      `json:"opened_by"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// IsOpen reports whether students can check in at the given time
+func (w *CheckInWindow) IsOpen(at time.Time) bool {
+	return !at.Before(w.OpensAt) && at.Before(w.ClosesAt)
+}
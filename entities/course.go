@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+type Course struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Title       string    `gorm:"not null" json:"title"`
+	Description string    `json:"description"`
+	Modules     []Module  `gorm:"foreignKey:CourseID" json:"modules,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
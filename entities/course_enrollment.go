@@ -0,0 +1,9 @@
+package entities
+
+import "time"
+
+type CourseEnrollment struct {
+	CourseID  uint      `gorm:"primaryKey" json:"course_id"`
+	StudentID uint      `gorm:"primaryKey;index" json:"student_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+const (
+	EnrollmentActive    = "active"
+	EnrollmentCompleted = "completed"
+)
+
+// Enrollment is the join row between lessons and students
+type Enrollment struct {
+	LessonID    uint       `gorm:"primaryKey" json:"lesson_id"`
+	StudentID   uint       `gorm:"primaryKey" json:"student_id"`
+	Status      string     `gorm:"default:'active'" json:"status"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (Enrollment) TableName() string {
+	return "lesson_students"
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+const (
+	EnrollmentEventEnrolled    = "enrolled"
+	EnrollmentEventRemoved     = "removed"
+	EnrollmentEventTransferred = "transferred"
+	EnrollmentEventCompleted   = "completed"
+)
+
+// EnrollmentEvent is one entry in a student's enrollment history. A transfer is
+// recorded once, against the target lesson, with FromLessonID naming the source
+type EnrollmentEvent struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	LessonID     uint      `gorm:"not null;index" json:"lesson_id"`
+	StudentID    uint      `gorm:"not null;index" json:"student_id"`
+	FromLessonID *uint     `gorm:"index" json:"from_lesson_id,omitempty"`
+	Type         string    `gorm:"not null" json:"type"`
+	CreatedAt    time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+const (
+	EnrollmentRequestPending  = "pending"
+	EnrollmentRequestApproved = "approved"
+	EnrollmentRequestRejected = "rejected"
+)
+
+type EnrollmentRequest struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	LessonID  uint       `gorm:"not null;index" json:"lesson_id"`
+	Lesson    *Lesson    `gorm:"foreignKey:LessonID" json:"lesson,omitempty"`
+	StudentID uint       `gorm:"not null;index" json:"student_id"`
+	Student   *Student   `gorm:"foreignKey:StudentID" json:"student,omitempty"`
+	Status    string     `gorm:"default:'pending';index" json:"status"`
+	DecidedBy *uint      `json:"decided_by,omitempty"`
+	DecidedAt *time.Time `json:"decided_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// Grade is a student's result for one grade item. Excused grades count towards nothing.
+// GradedBy is zero for grades written automatically, such as quiz scores
+type Grade struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	LessonID    uint      `gorm:"not null;index" json:"lesson_id"`
+	GradeItemID uint      `gorm:"not null;uniqueIndex:idx_grade_item_student" json:"grade_item_id"`
+	StudentID   uint      `gorm:"not null;uniqueIndex:idx_grade_item_student;index" json:"student_id"`
+	Points      float64   `json:"points"`
+	Excused     bool      `gorm:"default:false" json:"excused"`
+	Feedback    string    `json:"feedback,omitempty"`
+	GradedBy    uint      `json:"graded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
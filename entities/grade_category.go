@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// GradeCategory groups a lesson's grade items. Weights are relative: a student's total is
+// the weighted average of the categories that have graded items
+type GradeCategory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	LessonID  uint      `gorm:"not null;uniqueIndex:idx_grade_category_lesson_name" json:"lesson_id"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_grade_category_lesson_name" json:"name"`
+	Weight    float64   `gorm:"not null" json:"weight"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
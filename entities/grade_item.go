@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// GradeItem is one gradable piece of work in a lesson, such as a test or a project
+type GradeItem struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	LessonID   uint           `gorm:"not null;index" json:"lesson_id"`
+	CategoryID uint           `gorm:"not null;index" json:"category_id"`
+	Category   *GradeCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Title      string         `gorm:"not null" json:"title"`
+	MaxPoints  float64        `gorm:"not null" json:"max_points"`
+	DueAt      *time.Time     `json:"due_at,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+import "time"
+
+// GradingScale converts a percentage into a letter grade
+type GradingScale struct {
+	ID        uint               `gorm:"primaryKey" json:"id"`
+	Name      string             `gorm:"not null;uniqueIndex" json:"name"`
+	Bands     []GradingScaleBand `gorm:"foreignKey:GradingScaleID" json:"bands"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// LetterFor returns the letter of the highest band the percentage reaches, or an empty
+// string when it is below every band. Bands must be ordered by MinPercent, highest first
+func (s *GradingScale) LetterFor(percent float64) string {
+	for _, band := range s.Bands {
+		if percent >= band.MinPercent {
+			return band.Letter
+		}
+	}
+	return ""
+}
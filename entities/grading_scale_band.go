@@ -0,0 +1,9 @@
+package entities
+
+// GradingScaleBand awards Letter to percentages of at least MinPercent
+type GradingScaleBand struct {
+	ID             uint    `gorm:"primaryKey" json:"id"`
+	GradingScaleID uint    `gorm:"not null;index" json:"grading_scale_id"`
+	Letter         string  `gorm:"not null" json:"letter"`
+	MinPercent     float64 `gorm:"not null" json:"min_percent"`
+}
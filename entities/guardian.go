@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+type Guardian struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
+	Password  string    `gorm:"not null" json:"-"`
+	Role      string    `gorm:"default:'guardian'" json:"role"`
+	Students  []Student `gorm:"many2many:guardian_students" json:"students,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,14 +1,61 @@
 package entities
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+const (
+	LessonDraft     = "draft"
+	LessonPublished = "published"
+	LessonArchived  = "archived"
+)
+
+// Lesson is a class students enroll in. With CompletionPercent set, an enrollment is marked
+// completed once the student has worked through that share of the lesson's content
 type Lesson struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `gorm:"not null" json:"title"`
-	Description string    `json:"description"`
-	TeacherID   uint      `json:"teacher_id"`
-	Teacher     Teacher   `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
-	Students    []Student `gorm:"many2many:lesson_students;" json:"students,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                 uint            `gorm:"primaryKey" json:"id"`
+	Title              string          `gorm:"not null" json:"title"`
+	Description        string          `json:"description"`
+	TeacherID          uint            `json:"teacher_id"`
+	Teacher            Teacher         `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+	Teachers           []LessonTeacher `gorm:"foreignKey:LessonID" json:"teachers,omitempty"`
+	Students           []Student       `gorm:"many2many:lesson_students;" json:"students,omitempty"`
+	TermID             *uint           `gorm:"index;uniqueIndex:idx_lesson_term_rollover" json:"term_id,omitempty"`
+	Term               *Term           `gorm:"foreignKey:TermID" json:"term,omitempty"`
+	SubjectID          *uint           `gorm:"index" json:"subject_id,omitempty"`
+	Subject            *Subject        `gorm:"foreignKey:SubjectID" json:"subject,omitempty"`
+	Tags               []Tag           `gorm:"many2many:lesson_tags;" json:"tags,omitempty"`
+	Prerequisites      []Lesson        `gorm:"many2many:lesson_prerequisites;joinForeignKey:LessonID;joinReferences:PrerequisiteID" json:"prerequisites,omitempty"`
+	SelfEnrollment     bool            `gorm:"default:false" json:"self_enrollment"`
+	RequiresApproval   bool            `gorm:"default:false" json:"requires_approval"`
+	EnrollmentOpensAt  *time.Time      `json:"enrollment_opens_at,omitempty"`
+	EnrollmentClosesAt *time.Time      `json:"enrollment_closes_at,omitempty"`
+	Capacity           int             `gorm:"default:0" json:"capacity"`
+	GradingScaleID     *uint           `gorm:"index" json:"grading_scale_id,omitempty"`
+	CompletionPercent  int             `gorm:"not null;default:0" json:"completion_percent"`
+	Status             string          `gorm:"default:'draft';index" json:"status"`
+	PublishAt          *time.Time      `json:"publish_at,omitempty"`
+	UnpublishAt        *time.Time      `json:"unpublish_at,omitempty"`
+	PublishedAt        *time.Time      `json:"published_at,omitempty"`
+	ArchivedAt         *time.Time      `json:"archived_at,omitempty"`
+	RolledOverFromID   *uint           `gorm:"uniqueIndex:idx_lesson_term_rollover" json:"rolled_over_from_id,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+// EnrollmentOpen reports whether students can self-enroll at the given time
+func (l *Lesson) EnrollmentOpen(at time.Time) bool {
+	if !l.SelfEnrollment {
+		return false
+	}
+	if l.EnrollmentOpensAt != nil && at.Before(*l.EnrollmentOpensAt) {
+		return false
+	}
+	if l.EnrollmentClosesAt != nil && !at.Before(*l.EnrollmentClosesAt) {
+		return false
+	}
+	return true
 }
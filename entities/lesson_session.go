@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+const (
+	SessionScheduled = "scheduled"
+	SessionCancelled = "cancelled"
+)
+
+// LessonSession is one scheduled meeting of a lesson. Sequence grows with every change so
+// calendar clients replace their copy of the event
+type LessonSession struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	LessonID  uint      `gorm:"not null;index" json:"lesson_id"`
+	StartsAt  time.Time `gorm:"not null;index" json:"starts_at"`
+	EndsAt    time.Time `gorm:"not null" json:"ends_at"`
+	Location  string    `json:"location"`
+	Status    string    `gorm:"default:'scheduled'" json:"status"`
+	Sequence  int       `gorm:"not null;default:0" json:"sequence"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
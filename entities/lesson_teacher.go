@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+const (
+	LessonTeacherLead = "lead"
+	LessonTeacherCo   = "co_teacher"
+)
+
+// LessonTeacher assigns a teacher to a lesson; the lead is mirrored in Lesson.TeacherID
+type LessonTeacher struct {
+	LessonID  uint      `gorm:"primaryKey" json:"lesson_id"`
+	TeacherID uint      `gorm:"primaryKey;index" json:"teacher_id"`
+	Teacher   *Teacher  `gorm:"foreignKey:TeacherID" json:"teacher,omitempty"`
+	Role      string    `gorm:"default:'co_teacher'" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
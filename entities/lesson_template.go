@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// LessonTemplate holds reusable lesson content that admins instantiate into real lessons
+type LessonTemplate struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Name             string    `gorm:"not null;uniqueIndex" json:"name"`
+	Title            string    `gorm:"not null" json:"title"`
+	Description      string    `json:"description"`
+	SelfEnrollment   bool      `gorm:"default:false" json:"self_enrollment"`
+	RequiresApproval bool      `gorm:"default:false" json:"requires_approval"`
+	Prerequisites    []Lesson  `gorm:"many2many:lesson_template_prerequisites;joinReferences:PrerequisiteID" json:"prerequisites,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+const (
+	MaterialFile = "file"
+	MaterialLink = "link"
+)
+
+// Material is a file or link attached to a lesson, optionally inside a section.
+// Students only see it from ReleaseAt on; without a release date it is visible at once
+type Material struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	LessonID    uint       `gorm:"not null;index" json:"lesson_id"`
+	SectionID   *uint      `gorm:"index" json:"section_id,omitempty"`
+	Kind        string     `gorm:"type:varchar(10);not null" json:"kind"`
+	Title       string     `gorm:"not null" json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url,omitempty"`
+	FileName    string     `json:"file_name,omitempty"`
+	ContentType string     `json:"content_type,omitempty"`
+	Size        int64      `json:"size,omitempty"`
+	Checksum    string     `json:"checksum,omitempty"`
+	StorageKey  string     `json:"-"`
+	Position    int        `gorm:"not null;default:0" json:"position"`
+	ReleaseAt   *time.Time `gorm:"index" json:"release_at,omitempty"`
+	CreatedBy   uint       `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// IsReleased reports whether students can see the material at the given time
+func (m *Material) IsReleased(at time.Time) bool {
+	return m.ReleaseAt == nil || !m.ReleaseAt.After(at)
+}
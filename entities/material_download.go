@@ -0,0 +1,12 @@
+package entities
+
+import "time"
+
+// MaterialDownload records a student opening a material, whether a file download or a followed link
+type MaterialDownload struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	LessonID     uint      `gorm:"not null;index" json:"lesson_id"`
+	MaterialID   uint      `gorm:"not null;index:idx_material_download_student" json:"material_id"`
+	StudentID    uint      `gorm:"not null;index:idx_material_download_student" json:"student_id"`
+	DownloadedAt time.Time `gorm:"not null" json:"downloaded_at"`
+}
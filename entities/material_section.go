@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// MaterialSection groups a lesson's materials under a heading, in the order teachers choose
+type MaterialSection struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	LessonID  uint       `gorm:"not null;index" json:"lesson_id"`
+	Title     string     `gorm:"not null" json:"title"`
+	Position  int        `gorm:"not null;default:0" json:"position"`
+	Materials []Material `gorm:"foreignKey:SectionID" json:"materials"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
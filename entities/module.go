@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+// Module is an ordered unit of a course
+type Module struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CourseID  uint           `gorm:"not null;index" json:"course_id"`
+	Title     string         `gorm:"not null" json:"title"`
+	Position  int            `gorm:"not null;default:0" json:"position"`
+	Lessons   []ModuleLesson `gorm:"foreignKey:ModuleID" json:"lessons,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,9 @@
+package entities
+
+// ModuleLesson places a lesson inside a module; a lesson belongs to at most one module
+type ModuleLesson struct {
+	ModuleID uint    `gorm:"primaryKey" json:"module_id"`
+	LessonID uint    `gorm:"primaryKey;uniqueIndex" json:"lesson_id"`
+	Lesson   *Lesson `gorm:"foreignKey:LessonID" json:"lesson,omitempty"`
+	Position int     `gorm:"not null;default:0" json:"position"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// PrerequisiteOverride records an admin enrolling a student despite unmet prerequisites
+type PrerequisiteOverride struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	LessonID  uint      `gorm:"not null;index" json:"lesson_id"`
+	StudentID uint      `gorm:"not null;index" json:"student_id"`
+	AdminID   uint      `gorm:"not null" json:"admin_id"`
+	Reason    string    `gorm:"not null" json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
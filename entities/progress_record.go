@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// Kinds of progress a student makes through a lesson's content
+const (
+	ProgressMaterialViewed      = "material_viewed"
+	ProgressQuizPassed          = "quiz_passed"
+	ProgressAssignmentSubmitted = "assignment_submitted"
+)
+
+// ProgressRecord notes the first time a student viewed a material, passed a quiz or submitted
+// an assignment. ItemID is the ID of that material, quiz or assignment
+type ProgressRecord struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	LessonID   uint      `gorm:"not null;index" json:"lesson_id"`
+	StudentID  uint      `gorm:"not null;uniqueIndex:idx_progress_item" json:"student_id"`
+	Kind       string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_progress_item" json:"kind"`
+	ItemID     uint      `gorm:"not null;uniqueIndex:idx_progress_item" json:"item_id"`
+	RecordedAt time.Time `gorm:"not null" json:"recorded_at"`
+}
@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+// Score policies decide which attempt counts when a student takes a quiz more than once
+const (
+	QuizScoreBest   = "best"
+	QuizScoreLatest = "latest"
+)
+
+// Quiz is a short, automatically scored test in a lesson. Students only see published quizzes
+// and can start attempts between OpensAt and ClosesAt when those are set. With a grade item
+// linked, each student's counted score is written into the gradebook. An attempt scoring at
+// least PassPercent of the points passes the quiz
+type Quiz struct {
+	ID               uint           `gorm:"primaryKey" json:"id"`
+	LessonID         uint           `gorm:"not null;index" json:"lesson_id"`
+	Title            string         `gorm:"not null" json:"title"`
+	Description      string         `json:"description"`
+	GradeItemID      *uint          `gorm:"index" json:"grade_item_id,omitempty"`
+	TimeLimitMinutes int            `gorm:"not null;default:0" json:"time_limit_minutes"`
+	MaxAttempts      int            `gorm:"not null;default:0" json:"max_attempts"`
+	ShuffleQuestions bool           `gorm:"default:false" json:"shuffle_questions"`
+	ScorePolicy      string         `gorm:"type:varchar(10);not null;default:'best'" json:"score_policy"`
+	PassPercent      float64        `gorm:"not null;default:0" json:"pass_percent"`
+	Published        bool           `gorm:"default:false" json:"published"`
+	OpensAt          *time.Time     `json:"opens_at,omitempty"`
+	ClosesAt         *time.Time     `json:"closes_at,omitempty"`
+	Questions        []QuizQuestion `gorm:"foreignKey:QuizID" json:"questions,omitempty"`
+	CreatedBy        uint           `json:"created_by"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// IsOpen reports whether students can start an attempt at the given time
+func (q *Quiz) IsOpen(at time.Time) bool {
+	if !q.Published {
+		return false
+	}
+	if q.OpensAt != nil && at.Before(*q.OpensAt) {
+		return false
+	}
+	return q.ClosesAt == nil || at.Before(*q.ClosesAt)
+}
+
+// Passes reports whether the attempt scored enough to pass the quiz
+func (q *Quiz) Passes(attempt *QuizAttempt) bool {
+	if !attempt.IsSubmitted() || attempt.MaxScore <= 0 {
+		return false
+	}
+	return attempt.Score*100 >= q.PassPercent*attempt.MaxScore
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+// QuizAttempt is one go at a quiz by a student. It is scored when submitted, or when its
+// deadline passes without a submission. Seed fixes the question order of a shuffled quiz
+type QuizAttempt struct {
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	LessonID    uint           `gorm:"not null;index" json:"lesson_id"`
+	QuizID      uint           `gorm:"not null;uniqueIndex:idx_quiz_attempt" json:"quiz_id"`
+	StudentID   uint           `gorm:"not null;uniqueIndex:idx_quiz_attempt;index" json:"student_id"`
+	Number      int            `gorm:"not null;uniqueIndex:idx_quiz_attempt" json:"number"`
+	Seed        int64          `json:"-"`
+	StartedAt   time.Time      `gorm:"not null" json:"started_at"`
+	DeadlineAt  *time.Time     `json:"deadline_at,omitempty"`
+	SubmittedAt *time.Time     `json:"submitted_at,omitempty"`
+	Score       float64        `json:"score"`
+	MaxScore    float64        `json:"max_score"`
+	Responses   []QuizResponse `gorm:"foreignKey:AttemptID" json:"responses"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// IsSubmitted reports whether the attempt has been scored
+func (a *QuizAttempt) IsSubmitted() bool {
+	return a.SubmittedAt != nil
+}
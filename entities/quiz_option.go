@@ -0,0 +1,11 @@
+package entities
+
+// QuizOption is a choice offered by a choice question, or an accepted answer of a short text question
+type QuizOption struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	LessonID   uint   `gorm:"not null;index" json:"lesson_id"`
+	QuestionID uint   `gorm:"not null;index" json:"question_id"`
+	Text       string `gorm:"not null" json:"text"`
+	Correct    bool   `gorm:"default:false" json:"correct"`
+	Position   int    `gorm:"not null;default:0" json:"position"`
+}
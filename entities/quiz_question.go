@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// Question kinds
+const (
+	QuestionSingleChoice   = "single_choice"
+	QuestionMultipleChoice = "multiple_choice"
+	QuestionNumeric        = "numeric"
+	QuestionShortText      = "short_text"
+)
+
+// QuizQuestion is one question of a quiz. Choice questions mark their correct options; a
+// numeric question accepts answers within Tolerance of Answer; a short text question lists
+// its accepted answers as options
+type QuizQuestion struct {
+	ID            uint         `gorm:"primaryKey" json:"id"`
+	LessonID      uint         `gorm:"not null;index" json:"lesson_id"`
+	QuizID        uint         `gorm:"not null;index" json:"quiz_id"`
+	Kind          string       `gorm:"type:varchar(20);not null" json:"kind"`
+	Prompt        string       `gorm:"not null" json:"prompt"`
+	Points        float64      `gorm:"not null" json:"points"`
+	Position      int          `gorm:"not null;default:0" json:"position"`
+	Answer        *float64     `json:"answer,omitempty"`
+	Tolerance     float64      `gorm:"not null;default:0" json:"tolerance"`
+	CaseSensitive bool         `gorm:"default:false" json:"case_sensitive"`
+	Options       []QuizOption `gorm:"foreignKey:QuestionID" json:"options"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import "time"
+
+// QuizResponse is a student's answer to one question of an attempt. Correct and Points are
+// set when the attempt is scored; teachers may adjust the points while reviewing
+type QuizResponse struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	LessonID   uint      `gorm:"not null;index" json:"lesson_id"`
+	AttemptID  uint      `gorm:"not null;uniqueIndex:idx_response_question" json:"attempt_id"`
+	QuestionID uint      `gorm:"not null;uniqueIndex:idx_response_question" json:"question_id"`
+	OptionIDs  []uint    `gorm:"serializer:json" json:"option_ids,omitempty"`
+	Number     *float64  `json:"number,omitempty"`
+	Text       string    `json:"text,omitempty"`
+	Correct    bool      `gorm:"default:false" json:"correct"`
+	Points     float64   `json:"points"`
+	Feedback   string    `json:"feedback,omitempty"`
+	ReviewedBy *uint     `json:"reviewed_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
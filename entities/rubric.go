@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// Rubric is a reusable marking guide that teachers attach to assignments. Each criterion is
+// scored by picking one of its levels
+type Rubric struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	Name        string            `gorm:"not null" json:"name"`
+	Description string            `json:"description"`
+	Criteria    []RubricCriterion `gorm:"foreignKey:RubricID" json:"criteria"`
+	CreatedBy   uint              `gorm:"not null;index" json:"created_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// MaxPoints is the total of the best level of every criterion
+func (r *Rubric) MaxPoints() float64 {
+	var total float64
+	for _, criterion := range r.Criteria {
+		total += criterion.MaxPoints()
+	}
+	return total
+}
@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+// RubricAssessment is a teacher's rubric marking of one student's assignment. Total is the sum
+// of the criterion scores; Points is what counts after the late penalty of a late submission
+type RubricAssessment struct {
+	ID                 uint                   `gorm:"primaryKey" json:"id"`
+	LessonID           uint                   `gorm:"not null;index" json:"lesson_id"`
+	AssignmentID       uint                   `gorm:"not null;uniqueIndex:idx_rubric_assessment" json:"assignment_id"`
+	StudentID          uint                   `gorm:"not null;uniqueIndex:idx_rubric_assessment;index" json:"student_id"`
+	RubricID           uint                   `gorm:"not null;index" json:"rubric_id"`
+	SubmissionID       *uint                  `json:"submission_id,omitempty"`
+	Late               bool                   `gorm:"default:false" json:"late"`
+	Comment            string                 `json:"comment,omitempty"`
+	Total              float64                `json:"total"`
+	MaxPoints          float64                `json:"max_points"`
+	LatePenaltyPercent float64                `json:"late_penalty_percent"`
+	Points             float64                `json:"points"`
+	Scores             []RubricCriterionScore `gorm:"foreignKey:AssessmentID" json:"scores"`
+	GradedBy           uint                   `json:"graded_by"`
+	CreatedAt          time.Time              `json:"created_at"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package entities
+
+// RubricCriterion is one aspect a rubric marks, such as structure or use of sources
+type RubricCriterion struct {
+	ID          uint          `gorm:"primaryKey" json:"id"`
+	RubricID    uint          `gorm:"not null;index" json:"rubric_id"`
+	Title       string        `gorm:"not null" json:"title"`
+	Description string        `json:"description"`
+	Position    int           `gorm:"not null;default:0" json:"position"`
+	Levels      []RubricLevel `gorm:"foreignKey:CriterionID" json:"levels"`
+}
+
+// MaxPoints is the points of the criterion's best level
+func (c *RubricCriterion) MaxPoints() float64 {
+	var best float64
+	for _, level := range c.Levels {
+		if level.Points > best {
+			best = level.Points
+		}
+	}
+	return best
+}
@@ -0,0 +1,12 @@
+package entities
+
+// RubricCriterionScore is the level and points given for one criterion of an assessment
+type RubricCriterionScore struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	LessonID     uint    `gorm:"not null;index" json:"lesson_id"`
+	AssessmentID uint    `gorm:"not null;index" json:"assessment_id"`
+	CriterionID  uint    `gorm:"not null" json:"criterion_id"`
+	LevelID      *uint   `json:"level_id,omitempty"`
+	Points       float64 `json:"points"`
+	Comment      string  `json:"comment,omitempty"`
+}
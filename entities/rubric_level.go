@@ -0,0 +1,11 @@
+package entities
+
+// RubricLevel is one step of a criterion's scale, such as "Proficient", worth a number of points
+type RubricLevel struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	CriterionID uint    `gorm:"not null;index" json:"criterion_id"`
+	Title       string  `gorm:"not null" json:"title"`
+	Description string  `json:"description"`
+	Points      float64 `gorm:"not null" json:"points"`
+	Position    int     `gorm:"not null;default:0" json:"position"`
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// Subject is a node in the subject taxonomy; root subjects have no parent
+type Subject struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ParentID  *uint     `gorm:"uniqueIndex:idx_subject_parent_name" json:"parent_id,omitempty"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_subject_parent_name" json:"name"`
+	Children  []Subject `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
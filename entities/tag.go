@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"not null;uniqueIndex" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NormalizeTagName trims and lowercases a tag so "Algebra " and "algebra" are the same tag
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
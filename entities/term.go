@@ -0,0 +1,14 @@
+package entities
+
+import "time"
+
+type Term struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	AcademicYearID uint      `gorm:"not null;index" json:"academic_year_id"`
+	Name           string    `gorm:"not null" json:"name"`
+	StartsOn       time.Time `gorm:"not null;index" json:"starts_on"`
+	EndsOn         time.Time `gorm:"not null;index" json:"ends_on"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
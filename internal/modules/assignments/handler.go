@@ -0,0 +1,680 @@
+package assignments
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"lesson-management/pkg/storage"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+// multipartOverheadBytes allows for the boundaries and headers around an uploaded file
+const multipartOverheadBytes = 64 << 10
+
+type AssignmentHandler struct {
+	service        IAssignmentService
+	maxUploadBytes int64
+}
+
+func NewAssignmentHandler(service IAssignmentService, maxUploadBytes int64) *AssignmentHandler {
+	return &AssignmentHandler{
+		service:        service,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// Teacher handlers
+func (h *AssignmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	assignments, err := h.service.GetAssignments(lessonID, teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch assignments", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching assignments: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assignments)
+}
+
+func (h *AssignmentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	assignment, err := h.service.GetAssignment(lessonID, assignmentID, teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch assignment", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching assignment: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assignment)
+}
+
+func (h *AssignmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.CreateAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.service.CreateAssignment(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create assignment", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating assignment: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(assignment)
+}
+
+func (h *AssignmentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.PatchAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.service.UpdateAssignment(lessonID, assignmentID, teacherID, &requestBody)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update assignment", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating assignment: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assignment)
+}
+
+func (h *AssignmentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteAssignment(lessonID, assignmentID, teacherID); err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete assignment", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting assignment: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSubmissions returns each enrolled student's submission status, optionally
+// filtered with ?status=pending|missing|submitted|late
+func (h *AssignmentHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	statuses, err := h.service.GetSubmissionStatuses(lessonID, assignmentID, teacherID, r.URL.Query().Get("status"))
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch submission statuses", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching submission statuses: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(statuses)
+}
+
+func (h *AssignmentHandler) GetStudentHistory(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	studentID, ok := pathID(w, r, "studentID", "Invalid student ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	submissions, err := h.service.GetStudentSubmissionHistory(lessonID, assignmentID, uint(studentID), teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch submission history", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching submission history: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(submissions)
+}
+
+func (h *AssignmentHandler) DownloadSubmission(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	submissionID, ok := pathID(w, r, "submissionID", "Invalid submission ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	submission, file, err := h.service.OpenSubmission(lessonID, assignmentID, submissionID, teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to open submission", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while opening submission: ", err)
+		return
+	}
+	defer file.Close()
+
+	storage.Serve(w, r, file, submission.FileName, submission.ContentType, submission.SubmittedAt, submission.Checksum)
+}
+
+func (h *AssignmentHandler) GetSubmissionURL(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	submissionID, ok := pathID(w, r, "submissionID", "Invalid submission ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	link, err := h.service.GetSubmissionURL(lessonID, assignmentID, submissionID, teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to sign submission URL", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while signing submission URL: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(link)
+}
+
+// ListRubricAssessments returns the rubric assessments of every marked student
+func (h *AssignmentHandler) ListRubricAssessments(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	assessments, err := h.service.GetRubricAssessments(lessonID, assignmentID, teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch rubric assessments", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching rubric assessments: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assessments)
+}
+
+func (h *AssignmentHandler) GetStudentRubric(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	studentID, ok := pathID(w, r, "studentID", "Invalid student ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.service.GetRubricResult(lessonID, assignmentID, uint(studentID), teacherID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch rubric result", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching rubric result: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *AssignmentHandler) AssessStudent(w http.ResponseWriter, r *http.Request) {
+	lessonID, assignmentID, ok := assignmentIDs(w, r)
+	if !ok {
+		return
+	}
+
+	studentID, ok := pathID(w, r, "studentID", "Invalid student ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.RubricAssessmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.AssessWithRubric(lessonID, assignmentID, uint(studentID), teacherID, &requestBody)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to save rubric assessment", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while saving rubric assessment: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// Student handlers
+func (h *AssignmentHandler) ListForStudent(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	assignments, err := h.service.GetStudentAssignments(lessonID, studentID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch student assignments", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student assignments: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(assignments)
+}
+
+// Submit accepts the work as the "file" field of a multipart upload. The file is streamed
+// straight to storage rather than buffered on the server
+func (h *AssignmentHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	assignmentID, ok := pathID(w, r, "assignmentID", "Invalid assignment ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadBytes+multipartOverheadBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Content-Type must be multipart/form-data", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	part, err := filePart(reader)
+	if err != nil {
+		http.Error(w, "Multipart upload must include a file field", http.StatusBadRequest)
+		return
+	}
+	defer part.Close()
+
+	submission, err := h.service.Submit(assignmentID, studentID, part.FileName(), part)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to submit assignment", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while submitting assignment: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(submission)
+}
+
+func (h *AssignmentHandler) ListOwnSubmissions(w http.ResponseWriter, r *http.Request) {
+	assignmentID, ok := pathID(w, r, "assignmentID", "Invalid assignment ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	submissions, err := h.service.GetOwnSubmissions(assignmentID, studentID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch submissions", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching submissions: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(submissions)
+}
+
+func (h *AssignmentHandler) DownloadOwnSubmission(w http.ResponseWriter, r *http.Request) {
+	assignmentID, ok := pathID(w, r, "assignmentID", "Invalid assignment ID")
+	if !ok {
+		return
+	}
+
+	submissionID, ok := pathID(w, r, "submissionID", "Invalid submission ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	submission, file, err := h.service.OpenOwnSubmission(assignmentID, submissionID, studentID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to open submission", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while opening submission: ", err)
+		return
+	}
+	defer file.Close()
+
+	storage.Serve(w, r, file, submission.FileName, submission.ContentType, submission.SubmittedAt, submission.Checksum)
+}
+
+func (h *AssignmentHandler) GetOwnSubmissionURL(w http.ResponseWriter, r *http.Request) {
+	assignmentID, ok := pathID(w, r, "assignmentID", "Invalid assignment ID")
+	if !ok {
+		return
+	}
+
+	submissionID, ok := pathID(w, r, "submissionID", "Invalid submission ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	link, err := h.service.GetOwnSubmissionURL(assignmentID, submissionID, studentID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to sign submission URL", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while signing submission URL: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(link)
+}
+
+// GetOwnRubric shows the assignment's rubric with the student's scores once they are marked
+func (h *AssignmentHandler) GetOwnRubric(w http.ResponseWriter, r *http.Request) {
+	assignmentID, ok := pathID(w, r, "assignmentID", "Invalid assignment ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.service.GetOwnRubricResult(assignmentID, studentID)
+	if err != nil {
+		if status := assignmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch rubric result", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching rubric result: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+// filePart skips ahead to the "file" field of a multipart upload
+func filePart(reader *multipart.Reader) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" && part.FileName() != "" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// assignmentIDs parses the lesson and assignment IDs from the URL
+func assignmentIDs(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return 0, 0, false
+	}
+
+	assignmentID, ok := pathID(w, r, "assignmentID", "Invalid assignment ID")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return lessonID, assignmentID, true
+}
+
+// pathID parses a numeric route variable, answering 400 with message when it is invalid
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		http.Error(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// assignmentErrorStatus maps service errors to HTTP status codes
+func assignmentErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, storage.ErrFileTooLarge),
+		errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, ErrSubmittedFileMissing),
+		errors.Is(err, ErrNoRubric):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher),
+		errors.Is(err, lessons.ErrNotLessonStudent):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTitleRequired),
+		errors.Is(err, ErrDueDateRequired),
+		errors.Is(err, ErrInvalidLatePolicy),
+		errors.Is(err, ErrInvalidLateUntil),
+		errors.Is(err, ErrInvalidLatePenalty),
+		errors.Is(err, ErrInvalidStatus),
+		errors.Is(err, ErrStudentNotEnrolled),
+		errors.Is(err, ErrIncompleteScores),
+		errors.Is(err, ErrInvalidScore),
+		errors.Is(err, storage.ErrEmptyFile):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSubmissionClosed),
+		errors.Is(err, ErrAssignmentAssessed),
+		errors.Is(err, ErrGradeItemLinked),
+		errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
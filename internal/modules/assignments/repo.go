@@ -0,0 +1,213 @@
+package assignments
+
+import (
+	"errors"
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IAssignmentRepository interface {
+	GetAssignments(lessonID uint) ([]entities.Assignment, error)
+	GetAssignment(id uint) (entities.Assignment, error)
+	CreateAssignment(assignment *entities.Assignment) error
+	UpdateAssignment(assignment *entities.Assignment) error
+	DeleteAssignment(id uint) ([]string, error)
+	CreateSubmission(submission *entities.AssignmentSubmission) error
+	GetSubmissions(assignmentID uint, studentID *uint) ([]entities.AssignmentSubmission, error)
+	GetSubmission(id uint) (entities.AssignmentSubmission, error)
+	HasAssessments(assignmentID uint) (bool, error)
+	GradeItemLinked(itemID uint, assignmentID uint) (bool, error)
+	GetAssessments(assignmentID uint) ([]entities.RubricAssessment, error)
+	GetAssessment(assignmentID uint, studentID uint) (entities.RubricAssessment, error)
+	SaveAssessment(assessment *entities.RubricAssessment, grade *entities.Grade) error
+}
+
+type AssignmentRepository struct{}
+
+func NewAssignmentRepository() IAssignmentRepository {
+	return &AssignmentRepository{}
+}
+
+func (r *AssignmentRepository) GetAssignments(lessonID uint) ([]entities.Assignment, error) {
+	var assignments []entities.Assignment
+	result := common.DB.Where("lesson_id = ?", lessonID).Order("due_at, id").Find(&assignments)
+	return assignments, result.Error
+}
+
+func (r *AssignmentRepository) GetAssignment(id uint) (entities.Assignment, error) {
+	var assignment entities.Assignment
+	result := common.DB.First(&assignment, id)
+	return assignment, result.Error
+}
+
+func (r *AssignmentRepository) CreateAssignment(assignment *entities.Assignment) error {
+	return common.DB.Create(assignment).Error
+}
+
+func (r *AssignmentRepository) UpdateAssignment(assignment *entities.Assignment) error {
+	result := common.DB.Save(assignment)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteAssignment removes the assignment with its submissions and rubric scores, returning the storage keys
+// of the submitted files so the caller can delete them
+func (r *AssignmentRepository) DeleteAssignment(id uint) ([]string, error) {
+	var keys []string
+	err := common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.AssignmentSubmission{}).Where("assignment_id = ?", id).Pluck("storage_key", &keys).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("assignment_id = ?", id).Delete(&entities.AssignmentSubmission{}).Error; err != nil {
+			return err
+		}
+		assessments := tx.Model(&entities.RubricAssessment{}).Select("id").Where("assignment_id = ?", id)
+		if err := tx.Where("assessment_id IN (?)", assessments).Delete(&entities.RubricCriterionScore{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("assignment_id = ?", id).Delete(&entities.RubricAssessment{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entities.Assignment{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// CreateSubmission stores the submission as the student's next version. The assignment row
+// is locked so concurrent uploads from the same student get distinct versions
+func (r *AssignmentRepository) CreateSubmission(submission *entities.AssignmentSubmission) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var assignment entities.Assignment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&assignment, submission.AssignmentID).Error; err != nil {
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&entities.AssignmentSubmission{}).
+			Where("assignment_id = ? AND student_id = ?", submission.AssignmentID, submission.StudentID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		submission.Version = latest + 1
+		return tx.Create(submission).Error
+	})
+}
+
+// GetSubmissions lists the assignment's submissions, newest version first for each student
+func (r *AssignmentRepository) GetSubmissions(assignmentID uint, studentID *uint) ([]entities.AssignmentSubmission, error) {
+	query := common.DB.Where("assignment_id = ?", assignmentID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	}
+
+	var submissions []entities.AssignmentSubmission
+	result := query.Order("student_id, version DESC").Find(&submissions)
+	return submissions, result.Error
+}
+
+func (r *AssignmentRepository) GetSubmission(id uint) (entities.AssignmentSubmission, error) {
+	var submission entities.AssignmentSubmission
+	result := common.DB.First(&submission, id)
+	return submission, result.Error
+}
+
+func (r *AssignmentRepository) HasAssessments(assignmentID uint) (bool, error) {
+	var count int64
+	result := common.DB.Model(&entities.RubricAssessment{}).Where("assignment_id = ?", assignmentID).Count(&count)
+	return count > 0, result.Error
+}
+
+// GradeItemLinked reports whether an assignment other than assignmentID or any quiz already feeds the grade item
+func (r *AssignmentRepository) GradeItemLinked(itemID uint, assignmentID uint) (bool, error) {
+	var assignments int64
+	result := common.DB.Model(&entities.Assignment{}).Where("grade_item_id = ? AND id <> ?", itemID, assignmentID).Count(&assignments)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	var quizzes int64
+	result = common.DB.Model(&entities.Quiz{}).Where("grade_item_id = ?", itemID).Count(&quizzes)
+	return assignments+quizzes > 0, result.Error
+}
+
+func (r *AssignmentRepository) GetAssessments(assignmentID uint) ([]entities.RubricAssessment, error) {
+	var assessments []entities.RubricAssessment
+	result := common.DB.Preload("Scores").Where("assignment_id = ?", assignmentID).Order("student_id").Find(&assessments)
+	return assessments, result.Error
+}
+
+func (r *AssignmentRepository) GetAssessment(assignmentID uint, studentID uint) (entities.RubricAssessment, error) {
+	var assessment entities.RubricAssessment
+	result := common.DB.Preload("Scores").
+		Where("assignment_id = ? AND student_id = ?", assignmentID, studentID).
+		First(&assessment)
+	return assessment, result.Error
+}
+
+// SaveAssessment stores the student's assessment, replacing an earlier one with all of its
+// scores. The grade, when given, is written in the same transaction
+func (r *AssignmentRepository) SaveAssessment(assessment *entities.RubricAssessment, grade *entities.Grade) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var existing entities.RubricAssessment
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "created_at").
+			Where("assignment_id = ? AND student_id = ?", assessment.AssignmentID, assessment.StudentID).
+			First(&existing).Error
+		switch {
+		case err == nil:
+			assessment.ID = existing.ID
+			assessment.CreatedAt = existing.CreatedAt
+			if err := tx.Where("assessment_id = ?", existing.ID).Delete(&entities.RubricCriterionScore{}).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			assessment.ID = 0
+		default:
+			return err
+		}
+
+		if err := tx.Omit(clause.Associations).Save(assessment).Error; err != nil {
+			return err
+		}
+		for i := range assessment.Scores {
+			assessment.Scores[i].ID = 0
+			assessment.Scores[i].LessonID = assessment.LessonID
+			assessment.Scores[i].AssessmentID = assessment.ID
+		}
+		if len(assessment.Scores) > 0 {
+			if err := tx.Create(&assessment.Scores).Error; err != nil {
+				return err
+			}
+		}
+
+		if grade == nil {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "grade_item_id"}, {Name: "student_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"points", "feedback", "graded_by", "updated_at"}),
+		}).Create(grade).Error
+	})
+}
@@ -0,0 +1,42 @@
+package assignments
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *AssignmentHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/assignments").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.List).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("", handler.Create).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}", handler.Get).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/submissions", handler.ListSubmissions).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/submissions/{submissionID:[0-9]+}/file", handler.DownloadSubmission).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/submissions/{submissionID:[0-9]+}/url", handler.GetSubmissionURL).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/students/{studentID:[0-9]+}/submissions", handler.GetStudentHistory).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/rubric-assessments", handler.ListRubricAssessments).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/students/{studentID:[0-9]+}/rubric", handler.GetStudentRubric).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{assignmentID:[0-9]+}/students/{studentID:[0-9]+}/rubric", handler.AssessStudent).Methods(http.MethodPut)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/assignments", handler.ListForStudent).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/assignments/{assignmentID:[0-9]+}/submissions", handler.ListOwnSubmissions).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/assignments/{assignmentID:[0-9]+}/submissions", handler.Submit).Methods(http.MethodPost)
+	studentRoutes.HandleFunc("/assignments/{assignmentID:[0-9]+}/submissions/{submissionID:[0-9]+}/file", handler.DownloadOwnSubmission).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/assignments/{assignmentID:[0-9]+}/submissions/{submissionID:[0-9]+}/url", handler.GetOwnSubmissionURL).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/assignments/{assignmentID:[0-9]+}/rubric", handler.GetOwnRubric).Methods(http.MethodGet)
+}
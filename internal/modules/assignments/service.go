@@ -0,0 +1,836 @@
+package assignments
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/gradebook"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/progress"
+	"lesson-management/internal/modules/rubrics"
+	"lesson-management/models"
+	"lesson-management/pkg/storage"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Submission statuses in the teacher's overview
+const (
+	SubmissionPending   = "pending"
+	SubmissionMissing   = "missing"
+	SubmissionSubmitted = "submitted"
+	SubmissionLate      = "late"
+)
+
+var submissionStatuses = map[string]bool{
+	SubmissionPending:   true,
+	SubmissionMissing:   true,
+	SubmissionSubmitted: true,
+	SubmissionLate:      true,
+}
+
+var (
+	ErrTitleRequired        = errors.New("assignment title is required")
+	ErrDueDateRequired      = errors.New("due date is required")
+	ErrInvalidLatePolicy    = errors.New("late policy must be allow or deny")
+	ErrInvalidLateUntil     = errors.New("late submissions must close after the due date and only when late work is allowed")
+	ErrInvalidLatePenalty   = errors.New("late penalty must be between 0 and 100 percent")
+	ErrInvalidStatus        = errors.New("status must be pending, missing, submitted or late")
+	ErrSubmissionClosed     = errors.New("assignment no longer accepts submissions")
+	ErrSubmittedFileMissing = errors.New("submitted file is no longer available")
+	ErrNoRubric             = errors.New("assignment has no rubric")
+	ErrAssignmentAssessed   = errors.New("students have been marked with the assignment's rubric, so it can't be changed")
+	ErrStudentNotEnrolled   = errors.New("student is not enrolled in this lesson")
+	ErrIncompleteScores     = errors.New("every criterion of the rubric must be scored exactly once")
+	ErrInvalidScore         = errors.New("each score needs a level of its criterion or points between zero and the criterion's best level")
+	ErrGradeItemLinked      = errors.New("grade item is already linked to another assignment or quiz")
+)
+
+type IAssignmentService interface {
+	GetAssignments(lessonID uint64, teacherID uint) ([]entities.Assignment, error)
+	GetAssignment(lessonID uint64, assignmentID uint64, teacherID uint) (*entities.Assignment, error)
+	CreateAssignment(lessonID uint64, teacherID uint, request *models.CreateAssignmentRequest) (*entities.Assignment, error)
+	UpdateAssignment(lessonID uint64, assignmentID uint64, teacherID uint, request *models.PatchAssignmentRequest) (*entities.Assignment, error)
+	DeleteAssignment(lessonID uint64, assignmentID uint64, teacherID uint) error
+	GetSubmissionStatuses(lessonID uint64, assignmentID uint64, teacherID uint, status string) ([]models.SubmissionStatus, error)
+	GetStudentSubmissionHistory(lessonID uint64, assignmentID uint64, studentID uint, teacherID uint) ([]entities.AssignmentSubmission, error)
+	OpenSubmission(lessonID uint64, assignmentID uint64, submissionID uint64, teacherID uint) (*entities.AssignmentSubmission, storage.Object, error)
+	GetSubmissionURL(lessonID uint64, assignmentID uint64, submissionID uint64, teacherID uint) (*models.DownloadURLResponse, error)
+	GetStudentAssignments(lessonID uint64, studentID uint) ([]entities.Assignment, error)
+	Submit(assignmentID uint64, studentID uint, fileName string, content io.Reader) (*entities.AssignmentSubmission, error)
+	GetOwnSubmissions(assignmentID uint64, studentID uint) ([]entities.AssignmentSubmission, error)
+	OpenOwnSubmission(assignmentID uint64, submissionID uint64, studentID uint) (*entities.AssignmentSubmission, storage.Object, error)
+	GetOwnSubmissionURL(assignmentID uint64, submissionID uint64, studentID uint) (*models.DownloadURLResponse, error)
+	GetRubricAssessments(lessonID uint64, assignmentID uint64, teacherID uint) ([]entities.RubricAssessment, error)
+	GetRubricResult(lessonID uint64, assignmentID uint64, studentID uint, teacherID uint) (*models.RubricResult, error)
+	AssessWithRubric(lessonID uint64, assignmentID uint64, studentID uint, teacherID uint, request *models.RubricAssessmentRequest) (*models.RubricResult, error)
+	GetOwnRubricResult(assignmentID uint64, studentID uint) (*models.RubricResult, error)
+}
+
+type AssignmentService struct {
+	repo             IAssignmentRepository
+	lessonService    lessons.ILessonService
+	gradebookService gradebook.IGradebookService
+	rubricService    rubrics.IRubricService
+	progressService  progress.IProgressService
+	uploads          *storage.Uploads
+}
+
+func NewAssignmentService(repo IAssignmentRepository, lessonService lessons.ILessonService, gradebookService gradebook.IGradebookService, rubricService rubrics.IRubricService, progressService progress.IProgressService, uploads *storage.Uploads) IAssignmentService {
+	return &AssignmentService{
+		repo:             repo,
+		lessonService:    lessonService,
+		gradebookService: gradebookService,
+		rubricService:    rubricService,
+		progressService:  progressService,
+		uploads:          uploads,
+	}
+}
+
+func (s *AssignmentService) GetAssignments(lessonID uint64, teacherID uint) ([]entities.Assignment, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAssignments(lesson.ID)
+}
+
+func (s *AssignmentService) GetAssignment(lessonID uint64, assignmentID uint64, teacherID uint) (*entities.Assignment, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.lessonAssignment(lesson, assignmentID)
+}
+
+func (s *AssignmentService) CreateAssignment(lessonID uint64, teacherID uint, request *models.CreateAssignmentRequest) (*entities.Assignment, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment := &entities.Assignment{
+		LessonID:           lesson.ID,
+		Title:              strings.TrimSpace(request.Title),
+		Instructions:       request.Instructions,
+		DueAt:              request.DueAt,
+		LatePolicy:         request.LatePolicy,
+		LateUntil:          request.LateUntil,
+		LatePenaltyPercent: request.LatePenaltyPercent,
+		RubricID:           request.RubricID,
+		GradeItemID:        request.GradeItemID,
+		CreatedBy:          teacherID,
+	}
+	if assignment.LatePolicy == "" {
+		assignment.LatePolicy = entities.LatePolicyAllow
+	}
+	if err := validateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	if err := s.validateLinks(assignment); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+func (s *AssignmentService) UpdateAssignment(lessonID uint64, assignmentID uint64, teacherID uint, request *models.PatchAssignmentRequest) (*entities.Assignment, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Title != nil {
+		assignment.Title = strings.TrimSpace(*request.Title)
+	}
+	if request.Instructions != nil {
+		assignment.Instructions = *request.Instructions
+	}
+	if request.DueAt != nil {
+		assignment.DueAt = *request.DueAt
+	}
+	if request.LatePolicy != nil {
+		assignment.LatePolicy = *request.LatePolicy
+		if assignment.LatePolicy == entities.LatePolicyDeny {
+			assignment.LateUntil = nil
+		}
+	}
+	if request.LateUntil != nil {
+		assignment.LateUntil = request.LateUntil
+	}
+	if request.LatePenaltyPercent != nil {
+		assignment.LatePenaltyPercent = *request.LatePenaltyPercent
+	}
+	if request.RubricID != nil {
+		rubricID := request.RubricID
+		if *rubricID == 0 {
+			rubricID = nil
+		}
+		if !sameID(assignment.RubricID, rubricID) {
+			assessed, err := s.repo.HasAssessments(assignment.ID)
+			if err != nil {
+				return nil, err
+			}
+			if assessed {
+				return nil, ErrAssignmentAssessed
+			}
+		}
+		assignment.RubricID = rubricID
+	}
+	if request.GradeItemID != nil {
+		assignment.GradeItemID = request.GradeItemID
+		if *request.GradeItemID == 0 {
+			assignment.GradeItemID = nil
+		}
+	}
+	if err := validateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	if err := s.validateLinks(assignment); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateAssignment(assignment); err != nil {
+		return nil, err
+	}
+
+	if request.GradeItemID != nil || request.LatePenaltyPercent != nil {
+		if err := s.refreshAssessments(assignment); err != nil {
+			return nil, err
+		}
+	}
+
+	return assignment, nil
+}
+
+// DeleteAssignment removes the assignment with all submissions and their files
+func (s *AssignmentService) DeleteAssignment(lessonID uint64, assignmentID uint64, teacherID uint) error {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return err
+	}
+
+	keys, err := s.repo.DeleteAssignment(assignment.ID)
+	if err != nil {
+		return err
+	}
+
+	// The rows are gone either way, so a file that fails to delete is only logged
+	for _, key := range keys {
+		if err := s.uploads.Storage.Delete(key); err != nil {
+			fmt.Println("Error while deleting submitted file: ", err)
+		}
+	}
+
+	return nil
+}
+
+// GetSubmissionStatuses lists every enrolled student with their latest submission.
+// A non-empty status keeps only the students in that state
+func (s *AssignmentService) GetSubmissionStatuses(lessonID uint64, assignmentID uint64, teacherID uint, status string) ([]models.SubmissionStatus, error) {
+	if status != "" && !submissionStatuses[status] {
+		return nil, ErrInvalidStatus
+	}
+
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(lesson.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := s.repo.GetSubmissions(assignment.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[uint]*entities.AssignmentSubmission, len(submissions))
+	versions := make(map[uint]int, len(submissions))
+	for i := range submissions {
+		submission := &submissions[i]
+		// Submissions come newest version first per student
+		if _, ok := latest[submission.StudentID]; !ok {
+			latest[submission.StudentID] = submission
+		}
+		versions[submission.StudentID]++
+	}
+
+	now := time.Now()
+	statuses := make([]models.SubmissionStatus, 0, len(students))
+	for _, student := range students {
+		entry := models.SubmissionStatus{
+			StudentID: student.ID,
+			Name:      student.Name,
+			Versions:  versions[student.ID],
+			Latest:    latest[student.ID],
+		}
+
+		switch {
+		case entry.Latest == nil && assignment.IsLate(now):
+			entry.Status = SubmissionMissing
+		case entry.Latest == nil:
+			entry.Status = SubmissionPending
+		case entry.Latest.Late:
+			entry.Status = SubmissionLate
+		default:
+			entry.Status = SubmissionSubmitted
+		}
+
+		if status == "" || entry.Status == status {
+			statuses = append(statuses, entry)
+		}
+	}
+
+	return statuses, nil
+}
+
+// GetStudentSubmissionHistory returns every version a student submitted, newest first
+func (s *AssignmentService) GetStudentSubmissionHistory(lessonID uint64, assignmentID uint64, studentID uint, teacherID uint) ([]entities.AssignmentSubmission, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSubmissions(assignment.ID, &studentID)
+}
+
+func (s *AssignmentService) OpenSubmission(lessonID uint64, assignmentID uint64, submissionID uint64, teacherID uint) (*entities.AssignmentSubmission, storage.Object, error) {
+	submission, err := s.teacherSubmission(lessonID, assignmentID, submissionID, teacherID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.openFile(submission)
+}
+
+// GetSubmissionURL returns a short-lived link to the file that works without logging in
+func (s *AssignmentService) GetSubmissionURL(lessonID uint64, assignmentID uint64, submissionID uint64, teacherID uint) (*models.DownloadURLResponse, error) {
+	submission, err := s.teacherSubmission(lessonID, assignmentID, submissionID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signURL(submission), nil
+}
+
+// GetStudentAssignments lists the assignments of a lesson the student is enrolled in
+func (s *AssignmentService) GetStudentAssignments(lessonID uint64, studentID uint) ([]entities.Assignment, error) {
+	lesson, err := s.lessonService.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(lesson.ID), studentID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAssignments(lesson.ID)
+}
+
+// Submit stores the file and records it as the student's next version. Late work is
+// accepted or refused according to the assignment's late policy
+func (s *AssignmentService) Submit(assignmentID uint64, studentID uint, fileName string, content io.Reader) (*entities.AssignmentSubmission, error) {
+	assignment, err := s.repo.GetAssignment(uint(assignmentID))
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.lessonService.GetLesson(uint64(assignment.LessonID))
+	if err != nil {
+		return nil, err
+	}
+	if lesson.Status == entities.LessonArchived {
+		return nil, lessons.ErrLessonArchived
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(lesson.ID), studentID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !assignment.AcceptsSubmission(now) {
+		return nil, ErrSubmissionClosed
+	}
+
+	key, err := submissionKey(assignment.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := storage.Save(s.uploads.Storage, key, content, s.uploads.MaxUploadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	submission := &entities.AssignmentSubmission{
+		LessonID:     assignment.LessonID,
+		AssignmentID: assignment.ID,
+		StudentID:    studentID,
+		FileName:     storage.CleanFileName(fileName, "submission"),
+		ContentType:  upload.ContentType,
+		Size:         upload.Size,
+		Checksum:     upload.Checksum,
+		StorageKey:   upload.Key,
+		Late:         assignment.IsLate(now),
+		SubmittedAt:  now,
+	}
+	if err := s.repo.CreateSubmission(submission); err != nil {
+		s.discardFile(key)
+		return nil, err
+	}
+
+	if err := s.progressService.Record(assignment.LessonID, studentID, entities.ProgressAssignmentSubmitted, assignment.ID); err != nil {
+		fmt.Println("Error while recording progress: ", err)
+	}
+
+	return submission, nil
+}
+
+// GetOwnSubmissions returns the student's versions of an assignment, newest first
+func (s *AssignmentService) GetOwnSubmissions(assignmentID uint64, studentID uint) ([]entities.AssignmentSubmission, error) {
+	assignment, err := s.studentAssignment(assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSubmissions(assignment.ID, &studentID)
+}
+
+func (s *AssignmentService) OpenOwnSubmission(assignmentID uint64, submissionID uint64, studentID uint) (*entities.AssignmentSubmission, storage.Object, error) {
+	submission, err := s.ownSubmission(assignmentID, submissionID, studentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s.openFile(submission)
+}
+
+func (s *AssignmentService) GetOwnSubmissionURL(assignmentID uint64, submissionID uint64, studentID uint) (*models.DownloadURLResponse, error) {
+	submission, err := s.ownSubmission(assignmentID, submissionID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.signURL(submission), nil
+}
+
+// GetRubricAssessments lists the rubric assessments of every marked student
+func (s *AssignmentService) GetRubricAssessments(lessonID uint64, assignmentID uint64, teacherID uint) ([]entities.RubricAssessment, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetAssessments(assignment.ID)
+}
+
+func (s *AssignmentService) GetRubricResult(lessonID uint64, assignmentID uint64, studentID uint, teacherID uint) (*models.RubricResult, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rubricResult(assignment, studentID)
+}
+
+// AssessWithRubric marks a student's work against the assignment's rubric, replacing any earlier
+// marking. A late latest submission loses the assignment's late penalty, and with a grade item
+// linked the result is written into the gradebook scaled to the item's max points
+func (s *AssignmentService) AssessWithRubric(lessonID uint64, assignmentID uint64, studentID uint, teacherID uint, request *models.RubricAssessmentRequest) (*models.RubricResult, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment.RubricID == nil {
+		return nil, ErrNoRubric
+	}
+
+	enrolled, err := s.lessonService.IsStudentEnrolled(uint64(lesson.ID), studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, ErrStudentNotEnrolled
+	}
+
+	rubric, err := s.rubricService.GetRubric(uint64(*assignment.RubricID))
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := rubricScores(rubric, request.Scores)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment := &entities.RubricAssessment{
+		LessonID:     assignment.LessonID,
+		AssignmentID: assignment.ID,
+		StudentID:    studentID,
+		RubricID:     rubric.ID,
+		Comment:      strings.TrimSpace(request.Comment),
+		MaxPoints:    rubric.MaxPoints(),
+		Scores:       scores,
+		GradedBy:     teacherID,
+	}
+	for _, score := range scores {
+		assessment.Total += score.Points
+	}
+	assessment.Total = gradebook.RoundPoints(assessment.Total)
+
+	// The latest version is the one being marked
+	submissions, err := s.repo.GetSubmissions(assignment.ID, &studentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(submissions) > 0 {
+		assessment.SubmissionID = &submissions[0].ID
+		assessment.Late = submissions[0].Late
+	}
+
+	if err := s.saveAssessment(assignment, assessment); err != nil {
+		return nil, err
+	}
+
+	return &models.RubricResult{
+		StudentID:  studentID,
+		Rubric:     rubric,
+		Assessment: assessment,
+	}, nil
+}
+
+// GetOwnRubricResult shows the student the assignment's rubric and, once they are marked, their scores
+func (s *AssignmentService) GetOwnRubricResult(assignmentID uint64, studentID uint) (*models.RubricResult, error) {
+	assignment, err := s.studentAssignment(assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.rubricResult(assignment, studentID)
+}
+
+func (s *AssignmentService) rubricResult(assignment *entities.Assignment, studentID uint) (*models.RubricResult, error) {
+	if assignment.RubricID == nil {
+		return nil, ErrNoRubric
+	}
+
+	rubric, err := s.rubricService.GetRubric(uint64(*assignment.RubricID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RubricResult{
+		StudentID: studentID,
+		Rubric:    rubric,
+	}
+
+	assessment, err := s.repo.GetAssessment(assignment.ID, studentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Assessment = &assessment
+
+	return result, nil
+}
+
+// refreshAssessments recomputes the late penalty and gradebook entry of every marked student
+// after the assignment's penalty or grade item changed
+func (s *AssignmentService) refreshAssessments(assignment *entities.Assignment) error {
+	assessments, err := s.repo.GetAssessments(assignment.ID)
+	if err != nil {
+		return err
+	}
+
+	for i := range assessments {
+		if err := s.saveAssessment(assignment, &assessments[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveAssessment applies the late penalty and stores the assessment with its grade
+func (s *AssignmentService) saveAssessment(assignment *entities.Assignment, assessment *entities.RubricAssessment) error {
+	assessment.LatePenaltyPercent = 0
+	if assessment.Late {
+		assessment.LatePenaltyPercent = assignment.LatePenaltyPercent
+	}
+	assessment.Points = gradebook.RoundPoints(assessment.Total * (100 - assessment.LatePenaltyPercent) / 100)
+
+	var grade *entities.Grade
+	if assignment.GradeItemID != nil && assessment.MaxPoints > 0 {
+		item, err := s.gradebookService.GetLessonItem(assignment.LessonID, *assignment.GradeItemID)
+		if err != nil {
+			return err
+		}
+		grade = &entities.Grade{
+			LessonID:    assignment.LessonID,
+			GradeItemID: item.ID,
+			StudentID:   assessment.StudentID,
+			Points:      gradebook.RoundPoints(assessment.Points / assessment.MaxPoints * item.MaxPoints),
+			Feedback:    assessment.Comment,
+			GradedBy:    assessment.GradedBy,
+		}
+	}
+
+	return s.repo.SaveAssessment(assessment, grade)
+}
+
+// validateLinks checks that an attached rubric exists and a linked grade item belongs to the same lesson and feeds nothing else
+func (s *AssignmentService) validateLinks(assignment *entities.Assignment) error {
+	if assignment.RubricID != nil {
+		if _, err := s.rubricService.GetRubric(uint64(*assignment.RubricID)); err != nil {
+			return err
+		}
+	}
+
+	if assignment.GradeItemID != nil {
+		if _, err := s.gradebookService.GetLessonItem(assignment.LessonID, *assignment.GradeItemID); err != nil {
+			return err
+		}
+
+		linked, err := s.repo.GradeItemLinked(*assignment.GradeItemID, assignment.ID)
+		if err != nil {
+			return err
+		}
+		if linked {
+			return ErrGradeItemLinked
+		}
+	}
+
+	return nil
+}
+
+// teacherSubmission loads a submission to an assignment of one of the teacher's lessons
+func (s *AssignmentService) teacherSubmission(lessonID uint64, assignmentID uint64, submissionID uint64, teacherID uint) (*entities.AssignmentSubmission, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.lessonAssignment(lesson, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := s.repo.GetSubmission(uint(submissionID))
+	if err != nil {
+		return nil, err
+	}
+	if submission.AssignmentID != assignment.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &submission, nil
+}
+
+// ownSubmission loads one of the student's own submissions
+func (s *AssignmentService) ownSubmission(assignmentID uint64, submissionID uint64, studentID uint) (*entities.AssignmentSubmission, error) {
+	assignment, err := s.studentAssignment(assignmentID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := s.repo.GetSubmission(uint(submissionID))
+	if err != nil {
+		return nil, err
+	}
+	if submission.AssignmentID != assignment.ID || submission.StudentID != studentID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &submission, nil
+}
+
+func (s *AssignmentService) signURL(submission *entities.AssignmentSubmission) *models.DownloadURLResponse {
+	expiresAt := time.Now().Add(storage.DownloadURLTTL).Truncate(time.Second)
+	return &models.DownloadURLResponse{
+		URL: s.uploads.Signer.Sign(storage.SignedFile{
+			Key:         submission.StorageKey,
+			FileName:    submission.FileName,
+			ContentType: submission.ContentType,
+			ExpiresAt:   expiresAt,
+		}),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func (s *AssignmentService) openFile(submission *entities.AssignmentSubmission) (*entities.AssignmentSubmission, storage.Object, error) {
+	file, err := s.uploads.Storage.Open(submission.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrSubmittedFileMissing
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return submission, file, nil
+}
+
+// discardFile removes a file whose submission could not be recorded
+func (s *AssignmentService) discardFile(key string) {
+	if err := s.uploads.Storage.Delete(key); err != nil {
+		fmt.Println("Error while discarding submitted file: ", err)
+	}
+}
+
+// studentAssignment loads an assignment from a lesson the student is enrolled in
+func (s *AssignmentService) studentAssignment(assignmentID uint64, studentID uint) (*entities.Assignment, error) {
+	assignment, err := s.repo.GetAssignment(uint(assignmentID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(assignment.LessonID), studentID); err != nil {
+		return nil, err
+	}
+
+	return &assignment, nil
+}
+
+// lessonAssignment loads an assignment, treating one from another lesson as not found
+func (s *AssignmentService) lessonAssignment(lesson *entities.Lesson, assignmentID uint64) (*entities.Assignment, error) {
+	assignment, err := s.repo.GetAssignment(uint(assignmentID))
+	if err != nil {
+		return nil, err
+	}
+
+	if assignment.LessonID != lesson.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &assignment, nil
+}
+
+func validateAssignment(assignment *entities.Assignment) error {
+	if assignment.Title == "" {
+		return ErrTitleRequired
+	}
+	if assignment.DueAt.IsZero() {
+		return ErrDueDateRequired
+	}
+	if assignment.LatePolicy != entities.LatePolicyAllow && assignment.LatePolicy != entities.LatePolicyDeny {
+		return ErrInvalidLatePolicy
+	}
+	if assignment.LateUntil != nil && (assignment.LatePolicy == entities.LatePolicyDeny || !assignment.LateUntil.After(assignment.DueAt)) {
+		return ErrInvalidLateUntil
+	}
+	if assignment.LatePenaltyPercent < 0 || assignment.LatePenaltyPercent > 100 {
+		return ErrInvalidLatePenalty
+	}
+	return nil
+}
+
+// submissionKey names a new file under the assignment and student. The random part keeps
+// keys unguessable and lets the file be stored before its version number is known
+func submissionKey(assignmentID uint, studentID uint) (string, error) {
+	return storage.RandomKey(fmt.Sprintf("assignments/%d/%d", assignmentID, studentID))
+}
+
+// rubricScores checks that every criterion of the rubric is scored once and turns the request
+// into scores. A level gives its points unless points are given explicitly
+func rubricScores(rubric *entities.Rubric, requests []models.RubricScoreRequest) ([]entities.RubricCriterionScore, error) {
+	if len(requests) != len(rubric.Criteria) {
+		return nil, ErrIncompleteScores
+	}
+
+	criteria := make(map[uint]*entities.RubricCriterion, len(rubric.Criteria))
+	for i := range rubric.Criteria {
+		criteria[rubric.Criteria[i].ID] = &rubric.Criteria[i]
+	}
+
+	scores := make([]entities.RubricCriterionScore, 0, len(requests))
+	for _, request := range requests {
+		criterion, ok := criteria[request.CriterionID]
+		if !ok {
+			return nil, ErrIncompleteScores
+		}
+		delete(criteria, request.CriterionID)
+
+		score := entities.RubricCriterionScore{
+			CriterionID: criterion.ID,
+			LevelID:     request.LevelID,
+			Comment:     strings.TrimSpace(request.Comment),
+		}
+		if request.LevelID != nil {
+			found := false
+			for _, level := range criterion.Levels {
+				if level.ID == *request.LevelID {
+					score.Points = level.Points
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, ErrInvalidScore
+			}
+		}
+		if request.Points != nil {
+			score.Points = *request.Points
+		} else if request.LevelID == nil {
+			return nil, ErrInvalidScore
+		}
+		if score.Points < 0 || score.Points > criterion.MaxPoints() {
+			return nil, ErrInvalidScore
+		}
+
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
+
+func sameID(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
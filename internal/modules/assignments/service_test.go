@@ -0,0 +1,46 @@
+package assignments
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/gradebook"
+	"testing"
+)
+
+type linkRepo struct {
+	IAssignmentRepository
+	linked bool
+}
+
+func (r *linkRepo) GradeItemLinked(itemID uint, assignmentID uint) (bool, error) {
+	return r.linked, nil
+}
+
+type lessonItems struct {
+	gradebook.IGradebookService
+}
+
+func (g *lessonItems) GetLessonItem(lessonID uint, itemID uint) (*entities.GradeItem, error) {
+	return &entities.GradeItem{ID: itemID, LessonID: lessonID}, nil
+}
+
+func TestValidateLinksGradeItem(t *testing.T) {
+	itemID := uint(7)
+	assignment := entities.Assignment{LessonID: 1, Title: "Essay", GradeItemID: &itemID}
+
+	for _, test := range []struct {
+		name   string
+		linked bool
+		want   error
+	}{
+		{"unlinked item", false, nil},
+		{"item linked elsewhere", true, ErrGradeItemLinked},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			service := &AssignmentService{repo: &linkRepo{linked: test.linked}, gradebookService: &lessonItems{}}
+			if err := service.validateLinks(&assignment); !errors.Is(err, test.want) {
+				t.Errorf("validateLinks() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
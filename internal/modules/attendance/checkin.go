@@ -0,0 +1,73 @@
+package attendance
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"lesson-management/entities"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkInMACBytes is how much of the HMAC-SHA256 tag is kept in a code
+const checkInMACBytes = 12
+
+// checkInStep numbers the rotation period containing at, counting from the window opening
+func checkInStep(window *entities.CheckInWindow, at time.Time) int64 {
+	return int64(at.Sub(window.OpensAt) / (time.Duration(window.RotationSeconds) * time.Second))
+}
+
+// checkInStepEnd returns when the given rotation period ends
+func checkInStepEnd(window *entities.CheckInWindow, step int64) time.Time {
+	end := window.OpensAt.Add(time.Duration(step+1) * time.Duration(window.RotationSeconds) * time.Second)
+	if end.After(window.ClosesAt) {
+		return window.ClosesAt
+	}
+	return end
+}
+
+// signCheckInCode returns the code for one rotation period as "<session>.<step>.<mac>".
+// The session and step are in the clear so a scanned code can be checked without a lookup table
+func signCheckInCode(window *entities.CheckInWindow, step int64) string {
+	payload := fmt.Sprintf("%d.%d", window.SessionID, step)
+	return payload + "." + checkInMAC(window.Secret, payload)
+}
+
+// parseCheckInCode splits a code into its session, step and MAC without verifying it
+func parseCheckInCode(code string) (uint, int64, string, bool) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 3 {
+		return 0, 0, "", false
+	}
+
+	sessionID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, "", false
+	}
+	step, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || step < 0 {
+		return 0, 0, "", false
+	}
+
+	return uint(sessionID), step, parts[2], true
+}
+
+// verifyCheckInCode accepts codes for the current rotation period and the one before it,
+// so a code read just before it rotates still works
+func verifyCheckInCode(window *entities.CheckInWindow, step int64, mac string, at time.Time) bool {
+	current := checkInStep(window, at)
+	if step != current && step != current-1 {
+		return false
+	}
+
+	expected := checkInMAC(window.Secret, fmt.Sprintf("%d.%d", window.SessionID, step))
+	return hmac.Equal([]byte(mac), []byte(expected))
+}
+
+func checkInMAC(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:checkInMACBytes])
+}
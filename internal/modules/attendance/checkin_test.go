@@ -0,0 +1,98 @@
+package attendance
+
+import (
+	"lesson-management/entities"
+	"testing"
+	"time"
+)
+
+func testWindow() *entities.CheckInWindow {
+	opensAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	return &entities.CheckInWindow{
+		SessionID:       12,
+		Secret:          []byte("check-in secret"),
+		OpensAt:         opensAt,
+		ClosesAt:        opensAt.Add(10 * time.Minute),
+		RotationSeconds: 30,
+	}
+}
+
+func TestCheckInCodeRoundTrip(t *testing.T) {
+	window := testWindow()
+	code := signCheckInCode(window, 3)
+
+	sessionID, step, mac, ok := parseCheckInCode(" " + code + "\n")
+	if !ok || sessionID != window.SessionID || step != 3 {
+		t.Fatalf("parseCheckInCode(%q) = %d, %d, %v, want %d, 3, true", code, sessionID, step, ok, window.SessionID)
+	}
+	if !verifyCheckInCode(window, step, mac, window.OpensAt.Add(95*time.Second)) {
+		t.Errorf("code for step 3 rejected during step 3")
+	}
+}
+
+func TestParseCheckInCodeRejectsMalformed(t *testing.T) {
+	for _, code := range []string{"", "12.3", "12.3.mac.extra", "x.3.mac", "12.x.mac", "12.-1.mac"} {
+		if _, _, _, ok := parseCheckInCode(code); ok {
+			t.Errorf("parseCheckInCode(%q) accepted a malformed code", code)
+		}
+	}
+}
+
+func TestVerifyCheckInCodeWindow(t *testing.T) {
+	window := testWindow()
+	at := window.OpensAt.Add(95 * time.Second) // step 3
+
+	for _, test := range []struct {
+		name string
+		step int64
+		want bool
+	}{
+		{"current step", 3, true},
+		{"previous step", 2, true},
+		{"two steps old", 1, false},
+		{"future step", 4, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, step, mac, _ := parseCheckInCode(signCheckInCode(window, test.step))
+			if got := verifyCheckInCode(window, step, mac, at); got != test.want {
+				t.Errorf("verifyCheckInCode() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCheckInCodeRejectsForgeries(t *testing.T) {
+	window := testWindow()
+	at := window.OpensAt.Add(95 * time.Second)
+	_, _, mac, _ := parseCheckInCode(signCheckInCode(window, 3))
+
+	if verifyCheckInCode(window, 2, mac, at) {
+		t.Error("MAC for step 3 accepted for step 2")
+	}
+
+	reopened := testWindow()
+	reopened.Secret = []byte("new secret")
+	if verifyCheckInCode(reopened, 3, mac, at) {
+		t.Error("code signed with the old secret accepted after the window was reopened")
+	}
+
+	other := testWindow()
+	other.SessionID = 13
+	if verifyCheckInCode(other, 3, mac, at) {
+		t.Error("code for session 12 accepted for session 13")
+	}
+}
+
+func TestCheckInStepEnd(t *testing.T) {
+	window := testWindow()
+
+	if got, want := checkInStepEnd(window, 0), window.OpensAt.Add(30*time.Second); !got.Equal(want) {
+		t.Errorf("checkInStepEnd(0) = %v, want %v", got, want)
+	}
+	if got := checkInStepEnd(window, 19); !got.Equal(window.ClosesAt) {
+		t.Errorf("checkInStepEnd(19) = %v, want the window close %v", got, window.ClosesAt)
+	}
+	if got := checkInStepEnd(window, 25); !got.Equal(window.ClosesAt) {
+		t.Errorf("checkInStepEnd(25) = %v, want it capped at %v", got, window.ClosesAt)
+	}
+}
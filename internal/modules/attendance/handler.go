@@ -0,0 +1,414 @@
+package attendance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/sessions"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// QR code image sizes in pixels
+const (
+	defaultQRSize = 256
+	minQRSize     = 128
+	maxQRSize     = 1024
+)
+
+type AttendanceHandler struct {
+	service IAttendanceService
+}
+
+func NewAttendanceHandler(service IAttendanceService) *AttendanceHandler {
+	return &AttendanceHandler{
+		service: service,
+	}
+}
+
+func (h *AttendanceHandler) GetSessionAttendance(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sheet, err := h.service.GetSessionAttendance(lessonID, sessionID, teacherID)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch attendance", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching attendance: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sheet)
+}
+
+func (h *AttendanceHandler) TakeAttendance(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.TakeAttendanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	sheet, err := h.service.TakeAttendance(lessonID, sessionID, teacherID, &requestBody)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to take attendance", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while taking attendance: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sheet)
+}
+
+func (h *AttendanceHandler) MarkAllPresent(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	sheet, err := h.service.MarkAllPresent(lessonID, sessionID, teacherID)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to mark all present", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while marking all present: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sheet)
+}
+
+// GetStudentAttendance lists the signed-in student's attendance, optionally for one lesson
+func (h *AttendanceHandler) GetStudentAttendance(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := lessonFilter(r)
+	if err != nil {
+		http.Error(w, "Invalid lesson_id", http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.service.GetStudentAttendance(studentID, lessonID)
+	if err != nil {
+		http.Error(w, "Failed to fetch attendance", http.StatusInternalServerError)
+		fmt.Println("Error while fetching student attendance: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(records)
+}
+
+func (h *AttendanceHandler) GetGuardianStudentAttendance(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.ParseUint(mux.Vars(r)["studentID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid Student ID", http.StatusBadRequest)
+		return
+	}
+
+	guardianID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := lessonFilter(r)
+	if err != nil {
+		http.Error(w, "Invalid lesson_id", http.StatusBadRequest)
+		return
+	}
+
+	records, err := h.service.GetGuardianStudentAttendance(guardianID, uint(studentID), lessonID)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch attendance", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student attendance: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(records)
+}
+
+func (h *AttendanceHandler) OpenCheckIn(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.OpenCheckInRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	window, err := h.service.OpenCheckIn(lessonID, sessionID, teacherID, &requestBody)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to open check-in", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while opening check-in: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(window)
+}
+
+func (h *AttendanceHandler) CloseCheckIn(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.CloseCheckIn(lessonID, sessionID, teacherID); err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to close check-in", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while closing check-in: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AttendanceHandler) GetCheckInCode(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	code, err := h.service.GetCheckInCode(lessonID, sessionID, teacherID)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch check-in code", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching check-in code: ", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(code)
+}
+
+// GetCheckInQR renders the current check-in code as a QR code PNG. Clients should refetch
+// it when the time in the X-Code-Expires-At header has passed
+func (h *AttendanceHandler) GetCheckInQR(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	size := defaultQRSize
+	if sizeStr := r.URL.Query().Get("size"); sizeStr != "" {
+		parsed, err := strconv.Atoi(sizeStr)
+		if err != nil || parsed < minQRSize || parsed > maxQRSize {
+			http.Error(w, fmt.Sprintf("Size must be between %d and %d", minQRSize, maxQRSize), http.StatusBadRequest)
+			return
+		}
+		size = parsed
+	}
+
+	code, err := h.service.GetCheckInCode(lessonID, sessionID, teacherID)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch check-in code", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching check-in code: ", err)
+		return
+	}
+
+	png, err := qrcode.Encode(code.Code, qrcode.Medium, size)
+	if err != nil {
+		http.Error(w, "Failed to render QR code", http.StatusInternalServerError)
+		fmt.Println("Error while rendering check-in QR code: ", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Code-Expires-At", code.ExpiresAt.UTC().Format(time.RFC3339))
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	w.Write(png)
+}
+
+// CheckIn marks the signed-in student present using a code shown in class
+func (h *AttendanceHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.CheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	record, err := h.service.CheckIn(studentID, requestBody.Code)
+	if err != nil {
+		if status := attendanceErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to check in", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while checking in: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(record)
+}
+
+// sessionIDs parses the lesson and session IDs from the URL
+func sessionIDs(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	vars := mux.Vars(r)
+	lessonID, err := strconv.ParseUint(vars["lessonID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	sessionID, err := strconv.ParseUint(vars["sessionID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return lessonID, sessionID, true
+}
+
+// attendanceErrorStatus maps service errors to HTTP status codes
+// lessonFilter reads the optional lesson_id query parameter that narrows a student's attendance
+func lessonFilter(r *http.Request) (*uint, error) {
+	idStr := r.URL.Query().Get("lesson_id")
+	if idStr == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	value := uint(id)
+	return &value, nil
+}
+
+func attendanceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher),
+		errors.Is(err, ErrNotGuardian):
+		return http.StatusForbidden
+	case errors.Is(err, ErrNoAttendanceRecords),
+		errors.Is(err, ErrInvalidAttendanceStatus),
+		errors.Is(err, ErrDuplicateStudent),
+		errors.Is(err, ErrStudentNotEnrolled),
+		errors.Is(err, ErrInvalidCheckInDuration),
+		errors.Is(err, ErrInvalidRotation),
+		errors.Is(err, ErrInvalidCheckInCode):
+		return http.StatusBadRequest
+	case errors.Is(err, sessions.ErrSessionCancelled),
+		errors.Is(err, ErrCheckInClosed),
+		errors.Is(err, ErrAlreadyRecorded),
+		errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,114 @@
+package attendance
+
+import (
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IAttendanceRepository interface {
+	GetSessionRecords(sessionID uint) ([]entities.AttendanceRecord, error)
+	SaveRecords(records []entities.AttendanceRecord) error
+	CreateMissingRecords(records []entities.AttendanceRecord) error
+	GetStudentRecords(studentID uint, lessonID *uint) ([]entities.AttendanceRecord, error)
+	IsGuardianOf(guardianID uint, studentID uint) (bool, error)
+	CreateRecordIfMissing(record *entities.AttendanceRecord) (bool, error)
+	GetCheckInWindow(sessionID uint) (entities.CheckInWindow, error)
+	SaveCheckInWindow(window *entities.CheckInWindow) error
+	CloseCheckInWindow(sessionID uint, at time.Time) error
+}
+
+type AttendanceRepository struct{}
+
+func NewAttendanceRepository() IAttendanceRepository {
+	return &AttendanceRepository{}
+}
+
+func (r *AttendanceRepository) GetSessionRecords(sessionID uint) ([]entities.AttendanceRecord, error) {
+	var records []entities.AttendanceRecord
+	result := common.DB.Where("session_id = ?", sessionID).Find(&records)
+	return records, result.Error
+}
+
+// SaveRecords inserts the records, overwriting any attendance already taken for the same students
+func (r *AttendanceRepository) SaveRecords(records []entities.AttendanceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return common.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}, {Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "note", "method", "recorded_by", "updated_at"}),
+	}).Create(&records).Error
+}
+
+// CreateMissingRecords inserts the records, leaving attendance that was already taken untouched
+func (r *AttendanceRepository) CreateMissingRecords(records []entities.AttendanceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return common.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&records).Error
+}
+
+// GetStudentRecords returns the student's attendance, most recent session first
+func (r *AttendanceRepository) GetStudentRecords(studentID uint, lessonID *uint) ([]entities.AttendanceRecord, error) {
+	query := common.DB.Model(&entities.AttendanceRecord{}).
+		Joins("JOIN lesson_sessions ON lesson_sessions.id = attendance_records.session_id").
+		Where("attendance_records.student_id = ?", studentID)
+	if lessonID != nil {
+		query = query.Where("attendance_records.lesson_id = ?", *lessonID)
+	}
+
+	var records []entities.AttendanceRecord
+	result := query.Preload("Session").
+		Order("lesson_sessions.starts_at DESC, attendance_records.id DESC").
+		Find(&records)
+	return records, result.Error
+}
+
+func (r *AttendanceRepository) IsGuardianOf(guardianID uint, studentID uint) (bool, error) {
+	var count int64
+	result := common.DB.Table("guardian_students").
+		Where("guardian_id = ? AND student_id = ?", guardianID, studentID).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// CreateRecordIfMissing inserts the record unless the student already has one for the session,
+// reporting whether it was created
+func (r *AttendanceRepository) CreateRecordIfMissing(record *entities.AttendanceRecord) (bool, error) {
+	result := common.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *AttendanceRepository) GetCheckInWindow(sessionID uint) (entities.CheckInWindow, error) {
+	var window entities.CheckInWindow
+	result := common.DB.Where("session_id = ?", sessionID).First(&window)
+	return window, result.Error
+}
+
+// SaveCheckInWindow opens the session's window, replacing any earlier one
+func (r *AttendanceRepository) SaveCheckInWindow(window *entities.CheckInWindow) error {
+	return common.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "opens_at", "closes_at", "rotation_seconds", "opened_by", "updated_at"}),
+	}).Create(window).Error
+}
+
+// CloseCheckInWindow ends an open window early
+func (r *AttendanceRepository) CloseCheckInWindow(sessionID uint, at time.Time) error {
+	result := common.DB.Model(&entities.CheckInWindow{}).
+		Where("session_id = ? AND closes_at > ?", sessionID, at).
+		Update("closes_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
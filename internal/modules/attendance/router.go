@@ -0,0 +1,43 @@
+package attendance
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *AttendanceHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/sessions/{sessionID:[0-9]+}/attendance").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.GetSessionAttendance).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("", handler.TakeAttendance).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/mark-all-present", handler.MarkAllPresent).Methods(http.MethodPost)
+
+	checkInRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/sessions/{sessionID:[0-9]+}/check-in").Subrouter()
+	checkInRoutes.Use(authMiddleware)
+	checkInRoutes.Use(middleware.RequireRole("teacher"))
+	checkInRoutes.HandleFunc("", handler.OpenCheckIn).Methods(http.MethodPost)
+	checkInRoutes.HandleFunc("", handler.CloseCheckIn).Methods(http.MethodDelete)
+	checkInRoutes.HandleFunc("/code", handler.GetCheckInCode).Methods(http.MethodGet)
+	checkInRoutes.HandleFunc("/qr.png", handler.GetCheckInQR).Methods(http.MethodGet)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/attendance", handler.GetStudentAttendance).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/check-in", handler.CheckIn).Methods(http.MethodPost)
+
+	// Guardian-only endpoints, limited to students linked to the guardian
+	guardianRoutes := router.PathPrefix("/api/guardian").Subrouter()
+	guardianRoutes.Use(authMiddleware)
+	guardianRoutes.Use(middleware.RequireRole("guardian"))
+	guardianRoutes.HandleFunc("/students/{studentID:[0-9]+}/attendance", handler.GetGuardianStudentAttendance).Methods(http.MethodGet)
+}
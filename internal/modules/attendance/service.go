@@ -0,0 +1,373 @@
+package attendance
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/sessions"
+	"lesson-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Check-in window limits; zero values in a request fall back to the defaults
+const (
+	DefaultCheckInMinutes  = 10
+	MaxCheckInMinutes      = 180
+	DefaultRotationSeconds = 30
+	MinRotationSeconds     = 10
+	MaxRotationSeconds     = 300
+)
+
+var (
+	ErrNoAttendanceRecords     = errors.New("at least one attendance record is required")
+	ErrInvalidAttendanceStatus = errors.New("status must be present, absent, late or excused")
+	ErrDuplicateStudent        = errors.New("each student may appear only once")
+	ErrStudentNotEnrolled      = errors.New("student is not enrolled in this lesson")
+	ErrInvalidCheckInDuration  = fmt.Errorf("check-in duration must be between 1 and %d minutes", MaxCheckInMinutes)
+	ErrInvalidRotation         = fmt.Errorf("code rotation must be between %d and %d seconds", MinRotationSeconds, MaxRotationSeconds)
+	ErrCheckInClosed           = errors.New("check-in is not open for this session")
+	ErrInvalidCheckInCode      = errors.New("check-in code is invalid or has expired")
+	ErrAlreadyRecorded         = errors.New("attendance is already recorded for this session")
+	ErrNotGuardian             = errors.New("you are not a guardian of this student")
+)
+
+var attendanceStatuses = map[string]bool{
+	entities.AttendancePresent: true,
+	entities.AttendanceAbsent:  true,
+	entities.AttendanceLate:    true,
+	entities.AttendanceExcused: true,
+}
+
+type IAttendanceService interface {
+	GetSessionAttendance(lessonID uint64, sessionID uint64, teacherID uint) ([]models.SessionAttendanceEntry, error)
+	TakeAttendance(lessonID uint64, sessionID uint64, teacherID uint, request *models.TakeAttendanceRequest) ([]models.SessionAttendanceEntry, error)
+	MarkAllPresent(lessonID uint64, sessionID uint64, teacherID uint) ([]models.SessionAttendanceEntry, error)
+	GetStudentAttendance(studentID uint, lessonID *uint) ([]entities.AttendanceRecord, error)
+	GetGuardianStudentAttendance(guardianID uint, studentID uint, lessonID *uint) ([]entities.AttendanceRecord, error)
+	OpenCheckIn(lessonID uint64, sessionID uint64, teacherID uint, request *models.OpenCheckInRequest) (*entities.CheckInWindow, error)
+	CloseCheckIn(lessonID uint64, sessionID uint64, teacherID uint) error
+	GetCheckInCode(lessonID uint64, sessionID uint64, teacherID uint) (*models.CheckInCodeResponse, error)
+	CheckIn(studentID uint, code string) (*entities.AttendanceRecord, error)
+}
+
+type AttendanceService struct {
+	repo           IAttendanceRepository
+	lessonService  lessons.ILessonService
+	sessionService sessions.ISessionService
+}
+
+func NewAttendanceService(repo IAttendanceRepository, lessonService lessons.ILessonService, sessionService sessions.ISessionService) IAttendanceService {
+	return &AttendanceService{
+		repo:           repo,
+		lessonService:  lessonService,
+		sessionService: sessionService,
+	}
+}
+
+// GetSessionAttendance returns the attendance sheet: every enrolled student with their
+// record for the session, if one was taken
+func (s *AttendanceService) GetSessionAttendance(lessonID uint64, sessionID uint64, teacherID uint) ([]models.SessionAttendanceEntry, error) {
+	if _, err := s.lessonService.TeacherLesson(lessonID, teacherID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionService.GetLessonSession(lessonID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.attendanceSheet(session)
+}
+
+// TakeAttendance records or corrects attendance for the listed students
+func (s *AttendanceService) TakeAttendance(lessonID uint64, sessionID uint64, teacherID uint, request *models.TakeAttendanceRequest) ([]models.SessionAttendanceEntry, error) {
+	if len(request.Records) == 0 {
+		return nil, ErrNoAttendanceRecords
+	}
+
+	session, err := s.editableSession(lessonID, sessionID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(session.LessonID))
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[uint]bool, len(students))
+	for _, student := range students {
+		enrolled[student.ID] = true
+	}
+
+	records := make([]entities.AttendanceRecord, 0, len(request.Records))
+	seen := make(map[uint]bool, len(request.Records))
+	for _, entry := range request.Records {
+		if !attendanceStatuses[entry.Status] {
+			return nil, ErrInvalidAttendanceStatus
+		}
+		if seen[entry.StudentID] {
+			return nil, ErrDuplicateStudent
+		}
+		seen[entry.StudentID] = true
+		if !enrolled[entry.StudentID] {
+			return nil, ErrStudentNotEnrolled
+		}
+
+		records = append(records, entities.AttendanceRecord{
+			LessonID:   session.LessonID,
+			SessionID:  session.ID,
+			StudentID:  entry.StudentID,
+			Status:     entry.Status,
+			Note:       entry.Note,
+			Method:     entities.AttendanceByTeacher,
+			RecordedBy: teacherID,
+		})
+	}
+
+	if err := s.repo.SaveRecords(records); err != nil {
+		return nil, err
+	}
+
+	return s.attendanceSheet(session)
+}
+
+// MarkAllPresent marks every student without a record as present; attendance already
+// taken for the session is left as it is
+func (s *AttendanceService) MarkAllPresent(lessonID uint64, sessionID uint64, teacherID uint) ([]models.SessionAttendanceEntry, error) {
+	session, err := s.editableSession(lessonID, sessionID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(session.LessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]entities.AttendanceRecord, 0, len(students))
+	for _, student := range students {
+		records = append(records, entities.AttendanceRecord{
+			LessonID:   session.LessonID,
+			SessionID:  session.ID,
+			StudentID:  student.ID,
+			Status:     entities.AttendancePresent,
+			Method:     entities.AttendanceByTeacher,
+			RecordedBy: teacherID,
+		})
+	}
+
+	if err := s.repo.CreateMissingRecords(records); err != nil {
+		return nil, err
+	}
+
+	return s.attendanceSheet(session)
+}
+
+func (s *AttendanceService) GetStudentAttendance(studentID uint, lessonID *uint) ([]entities.AttendanceRecord, error) {
+	return s.repo.GetStudentRecords(studentID, lessonID)
+}
+
+// GetGuardianStudentAttendance returns the attendance of a student linked to the guardian
+func (s *AttendanceService) GetGuardianStudentAttendance(guardianID uint, studentID uint, lessonID *uint) ([]entities.AttendanceRecord, error) {
+	linked, err := s.repo.IsGuardianOf(guardianID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, ErrNotGuardian
+	}
+
+	return s.repo.GetStudentRecords(studentID, lessonID)
+}
+
+// OpenCheckIn starts a self check-in window for the session with a fresh signing secret
+func (s *AttendanceService) OpenCheckIn(lessonID uint64, sessionID uint64, teacherID uint, request *models.OpenCheckInRequest) (*entities.CheckInWindow, error) {
+	duration := request.DurationMinutes
+	if duration == 0 {
+		duration = DefaultCheckInMinutes
+	}
+	if duration < 1 || duration > MaxCheckInMinutes {
+		return nil, ErrInvalidCheckInDuration
+	}
+
+	rotation := request.RotationSeconds
+	if rotation == 0 {
+		rotation = DefaultRotationSeconds
+	}
+	if rotation < MinRotationSeconds || rotation > MaxRotationSeconds {
+		return nil, ErrInvalidRotation
+	}
+
+	session, err := s.editableSession(lessonID, sessionID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	window := &entities.CheckInWindow{
+		LessonID:        session.LessonID,
+		SessionID:       session.ID,
+		Secret:          secret,
+		OpensAt:         now,
+		ClosesAt:        now.Add(time.Duration(duration) * time.Minute),
+		RotationSeconds: rotation,
+		OpenedBy:        teacherID,
+	}
+	if err := s.repo.SaveCheckInWindow(window); err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+func (s *AttendanceService) CloseCheckIn(lessonID uint64, sessionID uint64, teacherID uint) error {
+	session, err := s.editableSession(lessonID, sessionID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.CloseCheckInWindow(session.ID, time.Now())
+}
+
+// GetCheckInCode returns the code for the current rotation period, for the teacher to display
+func (s *AttendanceService) GetCheckInCode(lessonID uint64, sessionID uint64, teacherID uint) (*models.CheckInCodeResponse, error) {
+	session, err := s.editableSession(lessonID, sessionID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := s.repo.GetCheckInWindow(session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !window.IsOpen(now) {
+		return nil, ErrCheckInClosed
+	}
+
+	step := checkInStep(&window, now)
+	return &models.CheckInCodeResponse{
+		Code:      signCheckInCode(&window, step),
+		ExpiresAt: checkInStepEnd(&window, step),
+	}, nil
+}
+
+// CheckIn marks the student present with a code from an open window. A code only verifies
+// against its own session and rotation period, and each student checks in at most once,
+// so a copied code stops working within a rotation and cannot be replayed
+func (s *AttendanceService) CheckIn(studentID uint, code string) (*entities.AttendanceRecord, error) {
+	sessionID, step, mac, ok := parseCheckInCode(code)
+	if !ok {
+		return nil, ErrInvalidCheckInCode
+	}
+
+	window, err := s.repo.GetCheckInWindow(sessionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidCheckInCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !window.IsOpen(now) {
+		return nil, ErrCheckInClosed
+	}
+	if !verifyCheckInCode(&window, step, mac, now) {
+		return nil, ErrInvalidCheckInCode
+	}
+
+	session, err := s.sessionService.GetLessonSession(uint64(window.LessonID), uint64(window.SessionID))
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == entities.SessionCancelled {
+		return nil, sessions.ErrSessionCancelled
+	}
+
+	enrolled, err := s.lessonService.IsStudentEnrolled(uint64(window.LessonID), studentID)
+	if err != nil {
+		return nil, err
+	}
+	if !enrolled {
+		return nil, ErrStudentNotEnrolled
+	}
+
+	record := &entities.AttendanceRecord{
+		LessonID:  window.LessonID,
+		SessionID: window.SessionID,
+		StudentID: studentID,
+		Status:    entities.AttendancePresent,
+		Method:    entities.AttendanceByCheckIn,
+	}
+	created, err := s.repo.CreateRecordIfMissing(record)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyRecorded
+	}
+
+	return record, nil
+}
+
+func (s *AttendanceService) attendanceSheet(session *entities.LessonSession) ([]models.SessionAttendanceEntry, error) {
+	students, err := s.lessonService.GetEnrolledStudents(uint64(session.LessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.GetSessionRecords(session.ID)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint]entities.AttendanceRecord, len(records))
+	for _, record := range records {
+		byStudent[record.StudentID] = record
+	}
+
+	sheet := make([]models.SessionAttendanceEntry, 0, len(students))
+	for _, student := range students {
+		entry := models.SessionAttendanceEntry{StudentID: student.ID, Name: student.Name}
+		if record, ok := byStudent[student.ID]; ok {
+			entry.Status = record.Status
+			entry.Note = record.Note
+			entry.RecordedAt = &record.UpdatedAt
+		}
+		sheet = append(sheet, entry)
+	}
+
+	return sheet, nil
+}
+
+// editableSession loads a session the teacher may take attendance for: the lesson must be
+// theirs and not archived, and the session must not be cancelled
+func (s *AttendanceService) editableSession(lessonID uint64, sessionID uint64, teacherID uint) (*entities.LessonSession, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+	if lesson.Status == entities.LessonArchived {
+		return nil, lessons.ErrLessonArchived
+	}
+
+	session, err := s.sessionService.GetLessonSession(lessonID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status == entities.SessionCancelled {
+		return nil, sessions.ErrSessionCancelled
+	}
+
+	return session, nil
+}
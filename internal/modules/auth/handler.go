@@ -2,6 +2,7 @@ package auth
 
 import (
 	"encoding/json"
+	"fmt"
 	"lesson-management/models"
 	"net/http"
 )
@@ -84,3 +85,27 @@ func (h *AuthHandler) RegisterTeacher(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(user)
 }
+
+func (h *AuthHandler) RegisterGuardian(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateGuardianRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Email == "" || req.Password == "" || req.Name == "" {
+		http.Error(w, "Name, email, and password are required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.service.RegisterGuardian(req.Name, req.Email, req.Password)
+	if err != nil {
+		http.Error(w, "Failed to register guardian", http.StatusInternalServerError)
+		fmt.Println("Error while registering guardian: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
@@ -10,12 +10,15 @@ type IAuthRepository interface {
 	FindAdminByEmail(email string) (*entities.Admin, error)
 	FindTeacherByEmail(email string) (*entities.Teacher, error)
 	FindStudentByEmail(email string) (*entities.Student, error)
+	FindGuardianByEmail(email string) (*entities.Guardian, error)
 	FindAdminByID(id uint) (*entities.Admin, error)
 	FindTeacherByID(id uint) (*entities.Teacher, error)
 	FindStudentByID(id uint) (*entities.Student, error)
+	FindGuardianByID(id uint) (*entities.Guardian, error)
 	CreateAdmin(admin *entities.Admin) error
 	CreateTeacher(teacher *entities.Teacher) error
 	CreateStudent(student *entities.Student) error
+	CreateGuardian(guardian *entities.Guardian) error
 }
 
 type AuthRepository struct{}
@@ -51,6 +54,15 @@ func (r *AuthRepository) FindStudentByEmail(email string) (*entities.Student, er
 	return &student, nil
 }
 
+func (r *AuthRepository) FindGuardianByEmail(email string) (*entities.Guardian, error) {
+	var guardian entities.Guardian
+	result := common.DB.Where("email = ?", email).First(&guardian)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &guardian, nil
+}
+
 func (r *AuthRepository) FindAdminByID(id uint) (*entities.Admin, error) {
 	var admin entities.Admin
 	result := common.DB.First(&admin, id)
@@ -78,6 +90,15 @@ func (r *AuthRepository) FindStudentByID(id uint) (*entities.Student, error) {
 	return &student, nil
 }
 
+func (r *AuthRepository) FindGuardianByID(id uint) (*entities.Guardian, error) {
+	var guardian entities.Guardian
+	result := common.DB.First(&guardian, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &guardian, nil
+}
+
 // GetUserByRole fetches user from appropriate table based on role
 func (r *AuthRepository) GetUserByRole(role string, userID uint) (interface{}, string, error) {
 	switch role {
@@ -99,6 +120,12 @@ func (r *AuthRepository) GetUserByRole(role string, userID uint) (interface{}, s
 			return nil, "", fmt.Errorf("student not found")
 		}
 		return user, user.Role, nil
+	case "guardian":
+		user, err := r.FindGuardianByID(userID)
+		if err != nil {
+			return nil, "", fmt.Errorf("guardian not found")
+		}
+		return user, user.Role, nil
 	default:
 		return nil, "", fmt.Errorf("invalid role")
 	}
@@ -115,3 +142,7 @@ func (r *AuthRepository) CreateTeacher(teacher *entities.Teacher) error {
 func (r *AuthRepository) CreateStudent(student *entities.Student) error {
 	return common.DB.Create(student).Error
 }
+
+func (r *AuthRepository) CreateGuardian(guardian *entities.Guardian) error {
+	return common.DB.Create(guardian).Error
+}
@@ -19,6 +19,7 @@ type IAuthService interface {
 	RegisterAdmin(name, email, password string) (*models.CreateUserResponse, error)
 	RegisterTeacher(name, email, password string) (*models.CreateUserResponse, error)
 	RegisterStudent(name, email, password string) (*models.CreateUserResponse, error)
+	RegisterGuardian(name, email, password string) (*models.CreateUserResponse, error)
 }
 
 type AuthService struct {
@@ -83,6 +84,17 @@ func (s *AuthService) Login(email, password, role string) (*models.LoginResponse
 		userID = student.ID
 		userName = student.Name
 
+	case "guardian":
+		guardian, err := s.repo.FindGuardianByEmail(email)
+		if err != nil {
+			return nil, errors.New("invalid credentials")
+		}
+		if !s.checkPasswordHash(password, guardian.Password) {
+			return nil, errors.New("invalid credentials")
+		}
+		userID = guardian.ID
+		userName = guardian.Name
+
 	default:
 		return nil, errors.New("invalid role")
 	}
@@ -222,3 +234,28 @@ func (s *AuthService) RegisterStudent(name, email, password string) (*models.Cre
 		Role:  student.Role,
 	}, nil
 }
+
+func (s *AuthService) RegisterGuardian(name, email, password string) (*models.CreateUserResponse, error) {
+	hashedPassword, err := s.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	guardian := &entities.Guardian{
+		Name:     name,
+		Email:    email,
+		Password: hashedPassword,
+		Role:     "guardian",
+	}
+
+	if err := s.repo.CreateGuardian(guardian); err != nil {
+		return nil, err
+	}
+
+	return &models.CreateUserResponse{
+		ID:    guardian.ID,
+		Name:  guardian.Name,
+		Email: guardian.Email,
+		Role:  guardian.Role,
+	}, nil
+}
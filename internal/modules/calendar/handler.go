@@ -0,0 +1,134 @@
+package calendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type CalendarHandler struct {
+	service ICalendarService
+}
+
+func NewCalendarHandler(service ICalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		service: service,
+	}
+}
+
+func (h *CalendarHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	role, ownerID, ok := feedOwner(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := h.service.GetFeedToken(role, ownerID)
+	if err != nil {
+		if status := calendarErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch calendar token", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching calendar token: ", err)
+		return
+	}
+
+	writeFeedResponse(w, r, token, http.StatusOK)
+}
+
+func (h *CalendarHandler) RegenerateFeed(w http.ResponseWriter, r *http.Request) {
+	role, ownerID, ok := feedOwner(w, r)
+	if !ok {
+		return
+	}
+
+	token, err := h.service.RegenerateFeedToken(role, ownerID)
+	if err != nil {
+		if status := calendarErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to regenerate calendar token", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while regenerating calendar token: ", err)
+		return
+	}
+
+	writeFeedResponse(w, r, token, http.StatusCreated)
+}
+
+// Feed serves the iCalendar file; the secret token in the URL is the only credential
+func (h *CalendarHandler) Feed(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+
+	feed, err := h.service.RenderFeed(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Calendar feed not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to build calendar feed", http.StatusInternalServerError)
+		fmt.Println("Error while building calendar feed: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(feed))
+}
+
+func feedOwner(w http.ResponseWriter, r *http.Request) (string, uint, bool) {
+	ownerID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", 0, false
+	}
+
+	role, ok := middleware.GetRole(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", 0, false
+	}
+
+	return role, ownerID, true
+}
+
+func writeFeedResponse(w http.ResponseWriter, r *http.Request, token *entities.CalendarToken, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.CalendarFeedResponse{
+		Token: token.Token,
+		URL:   feedURL(r, token.Token),
+	})
+}
+
+// feedURL builds the absolute feed address from the request, honouring a TLS-terminating proxy
+func feedURL(r *http.Request, token string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	feed := url.URL{Scheme: scheme, Host: r.Host, Path: "/api/calendar/" + token + ".ics"}
+	return feed.String()
+}
+
+// calendarErrorStatus maps service errors to HTTP status codes
+func calendarErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrUnsupportedRole):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,117 @@
+package calendar
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icsTimeFormat     = "20060102T150405Z"
+	icsMaxLineOctets  = 75
+	defaultUIDDomain  = "lesson-management"
+	calendarProductID = "-//Lesson Management//Lesson Calendar//EN"
+	feedRefreshPeriod = "PT1H"
+)
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// renderCalendar writes an RFC 5545 calendar with one event per session. Events are keyed by
+// session ID so edits and cancellations replace the copy already in the subscriber's calendar
+func renderCalendar(name string, lessons []*entities.Lesson, sessions []*entities.LessonSession, now time.Time) string {
+	byID := make(map[uint]*entities.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		byID[lesson.ID] = lesson
+	}
+
+	var b strings.Builder
+	line := func(property, value string) {
+		b.WriteString(foldLine(property + ":" + value))
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", calendarProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeText(name))
+	line("REFRESH-INTERVAL;VALUE=DURATION", feedRefreshPeriod)
+	line("X-PUBLISHED-TTL", feedRefreshPeriod)
+
+	domain := uidDomain()
+	for _, session := range sessions {
+		lesson, ok := byID[session.LessonID]
+		if !ok {
+			continue
+		}
+
+		status := "CONFIRMED"
+		if session.Status == entities.SessionCancelled {
+			status = "CANCELLED"
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("session-%d@%s", session.ID, domain))
+		line("DTSTAMP", now.UTC().Format(icsTimeFormat))
+		line("DTSTART", session.StartsAt.UTC().Format(icsTimeFormat))
+		line("DTEND", session.EndsAt.UTC().Format(icsTimeFormat))
+		line("LAST-MODIFIED", session.UpdatedAt.UTC().Format(icsTimeFormat))
+		line("SEQUENCE", fmt.Sprint(session.Sequence))
+		line("STATUS", status)
+		line("SUMMARY", escapeText(lesson.Title))
+		if lesson.Description != "" {
+			line("DESCRIPTION", escapeText(lesson.Description))
+		}
+		if session.Location != "" {
+			line("LOCATION", escapeText(session.Location))
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+	return b.String()
+}
+
+// escapeText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// foldLine splits a content line into chunks of at most 75 octets, never inside a UTF-8
+// sequence, and terminates it with CRLF (RFC 5545 section 3.1)
+func foldLine(content string) string {
+	var b strings.Builder
+	limit := icsMaxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = icsMaxLineOctets - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// uidDomain is the right-hand side of event UIDs; it must stay stable once feeds are in use
+func uidDomain() string {
+	if domain := os.Getenv("CALENDAR_UID_DOMAIN"); domain != "" {
+		return domain
+	}
+	return defaultUIDDomain
+}
@@ -0,0 +1,72 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		want  string
+	}{
+		{"Algebra", "Algebra"},
+		{`Room 4; bring a pen, paper`, `Room 4\; bring a pen\, paper`},
+		{`C:\path`, `C:\\path`},
+		{"line one\r\nline two\nline three\rend", `line one\nline two\nline three\nend`},
+	} {
+		if got := escapeText(test.input); got != test.want {
+			t.Errorf("escapeText(%q) = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestFoldLineShort(t *testing.T) {
+	content := "SUMMARY:" + strings.Repeat("a", icsMaxLineOctets-len("SUMMARY:"))
+	if got := foldLine(content); got != content+"\r\n" {
+		t.Errorf("foldLine() of a 75 octet line = %q, want it unfolded", got)
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		content string
+	}{
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multi-byte", "DESCRIPTION:" + strings.Repeat("äöü€😀", 30)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			folded := foldLine(test.content)
+			if !strings.HasSuffix(folded, "\r\n") {
+				t.Fatalf("foldLine() = %q, want it to end with CRLF", folded)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+			if len(lines) < 2 {
+				t.Fatalf("foldLine() = %q, want it folded", folded)
+			}
+
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > icsMaxLineOctets {
+					t.Errorf("line %d is %d octets, want at most %d", i, len(line), icsMaxLineOctets)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d %q splits a UTF-8 sequence", i, line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Fatalf("continuation line %d %q doesn't start with a space", i, line)
+					}
+					line = line[1:]
+				}
+				unfolded.WriteString(line)
+			}
+			if unfolded.String() != test.content {
+				t.Errorf("unfolding gave %q, want %q", unfolded.String(), test.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package calendar
+
+import (
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm/clause"
+)
+
+type ICalendarRepository interface {
+	GetOwnerToken(role string, ownerID uint) (entities.CalendarToken, error)
+	GetToken(token string) (entities.CalendarToken, error)
+	SaveToken(token *entities.CalendarToken) error
+}
+
+type CalendarRepository struct{}
+
+func NewCalendarRepository() ICalendarRepository {
+	return &CalendarRepository{}
+}
+
+func (r *CalendarRepository) GetOwnerToken(role string, ownerID uint) (entities.CalendarToken, error) {
+	var token entities.CalendarToken
+	result := common.DB.Where("owner_role = ? AND owner_id = ?", role, ownerID).First(&token)
+	return token, result.Error
+}
+
+func (r *CalendarRepository) GetToken(token string) (entities.CalendarToken, error) {
+	var calendarToken entities.CalendarToken
+	result := common.DB.Where("token = ?", token).First(&calendarToken)
+	return calendarToken, result.Error
+}
+
+// SaveToken stores the owner's token, replacing any previous one
+func (r *CalendarRepository) SaveToken(token *entities.CalendarToken) error {
+	return common.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "owner_role"}, {Name: "owner_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "created_at"}),
+	}).Create(token).Error
+}
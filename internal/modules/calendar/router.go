@@ -0,0 +1,24 @@
+package calendar
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *CalendarHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Feed endpoint, authenticated by the secret token in the URL
+	router.HandleFunc("/api/calendar/{token:[A-Za-z0-9_-]+}.ics", handler.Feed).Methods(http.MethodGet)
+
+	// Teacher and student endpoints
+	feedRoutes := router.PathPrefix("/api/calendar/feed").Subrouter()
+	feedRoutes.Use(authMiddleware)
+	feedRoutes.Use(middleware.RequireRole(RoleTeacher, RoleStudent))
+	feedRoutes.HandleFunc("", handler.GetFeed).Methods(http.MethodGet)
+	feedRoutes.HandleFunc("/regenerate", handler.RegenerateFeed).Methods(http.MethodPost)
+}
@@ -0,0 +1,146 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/sessions"
+	"lesson-management/pkg/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleTeacher = "teacher"
+	RoleStudent = "student"
+
+	tokenBytes = 32
+)
+
+var ErrUnsupportedRole = errors.New("calendar feeds are only available to teachers and students")
+
+type ICalendarService interface {
+	GetFeedToken(role string, ownerID uint) (*entities.CalendarToken, error)
+	RegenerateFeedToken(role string, ownerID uint) (*entities.CalendarToken, error)
+	RenderFeed(token string) (string, error)
+}
+
+type CalendarService struct {
+	repo           ICalendarRepository
+	lessonService  lessons.ILessonService
+	sessionService sessions.ISessionService
+}
+
+func NewCalendarService(repo ICalendarRepository, lessonService lessons.ILessonService, sessionService sessions.ISessionService) ICalendarService {
+	return &CalendarService{
+		repo:           repo,
+		lessonService:  lessonService,
+		sessionService: sessionService,
+	}
+}
+
+// GetFeedToken returns the owner's feed token, creating one on first use
+func (s *CalendarService) GetFeedToken(role string, ownerID uint) (*entities.CalendarToken, error) {
+	if role != RoleTeacher && role != RoleStudent {
+		return nil, ErrUnsupportedRole
+	}
+
+	token, err := s.repo.GetOwnerToken(role, ownerID)
+	if err == nil {
+		return &token, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return s.RegenerateFeedToken(role, ownerID)
+}
+
+// RegenerateFeedToken replaces the owner's token, so the old feed URL stops working
+func (s *CalendarService) RegenerateFeedToken(role string, ownerID uint) (*entities.CalendarToken, error) {
+	if role != RoleTeacher && role != RoleStudent {
+		return nil, ErrUnsupportedRole
+	}
+
+	secret := make([]byte, tokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	token := &entities.CalendarToken{
+		OwnerRole: role,
+		OwnerID:   ownerID,
+		Token:     base64.RawURLEncoding.EncodeToString(secret),
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.SaveToken(token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// RenderFeed builds the iCalendar feed of every session in the token owner's lessons
+func (s *CalendarService) RenderFeed(token string) (string, error) {
+	calendarToken, err := s.repo.GetToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	ownerLessons, err := s.ownerLessons(calendarToken.OwnerRole, calendarToken.OwnerID)
+	if err != nil {
+		return "", err
+	}
+
+	lessonIDs := make([]uint, 0, len(ownerLessons))
+	for _, lesson := range ownerLessons {
+		lessonIDs = append(lessonIDs, lesson.ID)
+	}
+	lessonSessions, err := s.sessionService.GetSessionsForLessons(lessonIDs)
+	if err != nil {
+		return "", err
+	}
+
+	name := "My lessons"
+	if calendarToken.OwnerRole == RoleTeacher {
+		name = "My teaching schedule"
+	}
+	return renderCalendar(name, ownerLessons, lessonSessions, time.Now()), nil
+}
+
+// ownerLessons walks every page of the owner's lesson list across all terms
+func (s *CalendarService) ownerLessons(role string, ownerID uint) ([]*entities.Lesson, error) {
+	var all []*entities.Lesson
+	page := pagination.New(lessons.LessonSorts, pagination.MaxLimit)
+
+	for {
+		var batch []*entities.Lesson
+		var pageInfo pagination.Page
+		var err error
+
+		switch role {
+		case RoleTeacher:
+			batch, pageInfo, err = s.lessonService.GetTeacherLessons(ownerID, lessons.LessonFilter{}, page)
+		case RoleStudent:
+			batch, pageInfo, err = s.lessonService.GetStudentLessons(ownerID, lessons.LessonFilter{}, page)
+		default:
+			return nil, ErrUnsupportedRole
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, batch...)
+		if pageInfo.NextCursor == "" {
+			return all, nil
+		}
+
+		page, err = page.After(pageInfo.NextCursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,459 @@
+package courses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type CourseHandler struct {
+	service ICourseService
+}
+
+func NewCourseHandler(service ICourseService) *CourseHandler {
+	return &CourseHandler{
+		service: service,
+	}
+}
+
+func (h *CourseHandler) Get(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.service.GetPublishedCourse(courseID)
+	if err != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		fmt.Println("Error while fetching course: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(course)
+}
+
+// GetAny returns the full course tree including unpublished lessons
+func (h *CourseHandler) GetAny(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.service.GetCourse(courseID)
+	if err != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		fmt.Println("Error while fetching course: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(course)
+}
+
+func (h *CourseHandler) List(w http.ResponseWriter, r *http.Request) {
+	courses, err := h.service.GetAllCourses()
+	if err != nil {
+		http.Error(w, "Failed to fetch courses", http.StatusInternalServerError)
+		fmt.Println("Error while fetching courses: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(courses)
+}
+
+func (h *CourseHandler) ListLessons(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	lessons, err := h.service.GetCourseLessons(courseID)
+	if err != nil {
+		http.Error(w, "Course not found", http.StatusNotFound)
+		fmt.Println("Error while fetching course lessons: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lessons)
+}
+
+func (h *CourseHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var requestBody models.CreateCourseRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Title == "" {
+		http.Error(w, "Course title is required", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.service.CreateCourse(&requestBody)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		fmt.Println("Error while creating course: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(course)
+}
+
+func (h *CourseHandler) Update(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.PatchCourseRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Title != nil && *requestBody.Title == "" {
+		http.Error(w, "Course title cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.service.UpdateCourse(&requestBody, courseID)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update course", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating course: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(course)
+}
+
+func (h *CourseHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteCourse(courseID); err != nil {
+		http.Error(w, "Failed to delete course", http.StatusInternalServerError)
+		fmt.Println("Error while deleting course: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Module handlers
+func (h *CourseHandler) CreateModule(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.CreateModuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Title == "" {
+		http.Error(w, "Module title is required", http.StatusBadRequest)
+		return
+	}
+
+	module, err := h.service.CreateModule(courseID, &requestBody)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create module", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating module: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(module)
+}
+
+func (h *CourseHandler) UpdateModule(w http.ResponseWriter, r *http.Request) {
+	courseID, moduleID, ok := parseCourseModuleIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var requestBody models.PatchModuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Title != nil && *requestBody.Title == "" {
+		http.Error(w, "Module title cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	module, err := h.service.UpdateModule(courseID, moduleID, &requestBody)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update module", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating module: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(module)
+}
+
+func (h *CourseHandler) DeleteModule(w http.ResponseWriter, r *http.Request) {
+	courseID, moduleID, ok := parseCourseModuleIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteModule(courseID, moduleID); err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete module", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting module: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *CourseHandler) ReorderModules(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.ReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.service.ReorderModules(courseID, requestBody.IDs)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to reorder modules", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while reordering modules: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(course)
+}
+
+// Module lesson handlers
+func (h *CourseHandler) AddLesson(w http.ResponseWriter, r *http.Request) {
+	courseID, moduleID, ok := parseCourseModuleIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var requestBody models.AddModuleLessonRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.AddLessonToModule(courseID, moduleID, requestBody.LessonID)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to add lesson to module", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while adding lesson to module: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *CourseHandler) RemoveLesson(w http.ResponseWriter, r *http.Request) {
+	courseID, moduleID, ok := parseCourseModuleIDs(w, r)
+	if !ok {
+		return
+	}
+
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.RemoveLessonFromModule(courseID, moduleID, uint(lessonID)); err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to remove lesson from module", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while removing lesson from module: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *CourseHandler) ReorderLessons(w http.ResponseWriter, r *http.Request) {
+	courseID, moduleID, ok := parseCourseModuleIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var requestBody models.ReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	course, err := h.service.ReorderModuleLessons(courseID, moduleID, requestBody.IDs)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to reorder module lessons", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while reordering module lessons: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(course)
+}
+
+// Enrollment handlers
+func (h *CourseHandler) EnrollStudent(w http.ResponseWriter, r *http.Request) {
+	courseIDStr := mux.Vars(r)["courseID"]
+	courseID, err := strconv.ParseUint(courseIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		StudentID uint `json:"student_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.EnrollStudent(courseID, req.StudentID)
+	if err != nil {
+		if status := courseErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to enroll student in course", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while enrolling student in course: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *CourseHandler) GetStudentCourses(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	courses, err := h.service.GetStudentCourses(studentID)
+	if err != nil {
+		http.Error(w, "Failed to fetch courses", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(courses)
+}
+
+func parseCourseModuleIDs(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	courseID, err := strconv.ParseUint(mux.Vars(r)["courseID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid course ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	moduleID, err := strconv.ParseUint(mux.Vars(r)["moduleID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid module ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return courseID, moduleID, true
+}
+
+// courseErrorStatus maps service errors to HTTP status codes
+func courseErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, ErrModuleNotInCourse):
+		return http.StatusNotFound
+	case errors.Is(err, ErrLessonInModule),
+		errors.Is(err, lessons.ErrLessonFull):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidOrder):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
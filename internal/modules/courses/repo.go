@@ -0,0 +1,311 @@
+package courses
+
+import (
+	"errors"
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ICourseRepository interface {
+	GetCourse(id uint) (entities.Course, error)
+	GetAllCourses() ([]*entities.Course, error)
+	CreateCourse(course *entities.Course) error
+	UpdateCourse(course *entities.Course) error
+	DeleteCourse(id uint) error
+	GetModule(id uint) (entities.Module, error)
+	CreateModule(module *entities.Module) error
+	UpdateModule(module *entities.Module) error
+	DeleteModule(id uint) error
+	ReorderModules(courseID uint, moduleIDs []uint) error
+	AddLessonToModule(module *entities.Module, lessonID uint, studentIDs []uint) ([]models.SkippedCourseStudent, error)
+	RemoveLessonFromModule(moduleID uint, lessonID uint) error
+	ReorderModuleLessons(moduleID uint, lessonIDs []uint) error
+	GetPublishedCourseLessons(courseID uint) ([]entities.Lesson, error)
+	GetCourseStudentIDs(courseID uint) ([]uint, error)
+	EnrollStudentInCourse(courseID uint, studentID uint, lessons []entities.Lesson) (*models.CourseEnrollmentResult, error)
+	GetCoursesByStudentID(studentID uint) ([]*entities.Course, error)
+}
+
+type CourseRepository struct{}
+
+func NewCourseRepository() ICourseRepository {
+	return &CourseRepository{}
+}
+
+func (r *CourseRepository) GetCourse(id uint) (entities.Course, error) {
+	var course entities.Course
+	result := common.DB.
+		Preload("Modules", orderByPosition).
+		Preload("Modules.Lessons", orderByPosition).
+		Preload("Modules.Lessons.Lesson").
+		First(&course, id)
+	return course, result.Error
+}
+
+func (r *CourseRepository) GetAllCourses() ([]*entities.Course, error) {
+	var courses []*entities.Course
+	result := common.DB.Preload("Modules", orderByPosition).Order("title").Find(&courses)
+	return courses, result.Error
+}
+
+func (r *CourseRepository) CreateCourse(course *entities.Course) error {
+	return common.DB.Create(course).Error
+}
+
+func (r *CourseRepository) UpdateCourse(course *entities.Course) error {
+	result := common.DB.Omit(clause.Associations).Save(course)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteCourse removes the course structure; lesson enrollments made through the course are kept
+func (r *CourseRepository) DeleteCourse(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		modules := tx.Model(&entities.Module{}).Select("id").Where("course_id = ?", id)
+		if err := tx.Where("module_id IN (?)", modules).Delete(&entities.ModuleLesson{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("course_id = ?", id).Delete(&entities.Module{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("course_id = ?", id).Delete(&entities.CourseEnrollment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Course{}, id).Error
+	})
+}
+
+func (r *CourseRepository) GetModule(id uint) (entities.Module, error) {
+	var module entities.Module
+	result := common.DB.First(&module, id)
+	return module, result.Error
+}
+
+// CreateModule appends the module to the end of its course
+func (r *CourseRepository) CreateModule(module *entities.Module) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&entities.Module{}).
+			Where("course_id = ?", module.CourseID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		module.Position = last + 1
+		return tx.Create(module).Error
+	})
+}
+
+func (r *CourseRepository) UpdateModule(module *entities.Module) error {
+	return common.DB.Omit(clause.Associations).Save(module).Error
+}
+
+func (r *CourseRepository) DeleteModule(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("module_id = ?", id).Delete(&entities.ModuleLesson{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Module{}, id).Error
+	})
+}
+
+func (r *CourseRepository) ReorderModules(courseID uint, moduleIDs []uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&entities.Module{}).Where("course_id = ?", courseID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !common.SameIDs(existing, moduleIDs) {
+			return ErrInvalidOrder
+		}
+
+		for position, id := range moduleIDs {
+			if err := tx.Model(&entities.Module{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AddLessonToModule appends the lesson to the module and enrolls the given course students in it.
+// Students the lesson has no room for are skipped and returned instead of failing the whole change
+func (r *CourseRepository) AddLessonToModule(module *entities.Module, lessonID uint, studentIDs []uint) ([]models.SkippedCourseStudent, error) {
+	skipped := []models.SkippedCourseStudent{}
+	err := common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entities.Lesson{}, lessonID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&entities.ModuleLesson{}).Where("lesson_id = ?", lessonID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrLessonInModule
+		}
+
+		var last int
+		if err := tx.Model(&entities.ModuleLesson{}).
+			Where("module_id = ?", module.ID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		moduleLesson := &entities.ModuleLesson{
+			ModuleID: module.ID,
+			LessonID: lessonID,
+			Position: last + 1,
+		}
+		if err := tx.Create(moduleLesson).Error; err != nil {
+			return err
+		}
+
+		for _, studentID := range studentIDs {
+			err := lessons.EnrollInTransaction(tx, lessonID, studentID)
+			if errors.Is(err, lessons.ErrLessonFull) {
+				skipped = append(skipped, models.SkippedCourseStudent{StudentID: studentID, Reason: SkipLessonFull})
+				continue
+			}
+			if err != nil {
+				return &LessonEnrollmentError{LessonID: lessonID, StudentID: studentID, Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return skipped, nil
+}
+
+func (r *CourseRepository) RemoveLessonFromModule(moduleID uint, lessonID uint) error {
+	result := common.DB.Where("module_id = ? AND lesson_id = ?", moduleID, lessonID).Delete(&entities.ModuleLesson{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *CourseRepository) ReorderModuleLessons(moduleID uint, lessonIDs []uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&entities.ModuleLesson{}).Where("module_id = ?", moduleID).Pluck("lesson_id", &existing).Error; err != nil {
+			return err
+		}
+		if !common.SameIDs(existing, lessonIDs) {
+			return ErrInvalidOrder
+		}
+
+		for position, id := range lessonIDs {
+			if err := tx.Model(&entities.ModuleLesson{}).
+				Where("module_id = ? AND lesson_id = ?", moduleID, id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetPublishedCourseLessons returns the published lessons of the course in module order
+func (r *CourseRepository) GetPublishedCourseLessons(courseID uint) ([]entities.Lesson, error) {
+	var courseLessons []entities.Lesson
+	result := common.DB.
+		Joins("JOIN module_lessons ON module_lessons.lesson_id = lessons.id").
+		Joins("JOIN modules ON modules.id = module_lessons.module_id").
+		Where("modules.course_id = ? AND lessons.status = ?", courseID, entities.LessonPublished).
+		Order("modules.position, module_lessons.position").
+		Find(&courseLessons)
+	return courseLessons, result.Error
+}
+
+func (r *CourseRepository) GetCourseStudentIDs(courseID uint) ([]uint, error) {
+	var studentIDs []uint
+	result := common.DB.Model(&entities.CourseEnrollment{}).
+		Where("course_id = ?", courseID).
+		Pluck("student_id", &studentIDs)
+	return studentIDs, result.Error
+}
+
+// EnrollStudentInCourse records the course enrollment and enrolls the student in the given lessons of the course.
+// Lessons that are already full are skipped and reported instead of being overfilled
+func (r *CourseRepository) EnrollStudentInCourse(courseID uint, studentID uint, courseLessons []entities.Lesson) (*models.CourseEnrollmentResult, error) {
+	result := &models.CourseEnrollmentResult{
+		CourseID:          courseID,
+		StudentID:         studentID,
+		EnrolledLessonIDs: []uint{},
+		Skipped:           []models.SkippedCourseLesson{},
+	}
+
+	err := common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entities.Student{}, studentID).Error; err != nil {
+			return err
+		}
+
+		courseEnrollment := &entities.CourseEnrollment{CourseID: courseID, StudentID: studentID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(courseEnrollment).Error; err != nil {
+			return err
+		}
+
+		for _, lesson := range courseLessons {
+			err := lessons.EnrollInTransaction(tx, lesson.ID, studentID)
+			if errors.Is(err, lessons.ErrLessonFull) {
+				result.Skipped = append(result.Skipped, models.SkippedCourseLesson{
+					LessonID: lesson.ID,
+					Title:    lesson.Title,
+					Reason:   SkipLessonFull,
+				})
+				continue
+			}
+			if err != nil {
+				return &LessonEnrollmentError{LessonID: lesson.ID, StudentID: studentID, Err: err}
+			}
+			result.EnrolledLessonIDs = append(result.EnrolledLessonIDs, lesson.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *CourseRepository) GetCoursesByStudentID(studentID uint) ([]*entities.Course, error) {
+	var courses []*entities.Course
+	result := common.DB.
+		Where("id IN (?)", common.DB.Model(&entities.CourseEnrollment{}).Select("course_id").Where("student_id = ?", studentID)).
+		Preload("Modules", orderByPosition).
+		Preload("Modules.Lessons", orderByPosition).
+		Preload("Modules.Lessons.Lesson").
+		Order("title").
+		Find(&courses)
+	return courses, result.Error
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
@@ -0,0 +1,47 @@
+package courses
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *CourseHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Public endpoints (no auth required)
+	router.HandleFunc("/api/courses", handler.List).Methods(http.MethodGet)
+	router.HandleFunc("/api/courses/{courseID:[0-9]+}", handler.Get).Methods(http.MethodGet)
+	router.HandleFunc("/api/courses/{courseID:[0-9]+}/lessons", handler.ListLessons).Methods(http.MethodGet)
+
+	// Admin-only endpoints
+	adminRoutes := router.PathPrefix("/api/courses").Subrouter()
+	adminRoutes.Use(authMiddleware)
+	adminRoutes.Use(middleware.RequireRole("admin"))
+	adminRoutes.HandleFunc("", handler.Create).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/enroll-student", handler.EnrollStudent).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules", handler.CreateModule).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules/order", handler.ReorderModules).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules/{moduleID:[0-9]+}", handler.UpdateModule).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules/{moduleID:[0-9]+}", handler.DeleteModule).Methods(http.MethodDelete)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules/{moduleID:[0-9]+}/lessons", handler.AddLesson).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules/{moduleID:[0-9]+}/lessons/order", handler.ReorderLessons).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{courseID:[0-9]+}/modules/{moduleID:[0-9]+}/lessons/{lessonID:[0-9]+}", handler.RemoveLesson).Methods(http.MethodDelete)
+
+	// Admin view including unpublished lessons
+	adminCourseRoutes := router.PathPrefix("/api/admin/courses").Subrouter()
+	adminCourseRoutes.Use(authMiddleware)
+	adminCourseRoutes.Use(middleware.RequireRole("admin"))
+	adminCourseRoutes.HandleFunc("/{courseID:[0-9]+}", handler.GetAny).Methods(http.MethodGet)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/courses", handler.GetStudentCourses).Methods(http.MethodGet)
+}
@@ -0,0 +1,367 @@
+package courses
+
+import (
+	"errors"
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+)
+
+var (
+	ErrModuleNotInCourse = errors.New("module does not belong to this course")
+	ErrLessonInModule    = errors.New("lesson already belongs to a module")
+	ErrInvalidOrder      = errors.New("order must list every item exactly once")
+)
+
+// Reasons a course lesson is skipped when a student enrolls in the course
+const (
+	SkipLessonFull    = "lesson_full"
+	SkipPrerequisites = "prerequisites_not_met"
+)
+
+// LessonEnrollmentError names the course lesson a student could not be enrolled in
+type LessonEnrollmentError struct {
+	LessonID  uint
+	StudentID uint
+	Err       error
+}
+
+func (e *LessonEnrollmentError) Error() string {
+	return fmt.Sprintf("student %d could not be enrolled in lesson %d: %v", e.StudentID, e.LessonID, e.Err)
+}
+
+func (e *LessonEnrollmentError) Unwrap() error {
+	return e.Err
+}
+
+type ICourseService interface {
+	GetCourse(id uint64) (*entities.Course, error)
+	GetPublishedCourse(id uint64) (*entities.Course, error)
+	GetAllCourses() ([]*entities.Course, error)
+	CreateCourse(request *models.CreateCourseRequest) (*entities.Course, error)
+	UpdateCourse(request *models.PatchCourseRequest, id uint64) (*entities.Course, error)
+	DeleteCourse(id uint64) error
+	GetCourseLessons(id uint64) ([]models.CourseLesson, error)
+	CreateModule(courseID uint64, request *models.CreateModuleRequest) (*entities.Module, error)
+	UpdateModule(courseID uint64, moduleID uint64, request *models.PatchModuleRequest) (*entities.Module, error)
+	DeleteModule(courseID uint64, moduleID uint64) error
+	ReorderModules(courseID uint64, moduleIDs []uint) (*entities.Course, error)
+	AddLessonToModule(courseID uint64, moduleID uint64, lessonID uint) (*models.ModuleLessonResult, error)
+	RemoveLessonFromModule(courseID uint64, moduleID uint64, lessonID uint) error
+	ReorderModuleLessons(courseID uint64, moduleID uint64, lessonIDs []uint) (*entities.Course, error)
+	EnrollStudent(courseID uint64, studentID uint) (*models.CourseEnrollmentResult, error)
+	GetStudentCourses(studentID uint) ([]*entities.Course, error)
+}
+
+type CourseService struct {
+	repo          ICourseRepository
+	lessonService lessons.ILessonService
+}
+
+func NewCourseService(repo ICourseRepository, lessonService lessons.ILessonService) ICourseService {
+	return &CourseService{
+		repo:          repo,
+		lessonService: lessonService,
+	}
+}
+
+func (s *CourseService) GetCourse(id uint64) (*entities.Course, error) {
+	course, err := s.repo.GetCourse(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &course, nil
+}
+
+// GetPublishedCourse returns the course with unpublished lessons left out
+func (s *CourseService) GetPublishedCourse(id uint64) (*entities.Course, error) {
+	course, err := s.GetCourse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	onlyPublishedLessons(course)
+	return course, nil
+}
+
+func (s *CourseService) GetAllCourses() ([]*entities.Course, error) {
+	return s.repo.GetAllCourses()
+}
+
+func (s *CourseService) CreateCourse(request *models.CreateCourseRequest) (*entities.Course, error) {
+	course := &entities.Course{
+		Title:       request.Title,
+		Description: request.Description,
+	}
+
+	if err := s.repo.CreateCourse(course); err != nil {
+		return nil, err
+	}
+
+	return course, nil
+}
+
+func (s *CourseService) UpdateCourse(request *models.PatchCourseRequest, id uint64) (*entities.Course, error) {
+	course, err := s.repo.GetCourse(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Title != nil {
+		course.Title = *request.Title
+	}
+	if request.Description != nil {
+		course.Description = *request.Description
+	}
+
+	if err := s.repo.UpdateCourse(&course); err != nil {
+		return nil, err
+	}
+
+	return &course, nil
+}
+
+func (s *CourseService) DeleteCourse(id uint64) error {
+	return s.repo.DeleteCourse(uint(id))
+}
+
+// GetCourseLessons flattens the course into its published lessons in curriculum order
+func (s *CourseService) GetCourseLessons(id uint64) ([]models.CourseLesson, error) {
+	course, err := s.GetPublishedCourse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons := []models.CourseLesson{}
+	for _, module := range course.Modules {
+		for _, moduleLesson := range module.Lessons {
+			lessons = append(lessons, models.CourseLesson{
+				ModuleID:       module.ID,
+				ModuleTitle:    module.Title,
+				ModulePosition: module.Position,
+				Position:       moduleLesson.Position,
+				Lesson:         moduleLesson.Lesson,
+			})
+		}
+	}
+
+	return lessons, nil
+}
+
+func (s *CourseService) CreateModule(courseID uint64, request *models.CreateModuleRequest) (*entities.Module, error) {
+	course, err := s.repo.GetCourse(uint(courseID))
+	if err != nil {
+		return nil, err
+	}
+
+	module := &entities.Module{
+		CourseID: course.ID,
+		Title:    request.Title,
+	}
+	if err := s.repo.CreateModule(module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func (s *CourseService) UpdateModule(courseID uint64, moduleID uint64, request *models.PatchModuleRequest) (*entities.Module, error) {
+	module, err := s.getCourseModule(courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Title != nil {
+		module.Title = *request.Title
+	}
+
+	if err := s.repo.UpdateModule(module); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+func (s *CourseService) DeleteModule(courseID uint64, moduleID uint64) error {
+	module, err := s.getCourseModule(courseID, moduleID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteModule(module.ID)
+}
+
+func (s *CourseService) ReorderModules(courseID uint64, moduleIDs []uint) (*entities.Course, error) {
+	if err := s.repo.ReorderModules(uint(courseID), moduleIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetCourse(courseID)
+}
+
+func (s *CourseService) AddLessonToModule(courseID uint64, moduleID uint64, lessonID uint) (*models.ModuleLessonResult, error) {
+	module, err := s.getCourseModule(courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.lessonService.GetLesson(uint64(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Course students join a published lesson now and any other lesson when the lessons module
+	// publishes it, in both cases only if they meet its prerequisites
+	var studentIDs []uint
+	unqualified := []models.SkippedCourseStudent{}
+	if lesson.Status == entities.LessonPublished {
+		courseStudentIDs, err := s.repo.GetCourseStudentIDs(module.CourseID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, studentID := range courseStudentIDs {
+			unmet, err := s.unmetPrerequisites(lesson.ID, studentID)
+			if err != nil {
+				return nil, err
+			}
+			if len(unmet) > 0 {
+				unqualified = append(unqualified, models.SkippedCourseStudent{StudentID: studentID, Reason: SkipPrerequisites})
+				continue
+			}
+			studentIDs = append(studentIDs, studentID)
+		}
+	}
+
+	skipped, err := s.repo.AddLessonToModule(module, lessonID, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	skipped = append(unqualified, skipped...)
+
+	course, err := s.GetCourse(courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ModuleLessonResult{Course: course, Skipped: skipped}, nil
+}
+
+func (s *CourseService) RemoveLessonFromModule(courseID uint64, moduleID uint64, lessonID uint) error {
+	module, err := s.getCourseModule(courseID, moduleID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RemoveLessonFromModule(module.ID, lessonID)
+}
+
+func (s *CourseService) ReorderModuleLessons(courseID uint64, moduleID uint64, lessonIDs []uint) (*entities.Course, error) {
+	module, err := s.getCourseModule(courseID, moduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderModuleLessons(module.ID, lessonIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetCourse(courseID)
+}
+
+// EnrollStudent enrolls the student in the course and every published lesson of it they are eligible for.
+// Lessons whose prerequisites they don't meet are skipped and reported
+func (s *CourseService) EnrollStudent(courseID uint64, studentID uint) (*models.CourseEnrollmentResult, error) {
+	course, err := s.repo.GetCourse(uint(courseID))
+	if err != nil {
+		return nil, err
+	}
+
+	courseLessons, err := s.repo.GetPublishedCourseLessons(course.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var eligible []entities.Lesson
+	skipped := []models.SkippedCourseLesson{}
+	for _, lesson := range courseLessons {
+		unmet, err := s.unmetPrerequisites(lesson.ID, studentID)
+		if err != nil {
+			return nil, err
+		}
+		if len(unmet) > 0 {
+			skipped = append(skipped, models.SkippedCourseLesson{
+				LessonID:           lesson.ID,
+				Title:              lesson.Title,
+				Reason:             SkipPrerequisites,
+				UnmetPrerequisites: unmet,
+			})
+			continue
+		}
+		eligible = append(eligible, lesson)
+	}
+
+	result, err := s.repo.EnrollStudentInCourse(course.ID, studentID, eligible)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Skipped = append(skipped, result.Skipped...)
+	return result, nil
+}
+
+// unmetPrerequisites runs the lesson's eligibility check for the student. Students already
+// enrolled in the lesson keep their place, so nothing is reported for them
+func (s *CourseService) unmetPrerequisites(lessonID uint, studentID uint) ([]models.UnmetPrerequisite, error) {
+	enrolled, err := s.lessonService.IsStudentEnrolled(uint64(lessonID), studentID)
+	if err != nil || enrolled {
+		return nil, err
+	}
+
+	eligibility, err := s.lessonService.CheckEligibility(uint64(lessonID), studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return eligibility.UnmetPrerequisites, nil
+}
+
+func (s *CourseService) GetStudentCourses(studentID uint) ([]*entities.Course, error) {
+	courses, err := s.repo.GetCoursesByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, course := range courses {
+		onlyPublishedLessons(course)
+	}
+	return courses, nil
+}
+
+func (s *CourseService) getCourseModule(courseID uint64, moduleID uint64) (*entities.Module, error) {
+	module, err := s.repo.GetModule(uint(moduleID))
+	if err != nil {
+		return nil, err
+	}
+
+	if module.CourseID != uint(courseID) {
+		return nil, ErrModuleNotInCourse
+	}
+
+	return &module, nil
+}
+
+// onlyPublishedLessons drops deleted and unpublished lessons from the course tree
+func onlyPublishedLessons(course *entities.Course) {
+	for i := range course.Modules {
+		lessons := course.Modules[i].Lessons[:0]
+		for _, moduleLesson := range course.Modules[i].Lessons {
+			if moduleLesson.Lesson != nil && moduleLesson.Lesson.Status == entities.LessonPublished {
+				lessons = append(lessons, moduleLesson)
+			}
+		}
+		course.Modules[i].Lessons = lessons
+	}
+}
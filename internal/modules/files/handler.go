@@ -0,0 +1,45 @@
+package files
+
+import (
+	"errors"
+	"fmt"
+	"lesson-management/pkg/storage"
+	"net/http"
+	"time"
+)
+
+type FileHandler struct {
+	uploads *storage.Uploads
+}
+
+func NewFileHandler(uploads *storage.Uploads) *FileHandler {
+	return &FileHandler{
+		uploads: uploads,
+	}
+}
+
+// Download serves a file through a signed, time-limited link. The link itself is the
+// credential, so no login is needed
+func (h *FileHandler) Download(w http.ResponseWriter, r *http.Request) {
+	file, err := h.uploads.Signer.Verify(r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, "Download link is invalid or has expired", http.StatusForbidden)
+		return
+	}
+
+	object, err := h.uploads.Storage.Open(file.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to open file", http.StatusInternalServerError)
+		fmt.Println("Error while opening file: ", err)
+		return
+	}
+	defer object.Close()
+
+	// Links expire anyway, so shared caches must not keep the file beyond that
+	w.Header().Set("Cache-Control", "private, no-store")
+	storage.Serve(w, r, object, file.FileName, file.ContentType, time.Time{}, "")
+}
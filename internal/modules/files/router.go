@@ -0,0 +1,13 @@
+package files
+
+import (
+	"lesson-management/pkg/storage"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *FileHandler) {
+	// Public endpoint, authorized by the signature in the link
+	router.HandleFunc(storage.DownloadPath, handler.Download).Methods(http.MethodGet)
+}
@@ -0,0 +1,534 @@
+package gradebook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type GradebookHandler struct {
+	service IGradebookService
+}
+
+func NewGradebookHandler(service IGradebookService) *GradebookHandler {
+	return &GradebookHandler{
+		service: service,
+	}
+}
+
+// Teacher handlers
+func (h *GradebookHandler) GetGradebook(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	gradebook, err := h.service.GetGradebook(lessonID, teacherID)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch gradebook", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching gradebook: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(gradebook)
+}
+
+func (h *GradebookHandler) SetLessonScale(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.SetLessonGradingScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetLessonScale(lessonID, teacherID, requestBody.GradingScaleID); err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to set grading scale", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while setting grading scale: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GradebookHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.GradeCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.CreateCategory(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create grade category", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating grade category: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(category)
+}
+
+func (h *GradebookHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	categoryID, ok := pathID(w, r, "categoryID", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.GradeCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	category, err := h.service.UpdateCategory(lessonID, categoryID, teacherID, &requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update grade category", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating grade category: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(category)
+}
+
+func (h *GradebookHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	categoryID, ok := pathID(w, r, "categoryID", "Invalid category ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteCategory(lessonID, categoryID, teacherID); err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete grade category", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting grade category: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GradebookHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.CreateGradeItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.CreateItem(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create grade item", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating grade item: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *GradebookHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	itemID, ok := pathID(w, r, "itemID", "Invalid item ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.PatchGradeItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.service.UpdateItem(lessonID, itemID, teacherID, &requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update grade item", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating grade item: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *GradebookHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	itemID, ok := pathID(w, r, "itemID", "Invalid item ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteItem(lessonID, itemID, teacherID); err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete grade item", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting grade item: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *GradebookHandler) SetGrades(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	itemID, ok := pathID(w, r, "itemID", "Invalid item ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.SetGradesRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	grades, err := h.service.SetGrades(lessonID, itemID, teacherID, &requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to save grades", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while saving grades: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(grades)
+}
+
+func (h *GradebookHandler) DeleteGrade(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	itemID, ok := pathID(w, r, "itemID", "Invalid item ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := pathID(w, r, "studentID", "Invalid student ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteGrade(lessonID, itemID, uint(studentID), teacherID); err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete grade", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting grade: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Student handlers
+func (h *GradebookHandler) GetStudentGrades(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	summary, err := h.service.GetStudentGrades(lessonID, studentID)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch student grades", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student grades: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(summary)
+}
+
+// Grading scale handlers
+func (h *GradebookHandler) ListScales(w http.ResponseWriter, r *http.Request) {
+	scales, err := h.service.GetScales()
+	if err != nil {
+		http.Error(w, "Failed to fetch grading scales", http.StatusInternalServerError)
+		fmt.Println("Error while fetching grading scales: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(scales)
+}
+
+func (h *GradebookHandler) GetScale(w http.ResponseWriter, r *http.Request) {
+	scaleID, ok := pathID(w, r, "scaleID", "Invalid grading scale ID")
+	if !ok {
+		return
+	}
+
+	scale, err := h.service.GetScale(scaleID)
+	if err != nil {
+		http.Error(w, "Grading scale not found", http.StatusNotFound)
+		fmt.Println("Error while fetching grading scale: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(scale)
+}
+
+func (h *GradebookHandler) CreateScale(w http.ResponseWriter, r *http.Request) {
+	var requestBody models.GradingScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	scale, err := h.service.CreateScale(&requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create grading scale", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating grading scale: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(scale)
+}
+
+func (h *GradebookHandler) UpdateScale(w http.ResponseWriter, r *http.Request) {
+	scaleID, ok := pathID(w, r, "scaleID", "Invalid grading scale ID")
+	if !ok {
+		return
+	}
+
+	var requestBody models.GradingScaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	scale, err := h.service.UpdateScale(scaleID, &requestBody)
+	if err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update grading scale", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating grading scale: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(scale)
+}
+
+func (h *GradebookHandler) DeleteScale(w http.ResponseWriter, r *http.Request) {
+	scaleID, ok := pathID(w, r, "scaleID", "Invalid grading scale ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteScale(scaleID); err != nil {
+		if status := gradebookErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete grading scale", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting grading scale: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathID parses a numeric route variable, answering 400 with message when it is invalid
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		http.Error(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// gradebookErrorStatus maps service errors to HTTP status codes
+func gradebookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher),
+		errors.Is(err, lessons.ErrNotLessonStudent):
+		return http.StatusForbidden
+	case errors.Is(err, ErrCategoryNameRequired),
+		errors.Is(err, ErrInvalidWeight),
+		errors.Is(err, ErrItemTitleRequired),
+		errors.Is(err, ErrInvalidMaxPoints),
+		errors.Is(err, ErrNoGrades),
+		errors.Is(err, ErrInvalidPoints),
+		errors.Is(err, ErrDuplicateStudent),
+		errors.Is(err, ErrStudentNotEnrolled),
+		errors.Is(err, ErrScaleNameRequired),
+		errors.Is(err, ErrInvalidScaleBands):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrCategoryInUse),
+		errors.Is(err, ErrScaleInUse),
+		errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
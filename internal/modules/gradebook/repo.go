@@ -0,0 +1,242 @@
+package gradebook
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IGradebookRepository interface {
+	GetCategories(lessonID uint) ([]entities.GradeCategory, error)
+	GetCategory(id uint) (entities.GradeCategory, error)
+	CreateCategory(category *entities.GradeCategory) error
+	UpdateCategory(category *entities.GradeCategory) error
+	DeleteCategory(id uint) error
+	GetItems(lessonID uint) ([]entities.GradeItem, error)
+	GetItem(id uint) (entities.GradeItem, error)
+	CreateItem(item *entities.GradeItem) error
+	UpdateItem(item *entities.GradeItem) error
+	DeleteItem(id uint) error
+	GetGrades(lessonID uint, studentID *uint) ([]entities.Grade, error)
+	SaveGrades(grades []entities.Grade) error
+	DeleteGrade(itemID uint, studentID uint) error
+	GetScales() ([]entities.GradingScale, error)
+	GetScale(id uint) (entities.GradingScale, error)
+	CreateScale(scale *entities.GradingScale) error
+	UpdateScale(scale *entities.GradingScale) error
+	DeleteScale(id uint) error
+	SetLessonScale(lessonID uint, scaleID *uint) error
+}
+
+type GradebookRepository struct{}
+
+func NewGradebookRepository() IGradebookRepository {
+	return &GradebookRepository{}
+}
+
+func (r *GradebookRepository) GetCategories(lessonID uint) ([]entities.GradeCategory, error) {
+	var categories []entities.GradeCategory
+	result := common.DB.Where("lesson_id = ?", lessonID).Order("name, id").Find(&categories)
+	return categories, result.Error
+}
+
+func (r *GradebookRepository) GetCategory(id uint) (entities.GradeCategory, error) {
+	var category entities.GradeCategory
+	result := common.DB.First(&category, id)
+	return category, result.Error
+}
+
+func (r *GradebookRepository) CreateCategory(category *entities.GradeCategory) error {
+	return common.DB.Create(category).Error
+}
+
+func (r *GradebookRepository) UpdateCategory(category *entities.GradeCategory) error {
+	result := common.DB.Save(category)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteCategory refuses to delete a category that still has grade items
+func (r *GradebookRepository) DeleteCategory(id uint) error {
+	var count int64
+	if err := common.DB.Model(&entities.GradeItem{}).Where("category_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCategoryInUse
+	}
+
+	return common.DB.Delete(&entities.GradeCategory{}, id).Error
+}
+
+func (r *GradebookRepository) GetItems(lessonID uint) ([]entities.GradeItem, error) {
+	var items []entities.GradeItem
+	result := common.DB.Where("lesson_id = ?", lessonID).Order("due_at NULLS LAST, id").Find(&items)
+	return items, result.Error
+}
+
+func (r *GradebookRepository) GetItem(id uint) (entities.GradeItem, error) {
+	var item entities.GradeItem
+	result := common.DB.First(&item, id)
+	return item, result.Error
+}
+
+func (r *GradebookRepository) CreateItem(item *entities.GradeItem) error {
+	return common.DB.Omit(clause.Associations).Create(item).Error
+}
+
+func (r *GradebookRepository) UpdateItem(item *entities.GradeItem) error {
+	result := common.DB.Omit(clause.Associations).Save(item)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteItem removes the item together with every grade given for it, unlinking any quiz or assignment that scored into it
+func (r *GradebookRepository) DeleteItem(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("grade_item_id = ?", id).Delete(&entities.Grade{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Quiz{}).Where("grade_item_id = ?", id).Update("grade_item_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&entities.Assignment{}).Where("grade_item_id = ?", id).Update("grade_item_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.GradeItem{}, id).Error
+	})
+}
+
+func (r *GradebookRepository) GetGrades(lessonID uint, studentID *uint) ([]entities.Grade, error) {
+	query := common.DB.Where("lesson_id = ?", lessonID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	}
+
+	var grades []entities.Grade
+	result := query.Order("grade_item_id, student_id").Find(&grades)
+	return grades, result.Error
+}
+
+// SaveGrades inserts the grades, replacing any earlier grade for the same item and student
+func (r *GradebookRepository) SaveGrades(grades []entities.Grade) error {
+	if len(grades) == 0 {
+		return nil
+	}
+	return common.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "grade_item_id"}, {Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"points", "excused", "feedback", "graded_by", "updated_at"}),
+	}).Create(&grades).Error
+}
+
+func (r *GradebookRepository) DeleteGrade(itemID uint, studentID uint) error {
+	result := common.DB.Where("grade_item_id = ? AND student_id = ?", itemID, studentID).Delete(&entities.Grade{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *GradebookRepository) GetScales() ([]entities.GradingScale, error) {
+	var scales []entities.GradingScale
+	result := common.DB.Preload("Bands", orderBands).Order("name").Find(&scales)
+	return scales, result.Error
+}
+
+func (r *GradebookRepository) GetScale(id uint) (entities.GradingScale, error) {
+	var scale entities.GradingScale
+	result := common.DB.Preload("Bands", orderBands).First(&scale, id)
+	return scale, result.Error
+}
+
+func (r *GradebookRepository) CreateScale(scale *entities.GradingScale) error {
+	return common.DB.Create(scale).Error
+}
+
+// UpdateScale renames the scale and replaces its bands in one transaction
+func (r *GradebookRepository) UpdateScale(scale *entities.GradingScale) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Save(scale)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no rows affected")
+		}
+
+		if err := tx.Where("grading_scale_id = ?", scale.ID).Delete(&entities.GradingScaleBand{}).Error; err != nil {
+			return err
+		}
+		for i := range scale.Bands {
+			scale.Bands[i].ID = 0
+			scale.Bands[i].GradingScaleID = scale.ID
+		}
+		return tx.Create(&scale.Bands).Error
+	})
+}
+
+// DeleteScale refuses to delete a scale that lessons still use
+func (r *GradebookRepository) DeleteScale(id uint) error {
+	var count int64
+	if err := common.DB.Unscoped().Model(&entities.Lesson{}).Where("grading_scale_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrScaleInUse
+	}
+
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("grading_scale_id = ?", id).Delete(&entities.GradingScaleBand{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.GradingScale{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *GradebookRepository) SetLessonScale(lessonID uint, scaleID *uint) error {
+	result := common.DB.Model(&entities.Lesson{}).Where("id = ?", lessonID).Update("grading_scale_id", scaleID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func orderBands(db *gorm.DB) *gorm.DB {
+	return db.Order("min_percent DESC")
+}
@@ -0,0 +1,47 @@
+package gradebook
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *GradebookHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Public endpoints (no auth required)
+	router.HandleFunc("/api/grading-scales", handler.ListScales).Methods(http.MethodGet)
+	router.HandleFunc("/api/grading-scales/{scaleID:[0-9]+}", handler.GetScale).Methods(http.MethodGet)
+
+	// Admin-only endpoints
+	adminRoutes := router.PathPrefix("/api/grading-scales").Subrouter()
+	adminRoutes.Use(authMiddleware)
+	adminRoutes.Use(middleware.RequireRole("admin"))
+	adminRoutes.HandleFunc("", handler.CreateScale).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{scaleID:[0-9]+}", handler.UpdateScale).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{scaleID:[0-9]+}", handler.DeleteScale).Methods(http.MethodDelete)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/gradebook").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.GetGradebook).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/scale", handler.SetLessonScale).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/categories", handler.CreateCategory).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/categories/{categoryID:[0-9]+}", handler.UpdateCategory).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/categories/{categoryID:[0-9]+}", handler.DeleteCategory).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/items", handler.CreateItem).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/items/{itemID:[0-9]+}", handler.UpdateItem).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/items/{itemID:[0-9]+}", handler.DeleteItem).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/items/{itemID:[0-9]+}/grades", handler.SetGrades).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/items/{itemID:[0-9]+}/grades/{studentID:[0-9]+}", handler.DeleteGrade).Methods(http.MethodDelete)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/grades", handler.GetStudentGrades).Methods(http.MethodGet)
+}
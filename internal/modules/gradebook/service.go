@@ -0,0 +1,492 @@
+package gradebook
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNameRequired = errors.New("category name is required")
+	ErrInvalidWeight        = errors.New("category weight must not be negative")
+	ErrCategoryInUse        = errors.New("category still has grade items")
+	ErrItemTitleRequired    = errors.New("grade item title is required")
+	ErrInvalidMaxPoints     = errors.New("max points must be greater than zero")
+	ErrNoGrades             = errors.New("at least one grade is required")
+	ErrInvalidPoints        = errors.New("points must not be negative")
+	ErrDuplicateStudent     = errors.New("each student may appear only once")
+	ErrStudentNotEnrolled   = errors.New("student is not enrolled in this lesson")
+	ErrScaleNameRequired    = errors.New("grading scale name is required")
+	ErrInvalidScaleBands    = errors.New("grading scale needs bands with a letter and a distinct minimum between 0 and 100")
+	ErrScaleInUse           = errors.New("grading scale is used by lessons")
+)
+
+type IGradebookService interface {
+	GetGradebook(lessonID uint64, teacherID uint) (*models.GradebookResponse, error)
+	GetStudentGrades(lessonID uint64, studentID uint) (*models.StudentGradeSummary, error)
+	CreateCategory(lessonID uint64, teacherID uint, request *models.GradeCategoryRequest) (*entities.GradeCategory, error)
+	UpdateCategory(lessonID uint64, categoryID uint64, teacherID uint, request *models.GradeCategoryRequest) (*entities.GradeCategory, error)
+	DeleteCategory(lessonID uint64, categoryID uint64, teacherID uint) error
+	CreateItem(lessonID uint64, teacherID uint, request *models.CreateGradeItemRequest) (*entities.GradeItem, error)
+	UpdateItem(lessonID uint64, itemID uint64, teacherID uint, request *models.PatchGradeItemRequest) (*entities.GradeItem, error)
+	DeleteItem(lessonID uint64, itemID uint64, teacherID uint) error
+	SetGrades(lessonID uint64, itemID uint64, teacherID uint, request *models.SetGradesRequest) ([]entities.Grade, error)
+	DeleteGrade(lessonID uint64, itemID uint64, studentID uint, teacherID uint) error
+	SetLessonScale(lessonID uint64, teacherID uint, scaleID *uint) error
+	GetScales() ([]entities.GradingScale, error)
+	GetScale(id uint64) (*entities.GradingScale, error)
+	CreateScale(request *models.GradingScaleRequest) (*entities.GradingScale, error)
+	UpdateScale(id uint64, request *models.GradingScaleRequest) (*entities.GradingScale, error)
+	DeleteScale(id uint64) error
+	GetLessonItem(lessonID uint, itemID uint) (*entities.GradeItem, error)
+}
+
+type GradebookService struct {
+	repo          IGradebookRepository
+	lessonService lessons.ILessonService
+}
+
+func NewGradebookService(repo IGradebookRepository, lessonService lessons.ILessonService) IGradebookService {
+	return &GradebookService{
+		repo:          repo,
+		lessonService: lessonService,
+	}
+}
+
+// GetGradebook returns the lesson's categories and items with every enrolled student's grades and totals
+func (s *GradebookService) GetGradebook(lessonID uint64, teacherID uint) (*models.GradebookResponse, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, items, scale, err := s.lessonSetup(lesson)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(lesson.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := s.repo.GetGrades(lesson.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint][]entities.Grade, len(students))
+	for _, grade := range grades {
+		byStudent[grade.StudentID] = append(byStudent[grade.StudentID], grade)
+	}
+
+	response := &models.GradebookResponse{
+		LessonID:     lesson.ID,
+		GradingScale: scale,
+		Categories:   categories,
+		Items:        items,
+		Students:     make([]models.StudentGradeSummary, 0, len(students)),
+	}
+	for _, student := range students {
+		response.Students = append(response.Students, summarize(student, categories, items, byStudent[student.ID], scale))
+	}
+
+	return response, nil
+}
+
+// GetStudentGrades returns a student's own grades in a lesson they are enrolled in
+func (s *GradebookService) GetStudentGrades(lessonID uint64, studentID uint) (*models.StudentGradeSummary, error) {
+	lesson, err := s.lessonService.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(lesson.ID))
+	if err != nil {
+		return nil, err
+	}
+	var student *entities.Student
+	for i := range students {
+		if students[i].ID == studentID {
+			student = &students[i]
+			break
+		}
+	}
+	if student == nil {
+		return nil, lessons.ErrNotLessonStudent
+	}
+
+	categories, items, scale, err := s.lessonSetup(lesson)
+	if err != nil {
+		return nil, err
+	}
+
+	grades, err := s.repo.GetGrades(lesson.ID, &studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarize(*student, categories, items, grades, scale)
+	return &summary, nil
+}
+
+func (s *GradebookService) CreateCategory(lessonID uint64, teacherID uint, request *models.GradeCategoryRequest) (*entities.GradeCategory, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	category := &entities.GradeCategory{LessonID: lesson.ID}
+	if err := applyCategory(category, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateCategory(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *GradebookService) UpdateCategory(lessonID uint64, categoryID uint64, teacherID uint, request *models.GradeCategoryRequest) (*entities.GradeCategory, error) {
+	category, err := s.lessonCategory(lessonID, categoryID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyCategory(category, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateCategory(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *GradebookService) DeleteCategory(lessonID uint64, categoryID uint64, teacherID uint) error {
+	category, err := s.lessonCategory(lessonID, categoryID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteCategory(category.ID)
+}
+
+func (s *GradebookService) CreateItem(lessonID uint64, teacherID uint, request *models.CreateGradeItemRequest) (*entities.GradeItem, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &entities.GradeItem{
+		LessonID:   lesson.ID,
+		CategoryID: request.CategoryID,
+		Title:      strings.TrimSpace(request.Title),
+		MaxPoints:  request.MaxPoints,
+		DueAt:      request.DueAt,
+	}
+	if err := s.validateItem(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateItem(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *GradebookService) UpdateItem(lessonID uint64, itemID uint64, teacherID uint, request *models.PatchGradeItemRequest) (*entities.GradeItem, error) {
+	item, err := s.lessonItem(lessonID, itemID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.CategoryID != nil {
+		item.CategoryID = *request.CategoryID
+	}
+	if request.Title != nil {
+		item.Title = strings.TrimSpace(*request.Title)
+	}
+	if request.MaxPoints != nil {
+		item.MaxPoints = *request.MaxPoints
+	}
+	if request.DueAt != nil {
+		item.DueAt = request.DueAt
+	}
+	if err := s.validateItem(item); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateItem(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *GradebookService) DeleteItem(lessonID uint64, itemID uint64, teacherID uint) error {
+	item, err := s.lessonItem(lessonID, itemID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteItem(item.ID)
+}
+
+// SetGrades records grades for the listed students, replacing earlier grades for the item
+func (s *GradebookService) SetGrades(lessonID uint64, itemID uint64, teacherID uint, request *models.SetGradesRequest) ([]entities.Grade, error) {
+	if len(request.Grades) == 0 {
+		return nil, ErrNoGrades
+	}
+
+	item, err := s.lessonItem(lessonID, itemID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(item.LessonID))
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[uint]bool, len(students))
+	for _, student := range students {
+		enrolled[student.ID] = true
+	}
+
+	grades := make([]entities.Grade, 0, len(request.Grades))
+	seen := make(map[uint]bool, len(request.Grades))
+	for _, entry := range request.Grades {
+		if seen[entry.StudentID] {
+			return nil, ErrDuplicateStudent
+		}
+		seen[entry.StudentID] = true
+		if !enrolled[entry.StudentID] {
+			return nil, ErrStudentNotEnrolled
+		}
+		if entry.Points < 0 {
+			return nil, ErrInvalidPoints
+		}
+
+		grades = append(grades, entities.Grade{
+			LessonID:    item.LessonID,
+			GradeItemID: item.ID,
+			StudentID:   entry.StudentID,
+			Points:      entry.Points,
+			Excused:     entry.Excused,
+			Feedback:    entry.Feedback,
+			GradedBy:    teacherID,
+		})
+	}
+
+	if err := s.repo.SaveGrades(grades); err != nil {
+		return nil, err
+	}
+
+	return grades, nil
+}
+
+func (s *GradebookService) DeleteGrade(lessonID uint64, itemID uint64, studentID uint, teacherID uint) error {
+	item, err := s.lessonItem(lessonID, itemID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteGrade(item.ID, studentID)
+}
+
+// SetLessonScale selects the grading scale used for the lesson's letter grades
+func (s *GradebookService) SetLessonScale(lessonID uint64, teacherID uint, scaleID *uint) error {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	if scaleID != nil {
+		if _, err := s.repo.GetScale(*scaleID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.SetLessonScale(lesson.ID, scaleID)
+}
+
+func (s *GradebookService) GetScales() ([]entities.GradingScale, error) {
+	return s.repo.GetScales()
+}
+
+func (s *GradebookService) GetScale(id uint64) (*entities.GradingScale, error) {
+	scale, err := s.repo.GetScale(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &scale, nil
+}
+
+func (s *GradebookService) CreateScale(request *models.GradingScaleRequest) (*entities.GradingScale, error) {
+	scale := &entities.GradingScale{}
+	if err := applyScale(scale, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateScale(scale); err != nil {
+		return nil, err
+	}
+
+	return scale, nil
+}
+
+func (s *GradebookService) UpdateScale(id uint64, request *models.GradingScaleRequest) (*entities.GradingScale, error) {
+	scale, err := s.repo.GetScale(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyScale(&scale, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateScale(&scale); err != nil {
+		return nil, err
+	}
+
+	return &scale, nil
+}
+
+func (s *GradebookService) DeleteScale(id uint64) error {
+	return s.repo.DeleteScale(uint(id))
+}
+
+// lessonSetup loads what every summary is computed from
+func (s *GradebookService) lessonSetup(lesson *entities.Lesson) ([]entities.GradeCategory, []entities.GradeItem, *entities.GradingScale, error) {
+	categories, err := s.repo.GetCategories(lesson.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	items, err := s.repo.GetItems(lesson.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var scale *entities.GradingScale
+	if lesson.GradingScaleID != nil {
+		loaded, err := s.repo.GetScale(*lesson.GradingScaleID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		scale = &loaded
+	}
+
+	return categories, items, scale, nil
+}
+
+// validateItem checks the item's fields and that its category belongs to the same lesson
+func (s *GradebookService) validateItem(item *entities.GradeItem) error {
+	if item.Title == "" {
+		return ErrItemTitleRequired
+	}
+	if item.MaxPoints <= 0 {
+		return ErrInvalidMaxPoints
+	}
+
+	category, err := s.repo.GetCategory(item.CategoryID)
+	if err != nil {
+		return err
+	}
+	if category.LessonID != item.LessonID {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// lessonCategory loads a category of an editable lesson, treating other lessons' categories as not found
+func (s *GradebookService) lessonCategory(lessonID uint64, categoryID uint64, teacherID uint) (*entities.GradeCategory, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.repo.GetCategory(uint(categoryID))
+	if err != nil {
+		return nil, err
+	}
+	if category.LessonID != lesson.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &category, nil
+}
+
+// GetLessonItem loads a grade item of the lesson, treating other lessons' items as not found.
+// Quizzes and assignments use it to check the item they score into
+func (s *GradebookService) GetLessonItem(lessonID uint, itemID uint) (*entities.GradeItem, error) {
+	item, err := s.repo.GetItem(itemID)
+	if err != nil {
+		return nil, err
+	}
+	if item.LessonID != lessonID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &item, nil
+}
+
+// lessonItem loads a grade item of an editable lesson, treating other lessons' items as not found
+func (s *GradebookService) lessonItem(lessonID uint64, itemID uint64, teacherID uint) (*entities.GradeItem, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetLessonItem(lesson.ID, uint(itemID))
+}
+
+func applyCategory(category *entities.GradeCategory, request *models.GradeCategoryRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return ErrCategoryNameRequired
+	}
+	if request.Weight < 0 {
+		return ErrInvalidWeight
+	}
+
+	category.Name = name
+	category.Weight = request.Weight
+	return nil
+}
+
+// applyScale validates the bands and stores them highest first, the order LetterFor expects
+func applyScale(scale *entities.GradingScale, request *models.GradingScaleRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return ErrScaleNameRequired
+	}
+	if len(request.Bands) == 0 {
+		return ErrInvalidScaleBands
+	}
+
+	bands := make([]entities.GradingScaleBand, 0, len(request.Bands))
+	seen := make(map[float64]bool, len(request.Bands))
+	for _, band := range request.Bands {
+		letter := strings.TrimSpace(band.Letter)
+		if letter == "" || band.MinPercent < 0 || band.MinPercent > 100 || seen[band.MinPercent] {
+			return ErrInvalidScaleBands
+		}
+		seen[band.MinPercent] = true
+		bands = append(bands, entities.GradingScaleBand{Letter: letter, MinPercent: band.MinPercent})
+	}
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinPercent > bands[j].MinPercent })
+
+	scale.Name = name
+	scale.Bands = bands
+	return nil
+}
@@ -0,0 +1,76 @@
+package gradebook
+
+import (
+	"lesson-management/entities"
+	"lesson-management/models"
+	"math"
+)
+
+// summarize computes a student's running totals. Each category scores earned points over
+// the points possible on its graded, non-excused items; the overall percentage is the
+// weighted average of the categories that have such items, so ungraded work neither
+// helps nor hurts until it is graded
+func summarize(student entities.Student, categories []entities.GradeCategory, items []entities.GradeItem, grades []entities.Grade, scale *entities.GradingScale) models.StudentGradeSummary {
+	itemsByID := make(map[uint]entities.GradeItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	earned := make(map[uint]float64, len(categories))
+	possible := make(map[uint]float64, len(categories))
+	for _, grade := range grades {
+		item, ok := itemsByID[grade.GradeItemID]
+		if !ok || grade.Excused {
+			continue
+		}
+		earned[item.CategoryID] += grade.Points
+		possible[item.CategoryID] += item.MaxPoints
+	}
+
+	summary := models.StudentGradeSummary{
+		StudentID:  student.ID,
+		Name:       student.Name,
+		Grades:     grades,
+		Categories: make([]models.CategoryTotal, 0, len(categories)),
+	}
+	if summary.Grades == nil {
+		summary.Grades = []entities.Grade{}
+	}
+
+	var weighted, weights float64
+	for _, category := range categories {
+		total := models.CategoryTotal{
+			CategoryID: category.ID,
+			Name:       category.Name,
+			Weight:     category.Weight,
+		}
+		if possible[category.ID] > 0 {
+			percent := earned[category.ID] / possible[category.ID] * 100
+			total.Percent = roundPercent(percent)
+			if category.Weight > 0 {
+				weighted += percent * category.Weight
+				weights += category.Weight
+			}
+		}
+		summary.Categories = append(summary.Categories, total)
+	}
+
+	if weights > 0 {
+		summary.Percent = roundPercent(weighted / weights)
+		if scale != nil {
+			summary.Letter = scale.LetterFor(*summary.Percent)
+		}
+	}
+
+	return summary
+}
+
+func roundPercent(percent float64) *float64 {
+	rounded := RoundPoints(percent)
+	return &rounded
+}
+
+// RoundPoints rounds to two decimals, the precision points and percentages are kept at
+func RoundPoints(points float64) float64 {
+	return math.Round(points*100) / 100
+}
@@ -0,0 +1,106 @@
+package gradebook
+
+import (
+	"lesson-management/entities"
+	"testing"
+)
+
+var (
+	testCategories = []entities.GradeCategory{
+		{ID: 1, Name: "Homework", Weight: 1},
+		{ID: 2, Name: "Exams", Weight: 3},
+		{ID: 3, Name: "Projects", Weight: 2},
+	}
+	testItems = []entities.GradeItem{
+		{ID: 10, CategoryID: 1, MaxPoints: 10},
+		{ID: 11, CategoryID: 1, MaxPoints: 10},
+		{ID: 20, CategoryID: 2, MaxPoints: 100},
+		{ID: 30, CategoryID: 3, MaxPoints: 50},
+	}
+	testScale = &entities.GradingScale{Bands: []entities.GradingScaleBand{
+		{Letter: "A", MinPercent: 90},
+		{Letter: "B", MinPercent: 80},
+		{Letter: "C", MinPercent: 70},
+	}}
+)
+
+func percent(p *float64) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func TestSummarize(t *testing.T) {
+	student := entities.Student{ID: 5, Name: "Ada"}
+	grades := []entities.Grade{
+		{GradeItemID: 10, Points: 10},
+		{GradeItemID: 11, Points: 6},
+		{GradeItemID: 20, Points: 70},
+		{GradeItemID: 30, Points: 0, Excused: true},
+		{GradeItemID: 99, Points: 100},
+	}
+
+	summary := summarize(student, testCategories, testItems, grades, testScale)
+
+	if summary.StudentID != 5 || summary.Name != "Ada" || len(summary.Grades) != len(grades) {
+		t.Errorf("summary = %+v, want the student and every grade", summary)
+	}
+
+	// Homework 16/20 = 80%, exams 70%, projects only excused so left out: (80*1 + 70*3) / 4
+	wantCategories := []any{80.0, 70.0, nil}
+	for i, category := range summary.Categories {
+		if got := percent(category.Percent); got != wantCategories[i] {
+			t.Errorf("category %s percent = %v, want %v", category.Name, got, wantCategories[i])
+		}
+	}
+	if got := percent(summary.Percent); got != 72.5 {
+		t.Errorf("overall percent = %v, want 72.5", got)
+	}
+	if summary.Letter != "C" {
+		t.Errorf("letter = %q, want C", summary.Letter)
+	}
+}
+
+func TestSummarizeNothingGraded(t *testing.T) {
+	summary := summarize(entities.Student{ID: 5}, testCategories, testItems, nil, testScale)
+
+	if summary.Grades == nil || len(summary.Grades) != 0 {
+		t.Errorf("grades = %#v, want an empty slice", summary.Grades)
+	}
+	if len(summary.Categories) != len(testCategories) {
+		t.Errorf("got %d categories, want %d", len(summary.Categories), len(testCategories))
+	}
+	if summary.Percent != nil || summary.Letter != "" {
+		t.Errorf("overall = %v %q, want no percent and no letter", percent(summary.Percent), summary.Letter)
+	}
+}
+
+func TestSummarizeSkipsUnweightedCategories(t *testing.T) {
+	categories := []entities.GradeCategory{
+		{ID: 1, Name: "Practice", Weight: 0},
+		{ID: 2, Name: "Exams", Weight: 1},
+	}
+	grades := []entities.Grade{
+		{GradeItemID: 10, Points: 2},
+		{GradeItemID: 20, Points: 95},
+	}
+
+	summary := summarize(entities.Student{}, categories, testItems, grades, nil)
+
+	if got := percent(summary.Categories[0].Percent); got != 20.0 {
+		t.Errorf("unweighted category percent = %v, want 20", got)
+	}
+	if got := percent(summary.Percent); got != 95.0 {
+		t.Errorf("overall percent = %v, want 95", got)
+	}
+	if summary.Letter != "" {
+		t.Errorf("letter = %q without a scale, want none", summary.Letter)
+	}
+}
+
+func TestRoundPercent(t *testing.T) {
+	if got := *roundPercent(200.0 / 3); got != 66.67 {
+		t.Errorf("roundPercent(66.666…) = %v, want 66.67", got)
+	}
+}
@@ -0,0 +1,176 @@
+package lessons
+
+import (
+	"errors"
+	"fmt"
+	"lesson-management/models"
+	"net/http"
+
+	"gorm.io/gorm"
+)
+
+// MaxBatchOperations caps the number of operations in a single batch request
+const MaxBatchOperations = 500
+
+const (
+	OpEnroll   = "enroll"
+	OpRemove   = "remove"
+	OpTransfer = "transfer"
+)
+
+// Per-operation outcomes reported in batch results
+const (
+	OperationSucceeded  = "succeeded"
+	OperationFailed     = "failed"
+	OperationRolledBack = "rolled_back"
+	OperationSkipped    = "skipped"
+)
+
+// BatchEnrollment applies a list of enroll, remove and transfer operations.
+// Atomic batches run in one transaction and stop at the first failure, undoing
+// every earlier operation; otherwise each operation succeeds or fails on its own.
+func (s *LessonService) BatchEnrollment(request *models.BatchEnrollmentRequest) (*models.BatchEnrollmentResponse, error) {
+	if len(request.Operations) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if len(request.Operations) > MaxBatchOperations {
+		return nil, ErrBatchTooLarge
+	}
+
+	response := &models.BatchEnrollmentResponse{
+		Atomic:  request.Atomic,
+		Results: make([]models.EnrollmentOperationResult, len(request.Operations)),
+	}
+	for i, operation := range request.Operations {
+		response.Results[i] = models.EnrollmentOperationResult{
+			Index:      i,
+			Op:         operation.Op,
+			StudentID:  operation.StudentID,
+			LessonID:   operation.LessonID,
+			ToLessonID: operation.ToLessonID,
+			Status:     OperationSkipped,
+		}
+	}
+
+	if request.Atomic {
+		applied, err := s.applyAtomicBatch(request.Operations, response.Results)
+		if err != nil {
+			return nil, err
+		}
+		response.Applied = applied
+	} else {
+		for i, operation := range request.Operations {
+			if err := applyOperation(s, operation); err != nil {
+				failOperation(&response.Results[i], err)
+				continue
+			}
+			response.Results[i].Status = OperationSucceeded
+		}
+	}
+
+	for _, result := range response.Results {
+		switch result.Status {
+		case OperationSucceeded:
+			response.Succeeded++
+		case OperationFailed:
+			response.Failed++
+		}
+	}
+	if !request.Atomic {
+		response.Applied = response.Succeeded > 0
+	}
+
+	return response, nil
+}
+
+// applyAtomicBatch reports whether the batch was committed. An error is only
+// returned when the transaction itself fails rather than one of its operations
+func (s *LessonService) applyAtomicBatch(operations []models.EnrollmentOperation, results []models.EnrollmentOperationResult) (bool, error) {
+	// Malformed operations are rejected before any work starts
+	valid := true
+	for i, operation := range operations {
+		if err := validateOperation(operation); err != nil {
+			failOperation(&results[i], err)
+			valid = false
+		}
+	}
+	if !valid {
+		return false, nil
+	}
+
+	failedAt := -1
+	err := s.Transaction(func(service ILessonService) error {
+		for i, operation := range operations {
+			if err := applyOperation(service, operation); err != nil {
+				failOperation(&results[i], err)
+				failedAt = i
+				return err
+			}
+			results[i].Status = OperationSucceeded
+		}
+		return nil
+	})
+	if err == nil {
+		return true, nil
+	}
+
+	for i := range results {
+		if results[i].Status == OperationSucceeded {
+			results[i].Status = OperationRolledBack
+		}
+	}
+	if failedAt < 0 {
+		return false, err
+	}
+	return false, nil
+}
+
+func applyOperation(service ILessonService, operation models.EnrollmentOperation) error {
+	if err := validateOperation(operation); err != nil {
+		return err
+	}
+
+	switch operation.Op {
+	case OpEnroll:
+		return service.EnrollStudentInLesson(uint64(operation.LessonID), operation.StudentID)
+	case OpRemove:
+		return service.UnenrollStudent(uint64(operation.LessonID), operation.StudentID)
+	default:
+		return service.TransferStudent(uint64(operation.LessonID), uint64(operation.ToLessonID), operation.StudentID)
+	}
+}
+
+func validateOperation(operation models.EnrollmentOperation) error {
+	switch operation.Op {
+	case OpEnroll, OpRemove, OpTransfer:
+	default:
+		return fmt.Errorf("%w: op must be enroll, remove or transfer", ErrInvalidOperation)
+	}
+
+	if operation.StudentID == 0 {
+		return fmt.Errorf("%w: student_id is required", ErrInvalidOperation)
+	}
+	if operation.LessonID == 0 {
+		return fmt.Errorf("%w: lesson_id is required", ErrInvalidOperation)
+	}
+	if operation.Op == OpTransfer && operation.ToLessonID == 0 {
+		return fmt.Errorf("%w: to_lesson_id is required", ErrInvalidOperation)
+	}
+	return nil
+}
+
+func failOperation(result *models.EnrollmentOperationResult, err error) {
+	result.Status = OperationFailed
+	result.Error = err.Error()
+
+	var ineligible *IneligibleError
+	switch {
+	case errors.As(err, &ineligible):
+		result.UnmetPrerequisites = ineligible.Unmet
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		result.Error = "lesson or student not found"
+	case enrollmentErrorStatus(err) == http.StatusInternalServerError:
+		result.Error = "operation failed"
+		fmt.Println("Error while applying enrollment operation: ", err)
+	}
+}
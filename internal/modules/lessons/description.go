@@ -0,0 +1,61 @@
+package lessons
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/markdown"
+	"time"
+)
+
+// Formats lesson detail routes can return the description in. Descriptions are stored as Markdown
+const (
+	DescriptionMarkdown = "markdown"
+	DescriptionHTML     = "html"
+)
+
+// Audiences a description is rendered for. Each sees material references as links only where
+// it can open the material
+const (
+	AudiencePublic  = "public"
+	AudienceStudent = "student"
+	AudienceTeacher = "teacher"
+)
+
+// RenderDescription turns the lesson's Markdown description into sanitized HTML. References
+// such as [slides](material:12) link to the lesson's material on a route the audience can
+// reach: students get the released materials through their open route and teachers every
+// material through the lesson's file route. References to other lessons' materials, to
+// materials the audience can't see, and every reference in public views stay plain text
+func (s *LessonService) RenderDescription(lesson *entities.Lesson, audience string) (string, error) {
+	if audience != AudienceStudent && audience != AudienceTeacher {
+		return markdown.ToHTML(lesson.Description, nil)
+	}
+
+	materials := make(map[uint]entities.Material)
+	if ids := markdown.References(lesson.Description); len(ids) > 0 {
+		found, err := s.repo.GetLessonMaterials(lesson.ID, ids)
+		if err != nil {
+			return "", err
+		}
+		for _, material := range found {
+			materials[material.ID] = material
+		}
+	}
+
+	now := time.Now()
+	return markdown.ToHTML(lesson.Description, func(materialID uint) (markdown.Reference, bool) {
+		material, ok := materials[materialID]
+		if !ok {
+			return markdown.Reference{}, false
+		}
+
+		url := fmt.Sprintf("/api/lessons/%d/materials/%d/file", lesson.ID, material.ID)
+		if audience == AudienceStudent {
+			if !material.IsReleased(now) {
+				return markdown.Reference{}, false
+			}
+			url = fmt.Sprintf("/api/student/materials/%d/open", material.ID)
+		}
+		return markdown.Reference{URL: url, Title: material.Title}, true
+	})
+}
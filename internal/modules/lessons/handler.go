@@ -2,13 +2,20 @@ package lessons
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/students"
 	"lesson-management/models"
 	"lesson-management/pkg/middleware"
+	"lesson-management/pkg/pagination"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
 type LessonHandler struct {
@@ -31,13 +38,18 @@ func (h *LessonHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Use lessonID to fetch lesson detailss
-	lesson, err := h.service.GetLesson(lessonID)
+	lesson, err := h.service.GetPublishedLesson(lessonID)
 	if err != nil {
 		http.Error(w, "Lesson not found", http.StatusNotFound)
 		fmt.Println("Error while fetching lesson: ", err)
 		return
 	}
 
+	// Anyone can read this route, so material references stay plain text
+	if !h.formatDescription(w, r, lesson, AudiencePublic) {
+		return
+	}
+
 	// Return lesson details as JSON
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -50,7 +62,23 @@ func (h *LessonHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 func (h *LessonHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Use lessonID to fetch lesson detailss
-	lessons, err := h.service.GetAllLessons()
+	filter, ok := h.lessonFilter(w, r)
+	if !ok {
+		return
+	}
+
+	// Rosters are private, so public listings cannot be narrowed by student
+	if filter.StudentID != nil {
+		http.Error(w, "Filtering by student requires admin access", http.StatusForbidden)
+		return
+	}
+
+	page, ok := pageParams(w, r, LessonSorts)
+	if !ok {
+		return
+	}
+
+	lessons, pageInfo, err := h.service.GetPublishedLessons(filter, page)
 	if err != nil {
 		http.Error(w, "Lessons not found", http.StatusNotFound)
 		fmt.Println("Error while fetching lessons: ", err)
@@ -58,6 +86,7 @@ func (h *LessonHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Return lesson details as JSON
+	pagination.WriteHeaders(w, r, page, pageInfo)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	err = json.NewEncoder(w).Encode(lessons)
@@ -67,6 +96,181 @@ func (h *LessonHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *LessonHandler) Search(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.lessonFilter(w, r)
+	if !ok {
+		return
+	}
+
+	if filter.StudentID != nil {
+		http.Error(w, "Filtering by student requires admin access", http.StatusForbidden)
+		return
+	}
+
+	page, ok := pageParams(w, r, SearchSorts)
+	if !ok {
+		return
+	}
+
+	results, pageInfo, err := h.service.SearchLessons(r.URL.Query().Get("q"), filter, page)
+	if err != nil {
+		if errors.Is(err, ErrEmptySearchQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to search lessons", http.StatusInternalServerError)
+		fmt.Println("Error while searching lessons: ", err)
+		return
+	}
+
+	pagination.WriteHeaders(w, r, page, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(results)
+}
+
+// Admin lesson views include drafts and archived lessons
+func (h *LessonHandler) GetAny(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.GetLesson(lessonID)
+	if err != nil {
+		http.Error(w, "Lesson not found", http.StatusNotFound)
+		fmt.Println("Error while fetching lesson: ", err)
+		return
+	}
+
+	// Admins have no material route to link to
+	if !h.formatDescription(w, r, lesson, AudiencePublic) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+// GetForStudent is the lesson detail for an enrolled student, with material references that
+// open the lesson's released materials
+func (h *LessonHandler) GetForStudent(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := strconv.ParseUint(mux.Vars(r)["lessonID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.GetStudentLesson(lessonID, studentID)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch lesson", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching lesson: ", err)
+		return
+	}
+
+	if !h.formatDescription(w, r, lesson, AudienceStudent) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+// GetForTeacher is the lesson detail for one of its teachers, with material references that
+// download any of the lesson's materials
+func (h *LessonHandler) GetForTeacher(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	lessonID, err := strconv.ParseUint(mux.Vars(r)["lessonID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch lesson", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching lesson: ", err)
+		return
+	}
+
+	if !h.formatDescription(w, r, lesson, AudienceTeacher) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+// formatDescription replaces the Markdown description with sanitized HTML when ?format=html
+// is requested, answering the error itself when it returns false. Material references are
+// resolved for the given audience
+func (h *LessonHandler) formatDescription(w http.ResponseWriter, r *http.Request, lesson *entities.Lesson, audience string) bool {
+	switch r.URL.Query().Get("format") {
+	case "", DescriptionMarkdown:
+		return true
+	case DescriptionHTML:
+		description, err := h.service.RenderDescription(lesson, audience)
+		if err != nil {
+			http.Error(w, "Failed to render lesson description", http.StatusInternalServerError)
+			fmt.Println("Error while rendering lesson description: ", err)
+			return false
+		}
+		lesson.Description = description
+		return true
+	default:
+		http.Error(w, ErrInvalidFormat.Error(), http.StatusBadRequest)
+		return false
+	}
+}
+
+func (h *LessonHandler) ListAll(w http.ResponseWriter, r *http.Request) {
+	filter, ok := h.lessonFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := pageParams(w, r, LessonSorts)
+	if !ok {
+		return
+	}
+
+	lessons, pageInfo, err := h.service.GetAllLessons(filter, page)
+	if err != nil {
+		http.Error(w, "Lessons not found", http.StatusNotFound)
+		fmt.Println("Error while fetching lessons: ", err)
+		return
+	}
+
+	pagination.WriteHeaders(w, r, page, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lessons)
+}
+
 func (h *LessonHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var requestBody models.CreateLessonRequest
 	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
@@ -74,6 +278,16 @@ func (h *LessonHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if msg := scheduleError(requestBody.EnrollmentOpensAt, requestBody.EnrollmentClosesAt, requestBody.PublishAt, requestBody.UnpublishAt); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Capacity < 0 {
+		http.Error(w, "Capacity cannot be negative", http.StatusBadRequest)
+		return
+	}
+
 	lesson, err := h.service.CreateLesson(&requestBody, requestBody.TeacherID)
 	if err != nil {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -97,7 +311,7 @@ func (h *LessonHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = h.service.GetLesson(lessonID)
+	existing, err := h.service.GetLesson(lessonID)
 	if err != nil {
 		http.Error(w, "Lesson not found", http.StatusNotFound)
 		fmt.Println("Error while fetching lesson: ", err)
@@ -121,8 +335,36 @@ func (h *LessonHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if requestBody.Capacity != nil && *requestBody.Capacity < 0 {
+		http.Error(w, "Capacity cannot be negative", http.StatusBadRequest)
+		return
+	}
+
+	opensAt, closesAt := existing.EnrollmentOpensAt, existing.EnrollmentClosesAt
+	if requestBody.EnrollmentOpensAt != nil {
+		opensAt = requestBody.EnrollmentOpensAt
+	}
+	if requestBody.EnrollmentClosesAt != nil {
+		closesAt = requestBody.EnrollmentClosesAt
+	}
+	publishAt, unpublishAt := existing.PublishAt, existing.UnpublishAt
+	if requestBody.PublishAt != nil {
+		publishAt = requestBody.PublishAt
+	}
+	if requestBody.UnpublishAt != nil {
+		unpublishAt = requestBody.UnpublishAt
+	}
+	if msg := scheduleError(opensAt, closesAt, publishAt, unpublishAt); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
 	lesson, err := h.service.UpdateLesson(&requestBody, lessonID)
 	if err != nil {
+		if errors.Is(err, ErrLessonArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		fmt.Println("Error while updating lesson: ", err)
 		return
@@ -150,6 +392,129 @@ func (h *LessonHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h *LessonHandler) Clone(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.CloneLessonRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Title != nil && *requestBody.Title == "" {
+		http.Error(w, "Lesson name cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.CloneLesson(lessonID, &requestBody)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to clone lesson", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while cloning lesson: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+func (h *LessonHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ChangeLessonStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.ChangeLessonStatus(lessonID, req.Status)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to change lesson status", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while changing lesson status: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+// Admin trash handlers
+func (h *LessonHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	lessons, err := h.service.GetDeletedLessons()
+	if err != nil {
+		http.Error(w, "Failed to fetch deleted lessons", http.StatusInternalServerError)
+		fmt.Println("Error while fetching deleted lessons: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lessons)
+}
+
+func (h *LessonHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.RestoreLesson(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Deleted lesson not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to restore lesson", http.StatusInternalServerError)
+		fmt.Println("Error while restoring lesson: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *LessonHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.PurgeLesson(lessonID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "Deleted lesson not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to purge lesson", http.StatusInternalServerError)
+		fmt.Println("Error while purging lesson: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 // Teacher handlers
 func (h *LessonHandler) GetTeacherLessons(w http.ResponseWriter, r *http.Request) {
 	// Get teacherID from context set by middleware
@@ -159,12 +524,23 @@ func (h *LessonHandler) GetTeacherLessons(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	lessons, err := h.service.GetTeacherLessons(teacherID)
+	filter, ok := h.lessonFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := pageParams(w, r, LessonSorts)
+	if !ok {
+		return
+	}
+
+	lessons, pageInfo, err := h.service.GetTeacherLessons(teacherID, filter, page)
 	if err != nil {
 		http.Error(w, "Failed to fetch lessons", http.StatusInternalServerError)
 		return
 	}
 
+	pagination.WriteHeaders(w, r, page, pageInfo)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(lessons)
@@ -178,12 +554,23 @@ func (h *LessonHandler) GetStudentLessons(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	lessons, err := h.service.GetStudentLessons(studentID)
+	filter, ok := h.lessonFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := pageParams(w, r, LessonSorts)
+	if !ok {
+		return
+	}
+
+	lessons, pageInfo, err := h.service.GetStudentLessons(studentID, filter, page)
 	if err != nil {
 		http.Error(w, "Failed to fetch lessons", http.StatusInternalServerError)
 		return
 	}
 
+	pagination.WriteHeaders(w, r, page, pageInfo)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(lessons)
@@ -208,6 +595,10 @@ func (h *LessonHandler) AssignTeacher(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.AssignTeacherToLesson(lessonID, req.TeacherID)
 	if err != nil {
+		if errors.Is(err, ErrLessonArchived) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, "Failed to assign teacher", http.StatusInternalServerError)
 		return
 	}
@@ -215,7 +606,7 @@ func (h *LessonHandler) AssignTeacher(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *LessonHandler) EnrollStudent(w http.ResponseWriter, r *http.Request) {
+func (h *LessonHandler) AddTeacher(w http.ResponseWriter, r *http.Request) {
 	lessonIDStr := mux.Vars(r)["lessonID"]
 	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
 	if err != nil {
@@ -223,25 +614,29 @@ func (h *LessonHandler) EnrollStudent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req struct {
-		StudentID uint `json:"student_id"`
-	}
+	var req models.AssignLessonTeacherRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	err = h.service.EnrollStudentInLesson(lessonID, req.StudentID)
+	lesson, err := h.service.AddLessonTeacher(lessonID, req.TeacherID, req.Role)
 	if err != nil {
-		http.Error(w, "Failed to enroll student", http.StatusInternalServerError)
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to add lesson teacher", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while adding lesson teacher: ", err)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
 }
 
-// Teacher handlers for student management
-func (h *LessonHandler) GetLessonStudents(w http.ResponseWriter, r *http.Request) {
+func (h *LessonHandler) RemoveTeacher(w http.ResponseWriter, r *http.Request) {
 	lessonIDStr := mux.Vars(r)["lessonID"]
 	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
 	if err != nil {
@@ -249,24 +644,183 @@ func (h *LessonHandler) GetLessonStudents(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	teacherID, ok := middleware.GetUserID(r)
-	if !ok {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	teacherIDStr := mux.Vars(r)["teacherID"]
+	teacherID, err := strconv.ParseUint(teacherIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid teacher ID", http.StatusBadRequest)
 		return
 	}
 
-	students, err := h.service.GetLessonStudents(lessonID, teacherID)
+	lesson, err := h.service.RemoveLessonTeacher(lessonID, uint(teacherID))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusForbidden)
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to remove lesson teacher", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while removing lesson teacher: ", err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(students)
+	json.NewEncoder(w).Encode(lesson)
 }
 
-func (h *LessonHandler) AddStudentToLesson(w http.ResponseWriter, r *http.Request) {
+func (h *LessonHandler) EnrollStudent(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.EnrollStudentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.OverridePrerequisites {
+		adminID, ok := middleware.GetUserID(r)
+		if !ok {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		err = h.service.EnrollStudentWithOverride(lessonID, req.StudentID, adminID, req.OverrideReason)
+	} else {
+		err = h.service.EnrollStudentInLesson(lessonID, req.StudentID)
+	}
+	if err != nil {
+		writeEnrollmentError(w, err)
+		fmt.Println("Error while enrolling student: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// BatchEnrollment applies many enrollment operations in one call. Atomic batches
+// that fail are answered with 422 and the per-operation results
+func (h *LessonHandler) BatchEnrollment(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchEnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.service.BatchEnrollment(&req)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to apply enrollment batch", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while applying enrollment batch: ", err)
+		return
+	}
+
+	status := http.StatusOK
+	if response.Atomic && !response.Applied {
+		status = http.StatusUnprocessableEntity
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *LessonHandler) TransferStudent(w http.ResponseWriter, r *http.Request) {
+	var req models.TransferStudentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.TransferStudent(uint64(req.FromLessonID), uint64(req.ToLessonID), req.StudentID); err != nil {
+		writeEnrollmentError(w, err)
+		fmt.Println("Error while transferring student: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// GetEnrollmentHistory lists enrollment events, optionally for one lesson or student
+func (h *LessonHandler) GetEnrollmentHistory(w http.ResponseWriter, r *http.Request) {
+	var filter EnrollmentEventFilter
+	for param, target := range map[string]**uint{
+		"lesson_id":  &filter.LessonID,
+		"student_id": &filter.StudentID,
+	} {
+		idStr := r.URL.Query().Get(param)
+		if idStr == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid "+param, http.StatusBadRequest)
+			return
+		}
+		value := uint(id)
+		*target = &value
+	}
+
+	page, ok := pageParams(w, r, EnrollmentEventSorts)
+	if !ok {
+		return
+	}
+
+	events, pageInfo, err := h.service.GetEnrollmentHistory(filter, page)
+	if err != nil {
+		http.Error(w, "Failed to fetch enrollment history", http.StatusInternalServerError)
+		fmt.Println("Error while fetching enrollment history: ", err)
+		return
+	}
+
+	pagination.WriteHeaders(w, r, page, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(events)
+}
+
+// Teacher handlers for student management
+func (h *LessonHandler) GetLessonStudents(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	page, ok := pageParams(w, r, students.StudentSorts)
+	if !ok {
+		return
+	}
+
+	students, pageInfo, err := h.service.GetLessonStudents(lessonID, teacherID, page)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		http.Error(w, "Failed to fetch lesson students", http.StatusInternalServerError)
+		return
+	}
+
+	pagination.WriteHeaders(w, r, page, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(students)
+}
+
+func (h *LessonHandler) AddStudentToLesson(w http.ResponseWriter, r *http.Request) {
 	lessonIDStr := mux.Vars(r)["lessonID"]
 	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
 	if err != nil {
@@ -282,9 +836,16 @@ func (h *LessonHandler) AddStudentToLesson(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = h.service.EnrollStudentInLesson(lessonID, req.StudentID)
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err = h.service.AddStudentToLesson(lessonID, req.StudentID, teacherID)
 	if err != nil {
-		http.Error(w, "Failed to add student", http.StatusInternalServerError)
+		writeEnrollmentError(w, err)
+		fmt.Println("Error while adding student: ", err)
 		return
 	}
 
@@ -306,11 +867,429 @@ func (h *LessonHandler) RemoveStudentFromLesson(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	err = h.service.RemoveStudentFromLesson(lessonID, uint(studentID))
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err = h.service.RemoveStudentFromLesson(lessonID, uint(studentID), teacherID)
 	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+			return
+		}
 		http.Error(w, "Failed to remove student", http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// Student self-enrollment handlers
+func (h *LessonHandler) SelfEnroll(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	request, err := h.service.SelfEnroll(lessonID, studentID)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		fmt.Println("Error while enrolling student: ", err)
+		return
+	}
+
+	// Lessons requiring approval queue the request instead of enrolling right away
+	if request != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(request)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *LessonHandler) GetStudentEnrollmentRequests(w http.ResponseWriter, r *http.Request) {
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.service.GetStudentEnrollmentRequests(studentID)
+	if err != nil {
+		http.Error(w, "Failed to fetch enrollment requests", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(requests)
+}
+
+// Teacher handlers for enrollment approval
+func (h *LessonHandler) GetLessonEnrollmentRequests(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = entities.EnrollmentRequestPending
+	} else if status == "all" {
+		status = ""
+	}
+
+	requests, err := h.service.GetLessonEnrollmentRequests(lessonID, teacherID, status)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		http.Error(w, "Failed to fetch enrollment requests", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(requests)
+}
+
+func (h *LessonHandler) ApproveEnrollmentRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideEnrollmentRequest(w, r, h.service.ApproveEnrollmentRequest)
+}
+
+func (h *LessonHandler) RejectEnrollmentRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideEnrollmentRequest(w, r, h.service.RejectEnrollmentRequest)
+}
+
+func (h *LessonHandler) decideEnrollmentRequest(w http.ResponseWriter, r *http.Request, decide func(uint64, uint) (*entities.EnrollmentRequest, error)) {
+	requestIDStr := mux.Vars(r)["requestID"]
+	requestID, err := strconv.ParseUint(requestIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid request ID", http.StatusBadRequest)
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	request, err := decide(requestID, teacherID)
+	if err != nil {
+		writeEnrollmentError(w, err)
+		fmt.Println("Error while deciding enrollment request: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(request)
+}
+
+func (h *LessonHandler) SetTags(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetLessonTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.SetLessonTags(lessonID, req.Tags)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to set lesson tags", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while setting lesson tags: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+// Prerequisite handlers
+func (h *LessonHandler) SetPrerequisites(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetPrerequisitesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lesson, err := h.service.SetPrerequisites(lessonID, req.PrerequisiteIDs)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to set prerequisites", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while setting prerequisites: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(lesson)
+}
+
+func (h *LessonHandler) CheckEligibility(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	eligibility, err := h.service.CheckEligibility(lessonID, studentID)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		http.Error(w, "Failed to check eligibility", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(eligibility)
+}
+
+func (h *LessonHandler) CompleteEnrollment(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	studentIDStr := mux.Vars(r)["studentID"]
+	studentID, err := strconv.ParseUint(studentIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid student ID", http.StatusBadRequest)
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err = h.service.CompleteEnrollment(lessonID, uint(studentID), teacherID)
+	if err != nil {
+		if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		http.Error(w, "Failed to complete enrollment", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// lessonFilter reads list filters from the query string; lists default to the current term
+// unless term_id selects another one or term=all disables the term filter
+func (h *LessonHandler) lessonFilter(w http.ResponseWriter, r *http.Request) (LessonFilter, bool) {
+	var filter LessonFilter
+	query := r.URL.Query()
+
+	for param, target := range map[string]**uint{
+		"teacher_id": &filter.TeacherID,
+		"student_id": &filter.StudentID,
+		"subject_id": &filter.SubjectID,
+	} {
+		idStr := query.Get(param)
+		if idStr == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(idStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid "+param, http.StatusBadRequest)
+			return filter, false
+		}
+		value := uint(id)
+		*target = &value
+	}
+
+	if status := query.Get("status"); status != "" {
+		if _, ok := lessonTransitions[status]; !ok {
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return filter, false
+		}
+		filter.Status = status
+	}
+
+	filter.Title = strings.TrimSpace(query.Get("title"))
+
+	if tags := query.Get("tags"); tags != "" {
+		filter.Tags = normalizeTagNames(strings.Split(tags, ","))
+	}
+
+	for param, target := range map[string]**time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		at, err := parseTimeParam(value)
+		if err != nil {
+			http.Error(w, "Invalid "+param+", expected RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+			return filter, false
+		}
+		*target = &at
+	}
+
+	if termIDStr := query.Get("term_id"); termIDStr != "" {
+		termID, err := strconv.ParseUint(termIDStr, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid term ID", http.StatusBadRequest)
+			return filter, false
+		}
+		id := uint(termID)
+		filter.TermID = &id
+		return filter, true
+	}
+
+	if query.Get("term") == "all" {
+		return filter, true
+	}
+
+	current, err := h.service.GetCurrentTerm()
+	if err != nil {
+		http.Error(w, "Failed to resolve current term", http.StatusInternalServerError)
+		fmt.Println("Error while fetching current term: ", err)
+		return filter, false
+	}
+	if current != nil {
+		filter.TermID = &current.ID
+	}
+	return filter, true
+}
+
+// pageParams reads limit, offset, cursor and sort, reporting bad values as 400s
+func pageParams(w http.ResponseWriter, r *http.Request, sorts pagination.Sorts) (pagination.Params, bool) {
+	page, err := pagination.FromRequest(r, sorts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return page, false
+	}
+	return page, true
+}
+
+// parseTimeParam accepts either a full RFC 3339 timestamp or a bare date
+func parseTimeParam(value string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339, value); err == nil {
+		return at, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// scheduleError validates that enrollment and publication windows end after they start
+func scheduleError(opensAt, closesAt, publishAt, unpublishAt *time.Time) string {
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		return "Enrollment must close after it opens"
+	}
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return "Lesson must be unpublished after it is published"
+	}
+	return ""
+}
+
+// writeEnrollmentError reports unmet prerequisites as a structured body, mapped errors as text and anything else generically
+func writeEnrollmentError(w http.ResponseWriter, err error) {
+	var ineligible *IneligibleError
+	if errors.As(err, &ineligible) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(models.EligibilityResponse{
+			Eligible:           false,
+			UnmetPrerequisites: ineligible.Unmet,
+		})
+		return
+	}
+
+	if status := enrollmentErrorStatus(err); status != http.StatusInternalServerError {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	http.Error(w, "Failed to update enrollment", http.StatusInternalServerError)
+}
+
+// enrollmentErrorStatus maps service errors to HTTP status codes
+func enrollmentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, ErrNotEnrolled):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotLessonTeacher),
+		errors.Is(err, ErrNotLessonStudent),
+		errors.Is(err, ErrSelfEnrollmentDisabled),
+		errors.Is(err, ErrEnrollmentClosed):
+		return http.StatusForbidden
+	case errors.Is(err, ErrAlreadyEnrolled),
+		errors.Is(err, ErrLessonFull),
+		errors.Is(err, ErrRequestAlreadyPending),
+		errors.Is(err, ErrRequestNotPending),
+		errors.Is(err, ErrPrerequisiteCycle),
+		errors.Is(err, ErrLessonArchived),
+		errors.Is(err, ErrInvalidStatusChange),
+		errors.Is(err, ErrLeadTeacherRequired):
+		return http.StatusConflict
+	case errors.Is(err, ErrOverrideReasonRequired),
+		errors.Is(err, ErrInvalidTeacherRole),
+		errors.Is(err, ErrSameLesson),
+		errors.Is(err, ErrEmptyBatch),
+		errors.Is(err, ErrBatchTooLarge),
+		errors.Is(err, ErrInvalidOperation):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
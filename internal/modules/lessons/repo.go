@@ -2,49 +2,244 @@ package lessons
 
 import (
 	"fmt"
+	"html"
 	"lesson-management/entities"
+	"lesson-management/internal/modules/students"
+	"lesson-management/models"
 	"lesson-management/pkg/common"
+	"lesson-management/pkg/pagination"
+	"lesson-management/pkg/storage"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// LessonFilter narrows lesson list queries; nil fields leave the list unrestricted
+type LessonFilter struct {
+	TermID        *uint
+	TeacherID     *uint
+	StudentID     *uint
+	SubjectID     *uint
+	Tags          []string
+	Status        string
+	Title         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+func (f LessonFilter) apply(query *gorm.DB) *gorm.DB {
+	// Subqueries start from the query's own connection, so they run inside its transaction
+	subquery := query.Session(&gorm.Session{NewDB: true})
+	if f.TermID != nil {
+		query = query.Where("lessons.term_id = ?", *f.TermID)
+	}
+	if f.TeacherID != nil {
+		query = query.Where("lessons.id IN (?)", subquery.Model(&entities.LessonTeacher{}).Select("lesson_id").Where("teacher_id = ?", *f.TeacherID))
+	}
+	if f.StudentID != nil {
+		query = query.Where("lessons.id IN (?)", subquery.Model(&entities.Enrollment{}).Select("lesson_id").Where("student_id = ?", *f.StudentID))
+	}
+	if f.SubjectID != nil {
+		// A subject filter matches the subject and everything below it
+		query = query.Where(`lessons.subject_id IN (
+			WITH RECURSIVE subtree AS (
+				SELECT id FROM subjects WHERE id = ?
+				UNION ALL
+				SELECT subjects.id FROM subjects JOIN subtree ON subjects.parent_id = subtree.id
+			)
+			SELECT id FROM subtree
+		)`, *f.SubjectID)
+	}
+	if len(f.Tags) > 0 {
+		// Lessons must carry every requested tag
+		query = query.Where(`lessons.id IN (
+			SELECT lesson_tags.lesson_id FROM lesson_tags
+			JOIN tags ON tags.id = lesson_tags.tag_id
+			WHERE tags.name IN ?
+			GROUP BY lesson_tags.lesson_id
+			HAVING COUNT(DISTINCT tags.id) = ?
+		)`, f.Tags, len(f.Tags))
+	}
+	if f.Status != "" {
+		query = query.Where("lessons.status = ?", f.Status)
+	}
+	if f.Title != "" {
+		query = query.Where("lessons.title ILIKE ?", "%"+likeEscaper.Replace(f.Title)+"%")
+	}
+	if f.CreatedAfter != nil {
+		query = query.Where("lessons.created_at >= ?", *f.CreatedAfter)
+	}
+	if f.CreatedBefore != nil {
+		query = query.Where("lessons.created_at < ?", *f.CreatedBefore)
+	}
+	return query
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchSorts orders search results by relevance; rank is the only sort key
+var SearchSorts = pagination.Sorts{
+	ID:      "results.id",
+	Default: "-rank",
+	Fields: map[string]pagination.SortField{
+		"rank": {Column: "results.rank", Type: "real"},
+	},
+}
+
+// LessonSorts lists the sort keys accepted by lesson list endpoints
+var LessonSorts = pagination.Sorts{
+	ID:      "lessons.id",
+	Default: "id",
+	Fields: map[string]pagination.SortField{
+		"id":         {Column: "lessons.id", Type: "bigint"},
+		"title":      {Column: "lessons.title", Type: "text"},
+		"created_at": {Column: "lessons.created_at", Type: "timestamptz"},
+		"updated_at": {Column: "lessons.updated_at", Type: "timestamptz"},
+	},
+}
+
+// EnrollmentEventFilter narrows the enrollment history; a lesson matches both the
+// lesson an event belongs to and the lesson a transfer came from
+type EnrollmentEventFilter struct {
+	LessonID  *uint
+	StudentID *uint
+}
+
+// EnrollmentEventSorts lists the sort keys accepted by the enrollment history
+var EnrollmentEventSorts = pagination.Sorts{
+	ID:      "enrollment_events.id",
+	Default: "-created_at",
+	Fields: map[string]pagination.SortField{
+		"created_at": {Column: "enrollment_events.created_at", Type: "timestamptz"},
+	},
+}
+
 type ILessonRepository interface {
 	GetLesson(id uint) (entities.Lesson, error)
-	GetAllLessons() ([]*entities.Lesson, error)
+	GetAllLessons(filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
 	CreateLesson(lesson *entities.Lesson) error
 	UpdateLesson(lesson *entities.Lesson) error
 	DeleteLesson(id uint) error
-	GetLessonsByTeacherID(teacherID uint) ([]*entities.Lesson, error)
-	GetLessonsByStudentID(studentID uint) ([]*entities.Lesson, error)
+	GetLessonsByTeacherID(teacherID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
+	GetLessonsByStudentID(studentID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
 	AssignTeacherToLesson(lessonID uint, teacherID uint) error
 	EnrollStudentInLesson(lessonID uint, studentID uint) error
 	RemoveStudentFromLesson(lessonID uint, studentID uint) error
-	GetLessonStudents(lessonID uint) ([]entities.Student, error)
+	GetLessonStudents(lessonID uint, page pagination.Params) ([]entities.Student, pagination.Page, error)
+	IsStudentEnrolled(lessonID uint, studentID uint) (bool, error)
+	GetEnrolledStudents(lessonID uint) ([]entities.Student, error)
+	CreateEnrollmentRequest(request *entities.EnrollmentRequest) error
+	GetEnrollmentRequest(id uint) (entities.EnrollmentRequest, error)
+	GetPendingEnrollmentRequest(lessonID uint, studentID uint) (*entities.EnrollmentRequest, error)
+	GetEnrollmentRequestsByLessonID(lessonID uint, status string) ([]*entities.EnrollmentRequest, error)
+	GetEnrollmentRequestsByStudentID(studentID uint) ([]*entities.EnrollmentRequest, error)
+	ApproveEnrollmentRequest(request *entities.EnrollmentRequest, decidedBy uint) error
+	RejectEnrollmentRequest(request *entities.EnrollmentRequest, decidedBy uint) error
+	SetPrerequisites(lessonID uint, prerequisiteIDs []uint) error
+	SetLessonTags(lessonID uint, names []string) error
+	GetPrerequisiteGraph() (map[uint][]uint, error)
+	GetStudentEnrollments(studentID uint, lessonIDs []uint) ([]entities.Enrollment, error)
+	CompleteEnrollment(lessonID uint, studentID uint) error
+	EnrollStudentWithOverride(lessonID uint, studentID uint, override *entities.PrerequisiteOverride) error
+	TransferStudent(fromLessonID uint, toLessonID uint, studentID uint) error
+	GetEnrollmentEvents(filter EnrollmentEventFilter, page pagination.Params) ([]entities.EnrollmentEvent, pagination.Page, error)
+	PublishScheduledLessons(now time.Time) ([]uint, error)
+	GetCourseStudentIDs(lessonID uint) ([]uint, error)
+	EnrollCourseStudent(lessonID uint, studentID uint) error
+	UnpublishScheduledLessons(now time.Time) (int64, error)
+	GetDeletedLessons() ([]*entities.Lesson, error)
+	RestoreLesson(id uint) error
+	PurgeLesson(id uint) ([]string, error)
+	PurgeDeletedLessons(before time.Time) (int64, []string, error)
+	AddLessonTeacher(lessonTeacher *entities.LessonTeacher) error
+	RemoveLessonTeacher(lessonID uint, teacherID uint) error
+	IsLessonTeacher(lessonID uint, teacherID uint) (bool, error)
+	GetLessonMaterials(lessonID uint, ids []uint) ([]entities.Material, error)
+	GetCurrentTerm(now time.Time) (*entities.Term, error)
+	CloneLesson(source *entities.Lesson, clone *entities.Lesson, includeRoster bool) ([]FileCopy, error)
+	SearchLessons(tsquery string, filter LessonFilter, page pagination.Params) ([]models.LessonSearchResult, pagination.Page, error)
+	Transaction(fn func(repo ILessonRepository) error) error
 }
 
-type LessonRepository struct{}
+type LessonRepository struct {
+	// tx is set on repositories bound to an open transaction
+	tx *gorm.DB
+}
 
 func NewLessonRepository() ILessonRepository {
 	return &LessonRepository{}
 }
 
+func (r *LessonRepository) db() *gorm.DB {
+	if r.tx != nil {
+		return r.tx
+	}
+	return common.DB
+}
+
+func (r *LessonRepository) GetEnrollmentEvents(filter EnrollmentEventFilter, page pagination.Params) ([]entities.EnrollmentEvent, pagination.Page, error) {
+	query := r.db().Model(&entities.EnrollmentEvent{})
+	if filter.LessonID != nil {
+		query = query.Where("(enrollment_events.lesson_id = ? OR enrollment_events.from_lesson_id = ?)", *filter.LessonID, *filter.LessonID)
+	}
+	if filter.StudentID != nil {
+		query = query.Where("enrollment_events.student_id = ?", *filter.StudentID)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	var events []entities.EnrollmentEvent
+	if err := page.Apply(query).Find(&events).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	events, next := pagination.Trim(events, page, func(event entities.EnrollmentEvent) pagination.Cursor {
+		return pagination.Cursor{Value: event.CreatedAt.Format(time.RFC3339Nano), ID: event.ID}
+	})
+	return events, pagination.Page{Total: total, NextCursor: next}, nil
+}
+
+// Transaction runs fn against a repository bound to a single database transaction.
+// Nested calls use savepoints, so a failing step only rolls back its own writes.
+func (r *LessonRepository) Transaction(fn func(repo ILessonRepository) error) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		return fn(&LessonRepository{tx: tx})
+	})
+}
+
 func (r *LessonRepository) GetLesson(id uint) (entities.Lesson, error) {
 	var lesson entities.Lesson
-	result := common.DB.Preload("Teacher").Preload("Students").First(&lesson, id)
+	result := r.db().Preload("Teacher").Preload("Teachers.Teacher").Preload("Students").Preload("Prerequisites").Preload("Term").Preload("Subject").Preload("Tags").First(&lesson, id)
 	return lesson, result.Error
 }
 
-func (r *LessonRepository) GetAllLessons() ([]*entities.Lesson, error) {
-	var lessons []*entities.Lesson
-	result := common.DB.Preload("Teacher").Preload("Students").Find(&lessons)
-
-	return lessons, result.Error
+func (r *LessonRepository) GetAllLessons(filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	query := filter.apply(r.db().Model(&entities.Lesson{}))
+	return findLessons(query, page, "Teacher", "Subject", "Tags")
 }
 
 func (r *LessonRepository) CreateLesson(lesson *entities.Lesson) error {
-	return common.DB.Create(lesson).Error
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(lesson).Error; err != nil {
+			return err
+		}
+
+		if lesson.TeacherID == 0 {
+			return nil
+		}
+		return setLeadTeacher(tx, lesson.ID, lesson.TeacherID)
+	})
 }
 
 func (r *LessonRepository) UpdateLesson(lesson *entities.Lesson) error {
-	result := common.DB.Save(lesson)
+	result := r.db().Omit(clause.Associations).Save(lesson)
 
 	if result.Error != nil {
 		return result.Error
@@ -58,75 +253,964 @@ func (r *LessonRepository) UpdateLesson(lesson *entities.Lesson) error {
 }
 
 func (r *LessonRepository) DeleteLesson(id uint) error {
-	return common.DB.Delete(&entities.Lesson{}, id).Error
+	return r.db().Delete(&entities.Lesson{}, id).Error
 }
 
-func (r *LessonRepository) GetLessonsByTeacherID(teacherID uint) ([]*entities.Lesson, error) {
-	var lessons []*entities.Lesson
-	result := common.DB.Where("teacher_id = ?", teacherID).Preload("Students").Find(&lessons)
-	return lessons, result.Error
+func (r *LessonRepository) GetLessonsByTeacherID(teacherID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	query := filter.apply(r.db().Model(&entities.Lesson{})).
+		Where("lessons.id IN (?)", r.db().Model(&entities.LessonTeacher{}).Select("lesson_id").Where("teacher_id = ?", teacherID))
+	return findLessons(query, page, "Teachers.Teacher", "Subject", "Tags")
 }
 
-func (r *LessonRepository) GetLessonsByStudentID(studentID uint) ([]*entities.Lesson, error) {
-	var lessons []*entities.Lesson
-	result := common.DB.Model(&entities.Lesson{}).
+func (r *LessonRepository) GetLessonsByStudentID(studentID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	query := filter.apply(r.db().Model(&entities.Lesson{})).
 		Joins("JOIN lesson_students ON lessons.id = lesson_students.lesson_id").
 		Where("lesson_students.student_id = ?", studentID).
+		Where("lessons.status IN ?", []string{entities.LessonPublished, entities.LessonArchived})
+	return findLessons(query, page, "Teacher", "Subject", "Tags")
+}
+
+func (r *LessonRepository) AssignTeacherToLesson(lessonID uint, teacherID uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		return setLeadTeacher(tx, lessonID, teacherID)
+	})
+}
+
+func (r *LessonRepository) EnrollStudentInLesson(lessonID uint, studentID uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entities.Student{}, studentID).Error; err != nil {
+			return err
+		}
+
+		enrolled, err := isEnrolled(tx, lessonID, studentID)
+		if err != nil {
+			return err
+		}
+		if enrolled {
+			return ErrAlreadyEnrolled
+		}
+
+		return addEnrollment(tx, lessonID, studentID, nil)
+	})
+}
+
+func (r *LessonRepository) RemoveStudentFromLesson(lessonID uint, studentID uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entities.Lesson{}, lessonID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&entities.Student{}, studentID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("lesson_id = ? AND student_id = ?", lessonID, studentID).Delete(&entities.Enrollment{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		return tx.Create(&entities.EnrollmentEvent{
+			LessonID:  lessonID,
+			StudentID: studentID,
+			Type:      entities.EnrollmentEventRemoved,
+		}).Error
+	})
+}
+
+// TransferStudent moves an enrollment to another lesson, checking the target's capacity,
+// in one transaction so the student is never left in neither lesson
+func (r *LessonRepository) TransferStudent(fromLessonID uint, toLessonID uint, studentID uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		enrolled, err := isEnrolled(tx, toLessonID, studentID)
+		if err != nil {
+			return err
+		}
+		if enrolled {
+			return ErrAlreadyEnrolled
+		}
+
+		result := tx.Where("lesson_id = ? AND student_id = ?", fromLessonID, studentID).Delete(&entities.Enrollment{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotEnrolled
+		}
+
+		return addEnrollment(tx, toLessonID, studentID, &fromLessonID)
+	})
+}
+
+func (r *LessonRepository) GetLessonStudents(lessonID uint, page pagination.Params) ([]entities.Student, pagination.Page, error) {
+	query := r.db().Model(&entities.Student{}).
+		Where("students.id IN (?)", r.db().Model(&entities.Enrollment{}).Select("student_id").Where("lesson_id = ?", lessonID)).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	var roster []entities.Student
+	if err := page.Apply(query).Find(&roster).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	roster, next := pagination.Trim(roster, page, func(student entities.Student) pagination.Cursor {
+		return students.StudentCursor(student, page.Key)
+	})
+	return roster, pagination.Page{Total: total, NextCursor: next}, nil
+}
+
+func (r *LessonRepository) IsStudentEnrolled(lessonID uint, studentID uint) (bool, error) {
+	var count int64
+	result := r.db().Table("lesson_students").
+		Where("lesson_id = ? AND student_id = ?", lessonID, studentID).
+		Count(&count)
+	return count > 0, result.Error
+}
+
+// GetEnrolledStudents returns everyone enrolled in the lesson, ordered by name
+func (r *LessonRepository) GetEnrolledStudents(lessonID uint) ([]entities.Student, error) {
+	var roster []entities.Student
+	result := r.db().
+		Where("id IN (?)", r.db().Model(&entities.Enrollment{}).Select("student_id").Where("lesson_id = ?", lessonID)).
+		Order("name, id").
+		Find(&roster)
+	return roster, result.Error
+}
+
+func (r *LessonRepository) CreateEnrollmentRequest(request *entities.EnrollmentRequest) error {
+	return r.db().Create(request).Error
+}
+
+func (r *LessonRepository) GetEnrollmentRequest(id uint) (entities.EnrollmentRequest, error) {
+	var request entities.EnrollmentRequest
+	result := r.db().Preload("Lesson").Preload("Student").First(&request, id)
+	return request, result.Error
+}
+
+func (r *LessonRepository) GetPendingEnrollmentRequest(lessonID uint, studentID uint) (*entities.EnrollmentRequest, error) {
+	var requests []*entities.EnrollmentRequest
+	result := r.db().
+		Where("lesson_id = ? AND student_id = ? AND status = ?", lessonID, studentID, entities.EnrollmentRequestPending).
+		Limit(1).
+		Find(&requests)
+	if result.Error != nil || len(requests) == 0 {
+		return nil, result.Error
+	}
+	return requests[0], nil
+}
+
+func (r *LessonRepository) GetEnrollmentRequestsByLessonID(lessonID uint, status string) ([]*entities.EnrollmentRequest, error) {
+	var requests []*entities.EnrollmentRequest
+	query := r.db().Where("lesson_id = ?", lessonID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Preload("Student").Order("created_at").Find(&requests)
+	return requests, result.Error
+}
+
+func (r *LessonRepository) GetEnrollmentRequestsByStudentID(studentID uint) ([]*entities.EnrollmentRequest, error) {
+	var requests []*entities.EnrollmentRequest
+	result := r.db().Where("student_id = ?", studentID).
+		Preload("Lesson").
+		Order("created_at DESC").
+		Find(&requests)
+	return requests, result.Error
+}
+
+// ApproveEnrollmentRequest enrolls the student and closes the request in a single transaction
+func (r *LessonRepository) ApproveEnrollmentRequest(request *entities.EnrollmentRequest, decidedBy uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Table("lesson_students").
+			Where("lesson_id = ? AND student_id = ?", request.LessonID, request.StudentID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+
+		if count == 0 {
+			if err := addEnrollment(tx, request.LessonID, request.StudentID, nil); err != nil {
+				return err
+			}
+		}
+
+		return decideEnrollmentRequest(tx, request, entities.EnrollmentRequestApproved, decidedBy)
+	})
+}
+
+func (r *LessonRepository) RejectEnrollmentRequest(request *entities.EnrollmentRequest, decidedBy uint) error {
+	return decideEnrollmentRequest(r.db(), request, entities.EnrollmentRequestRejected, decidedBy)
+}
+
+func decideEnrollmentRequest(db *gorm.DB, request *entities.EnrollmentRequest, status string, decidedBy uint) error {
+	now := time.Now()
+	result := db.Model(&entities.EnrollmentRequest{}).
+		Where("id = ? AND status = ?", request.ID, entities.EnrollmentRequestPending).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"decided_by": decidedBy,
+			"decided_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrRequestNotPending
+	}
+
+	request.Status = status
+	request.DecidedBy = &decidedBy
+	request.DecidedAt = &now
+	return nil
+}
+
+func (r *LessonRepository) SetPrerequisites(lessonID uint, prerequisiteIDs []uint) error {
+	prerequisites := make([]entities.Lesson, 0, len(prerequisiteIDs))
+	if len(prerequisiteIDs) > 0 {
+		if err := r.db().Find(&prerequisites, prerequisiteIDs).Error; err != nil {
+			return err
+		}
+		if len(prerequisites) != len(prerequisiteIDs) {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	lesson := &entities.Lesson{ID: lessonID}
+	return r.db().Model(lesson).Association("Prerequisites").Replace(prerequisites)
+}
+
+// SetLessonTags replaces the lesson's tags, creating tags that do not exist yet
+func (r *LessonRepository) SetLessonTags(lessonID uint, names []string) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		tags := make([]entities.Tag, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, entities.Tag{Name: name})
+		}
+		if len(tags) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+				return err
+			}
+			tags = tags[:0]
+			if err := tx.Where("name IN ?", names).Find(&tags).Error; err != nil {
+				return err
+			}
+		}
+
+		lesson := &entities.Lesson{ID: lessonID}
+		return tx.Model(lesson).Association("Tags").Replace(tags)
+	})
+}
+
+// GetPrerequisiteGraph returns every prerequisite edge keyed by the dependent lesson
+func (r *LessonRepository) GetPrerequisiteGraph() (map[uint][]uint, error) {
+	var edges []struct {
+		LessonID       uint
+		PrerequisiteID uint
+	}
+	if err := r.db().Table("lesson_prerequisites").Find(&edges).Error; err != nil {
+		return nil, err
+	}
+
+	graph := make(map[uint][]uint)
+	for _, edge := range edges {
+		graph[edge.LessonID] = append(graph[edge.LessonID], edge.PrerequisiteID)
+	}
+	return graph, nil
+}
+
+func (r *LessonRepository) GetStudentEnrollments(studentID uint, lessonIDs []uint) ([]entities.Enrollment, error) {
+	var enrollments []entities.Enrollment
+	if len(lessonIDs) == 0 {
+		return enrollments, nil
+	}
+	result := r.db().Where("student_id = ? AND lesson_id IN ?", studentID, lessonIDs).Find(&enrollments)
+	return enrollments, result.Error
+}
+
+func (r *LessonRepository) CompleteEnrollment(lessonID uint, studentID uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.Enrollment{}).
+			Where("lesson_id = ? AND student_id = ?", lessonID, studentID).
+			Updates(map[string]interface{}{
+				"status":       entities.EnrollmentCompleted,
+				"completed_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&entities.EnrollmentEvent{
+			LessonID:  lessonID,
+			StudentID: studentID,
+			Type:      entities.EnrollmentEventCompleted,
+		}).Error
+	})
+}
+
+// EnrollStudentWithOverride enrolls the student and records the admin override in one transaction
+func (r *LessonRepository) EnrollStudentWithOverride(lessonID uint, studentID uint, override *entities.PrerequisiteOverride) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&entities.Student{}, studentID).Error; err != nil {
+			return err
+		}
+
+		enrolled, err := isEnrolled(tx, lessonID, studentID)
+		if err != nil {
+			return err
+		}
+		if enrolled {
+			return ErrAlreadyEnrolled
+		}
+
+		if err := addEnrollment(tx, lessonID, studentID, nil); err != nil {
+			return err
+		}
+
+		return tx.Create(override).Error
+	})
+}
+
+// PublishScheduledLessons publishes drafts whose publish time has passed and returns their IDs
+func (r *LessonRepository) PublishScheduledLessons(now time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Lesson{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", entities.LessonDraft, now).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tx.Model(&entities.Lesson{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":       entities.LessonPublished,
+				"published_at": now,
+				"publish_at":   nil,
+			}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetCourseStudentIDs returns the students enrolled in any course the lesson belongs to
+func (r *LessonRepository) GetCourseStudentIDs(lessonID uint) ([]uint, error) {
+	var studentIDs []uint
+	result := r.db().Model(&entities.CourseEnrollment{}).
+		Distinct("course_enrollments.student_id").
+		Joins("JOIN modules ON modules.course_id = course_enrollments.course_id").
+		Joins("JOIN module_lessons ON module_lessons.module_id = modules.id").
+		Where("module_lessons.lesson_id = ?", lessonID).
+		Order("course_enrollments.student_id").
+		Pluck("course_enrollments.student_id", &studentIDs)
+	return studentIDs, result.Error
+}
+
+// EnrollCourseStudent enrolls a student who reaches the lesson through a course, leaving existing enrollments alone
+func (r *LessonRepository) EnrollCourseStudent(lessonID uint, studentID uint) error {
+	return r.db().Transaction(func(tx *gorm.DB) error {
+		return EnrollInTransaction(tx, lessonID, studentID)
+	})
+}
+
+// UnpublishScheduledLessons moves published lessons back to draft once their unpublish time has passed
+func (r *LessonRepository) UnpublishScheduledLessons(now time.Time) (int64, error) {
+	result := r.db().Model(&entities.Lesson{}).
+		Where("status = ? AND unpublish_at IS NOT NULL AND unpublish_at <= ?", entities.LessonPublished, now).
+		Updates(map[string]interface{}{
+			"status":       entities.LessonDraft,
+			"unpublish_at": nil,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *LessonRepository) GetDeletedLessons() ([]*entities.Lesson, error) {
+	var lessons []*entities.Lesson
+	result := r.db().Unscoped().
+		Where("deleted_at IS NOT NULL").
 		Preload("Teacher").
+		Order("deleted_at DESC").
 		Find(&lessons)
 	return lessons, result.Error
 }
 
-func (r *LessonRepository) AssignTeacherToLesson(lessonID uint, teacherID uint) error {
-	result := common.DB.Model(&entities.Lesson{}).Where("id = ?", lessonID).Update("teacher_id", teacherID)
-	return result.Error
+func (r *LessonRepository) RestoreLesson(id uint) error {
+	result := r.db().Unscoped().Model(&entities.Lesson{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
 }
 
-func (r *LessonRepository) EnrollStudentInLesson(lessonID uint, studentID uint) error {
-	lesson := &entities.Lesson{}
-	student := &entities.Student{}
+// PurgeLesson permanently removes a lesson that is already in the trash. It returns the storage keys
+// of the files the deleted rows referred to, for the caller to delete once the transaction has committed
+func (r *LessonRepository) PurgeLesson(id uint) ([]string, error) {
+	var keys []string
+	err := r.db().Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Unscoped().Model(&entities.Lesson{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
 
-	if err := common.DB.First(lesson, lessonID).Error; err != nil {
+		var err error
+		keys, err = purgeLessons(tx, []uint{id})
 		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if err := common.DB.First(student, studentID).Error; err != nil {
+	return keys, nil
+}
+
+// PurgeDeletedLessons is PurgeLesson for every lesson deleted before the given time
+func (r *LessonRepository) PurgeDeletedLessons(before time.Time) (int64, []string, error) {
+	var ids []uint
+	if err := r.db().Unscoped().Model(&entities.Lesson{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, nil, err
+	}
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	var keys []string
+	err := r.db().Transaction(func(tx *gorm.DB) error {
+		var err error
+		keys, err = purgeLessons(tx, ids)
 		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return int64(len(ids)), keys, nil
+}
+
+// purgeLessons hard-deletes lessons together with every row that references them, returning the storage
+// keys of the files those rows referred to. Soft deletion leaves these rows in place so a restore brings the
+// lesson back intact.
+func purgeLessons(tx *gorm.DB, ids []uint) ([]string, error) {
+	var keys []string
+	for _, stored := range []interface{}{&entities.Material{}, &entities.AssignmentSubmission{}} {
+		var found []string
+		if err := tx.Model(stored).
+			Where("lesson_id IN ? AND storage_key <> ''", ids).
+			Pluck("storage_key", &found).Error; err != nil {
+			return nil, err
+		}
+		keys = append(keys, found...)
 	}
 
-	// Check if already enrolled
+	dependents := []interface{}{
+		&entities.Enrollment{},
+		&entities.EnrollmentRequest{},
+		&entities.PrerequisiteOverride{},
+		&entities.LessonTeacher{},
+		&entities.ModuleLesson{},
+		&entities.AttendanceRecord{},
+		&entities.CheckInWindow{},
+		&entities.LessonSession{},
+		&entities.EnrollmentEvent{},
+		&entities.ProgressRecord{},
+		&entities.Grade{},
+		&entities.GradeItem{},
+		&entities.GradeCategory{},
+		&entities.RubricCriterionScore{},
+		&entities.RubricAssessment{},
+		&entities.AssignmentSubmission{},
+		&entities.Assignment{},
+		&entities.MaterialDownload{},
+		&entities.Material{},
+		&entities.MaterialSection{},
+		&entities.QuizResponse{},
+		&entities.QuizAttempt{},
+		&entities.QuizOption{},
+		&entities.QuizQuestion{},
+		&entities.Quiz{},
+	}
+	for _, dependent := range dependents {
+		if err := tx.Where("lesson_id IN ?", ids).Delete(dependent).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Exec("DELETE FROM lesson_prerequisites WHERE lesson_id IN ? OR prerequisite_id IN ?", ids, ids).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Exec("DELETE FROM lesson_template_prerequisites WHERE prerequisite_id IN ?", ids).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Exec("DELETE FROM lesson_tags WHERE lesson_id IN ?", ids).Error; err != nil {
+		return nil, err
+	}
+	// Keep the target lesson's history of students transferred out of a purged lesson
+	if err := tx.Model(&entities.EnrollmentEvent{}).Where("from_lesson_id IN ?", ids).Update("from_lesson_id", nil).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Unscoped().Delete(&entities.Lesson{}, ids).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// AddLessonTeacher assigns a teacher or changes their role on the lesson
+func (r *LessonRepository) AddLessonTeacher(lessonTeacher *entities.LessonTeacher) error {
+	if lessonTeacher.Role == entities.LessonTeacherLead {
+		return r.AssignTeacherToLesson(lessonTeacher.LessonID, lessonTeacher.TeacherID)
+	}
+
+	if err := r.db().First(&entities.Teacher{}, lessonTeacher.TeacherID).Error; err != nil {
+		return err
+	}
+
+	return r.db().Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "lesson_id"}, {Name: "teacher_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(lessonTeacher).Error
+}
+
+func (r *LessonRepository) RemoveLessonTeacher(lessonID uint, teacherID uint) error {
+	result := r.db().Where("lesson_id = ? AND teacher_id = ?", lessonID, teacherID).Delete(&entities.LessonTeacher{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *LessonRepository) IsLessonTeacher(lessonID uint, teacherID uint) (bool, error) {
 	var count int64
-	common.DB.Model(&entities.Lesson{ID: lessonID}).Association("Students").Count()
-	common.DB.Table("lesson_students").
-		Where("lesson_id = ? AND student_id = ?", lessonID, studentID).
+	result := r.db().Model(&entities.LessonTeacher{}).
+		Where("lesson_id = ? AND teacher_id = ?", lessonID, teacherID).
 		Count(&count)
+	return count > 0, result.Error
+}
 
-	if count > 0 {
-		return fmt.Errorf("student already enrolled in this lesson")
+// GetLessonMaterials loads the listed materials that belong to the lesson
+func (r *LessonRepository) GetLessonMaterials(lessonID uint, ids []uint) ([]entities.Material, error) {
+	var materials []entities.Material
+	result := r.db().Where("lesson_id = ? AND id IN ?", lessonID, ids).Find(&materials)
+	return materials, result.Error
+}
+
+// findLessons counts the filtered lessons and loads one page of them with the given associations
+func findLessons(query *gorm.DB, page pagination.Params, preloads ...string) ([]*entities.Lesson, pagination.Page, error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	query = page.Apply(query)
+	for _, preload := range preloads {
+		query = query.Preload(preload)
 	}
 
-	return common.DB.Model(lesson).Association("Students").Append(student)
+	var lessons []*entities.Lesson
+	if err := query.Find(&lessons).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	lessons, next := pagination.Trim(lessons, page, func(lesson *entities.Lesson) pagination.Cursor {
+		return lessonCursor(lesson, page.Key)
+	})
+	return lessons, pagination.Page{Total: total, NextCursor: next}, nil
 }
 
-func (r *LessonRepository) RemoveStudentFromLesson(lessonID uint, studentID uint) error {
-	lesson := &entities.Lesson{}
-	student := &entities.Student{}
+func lessonCursor(lesson *entities.Lesson, key string) pagination.Cursor {
+	cursor := pagination.Cursor{ID: lesson.ID}
+	switch key {
+	case "title":
+		cursor.Value = lesson.Title
+	case "created_at":
+		cursor.Value = lesson.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		cursor.Value = lesson.UpdatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = strconv.FormatUint(uint64(lesson.ID), 10)
+	}
+	return cursor
+}
+
+func isEnrolled(tx *gorm.DB, lessonID uint, studentID uint) (bool, error) {
+	var count int64
+	err := tx.Model(&entities.Enrollment{}).
+		Where("lesson_id = ? AND student_id = ?", lessonID, studentID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// EnrollInTransaction enrolls the student as part of another module's transaction, with the
+// same capacity check and enrollment history as a direct enrollment. A student who is already
+// enrolled is left as they are
+func EnrollInTransaction(tx *gorm.DB, lessonID uint, studentID uint) error {
+	enrolled, err := isEnrolled(tx, lessonID, studentID)
+	if err != nil || enrolled {
+		return err
+	}
 
-	if err := common.DB.First(lesson, lessonID).Error; err != nil {
+	return addEnrollment(tx, lessonID, studentID, nil)
+}
+
+// addEnrollment enrolls the student and records it in the enrollment history. The lesson
+// row is locked while active enrollments are counted, so concurrent enrollments cannot
+// overfill it; a capacity of zero means the lesson is unlimited
+func addEnrollment(tx *gorm.DB, lessonID uint, studentID uint, fromLessonID *uint) error {
+	var lesson entities.Lesson
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&lesson, lessonID).Error; err != nil {
 		return err
 	}
 
-	if err := common.DB.First(student, studentID).Error; err != nil {
+	if lesson.Capacity > 0 {
+		var active int64
+		if err := tx.Model(&entities.Enrollment{}).
+			Where("lesson_id = ? AND status = ?", lessonID, entities.EnrollmentActive).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= int64(lesson.Capacity) {
+			return ErrLessonFull
+		}
+	}
+
+	enrollment := &entities.Enrollment{
+		LessonID:  lessonID,
+		StudentID: studentID,
+		Status:    entities.EnrollmentActive,
+	}
+	if err := tx.Create(enrollment).Error; err != nil {
 		return err
 	}
 
-	return common.DB.Model(lesson).Association("Students").Delete(student)
+	event := &entities.EnrollmentEvent{
+		LessonID:     lessonID,
+		StudentID:    studentID,
+		FromLessonID: fromLessonID,
+		Type:         entities.EnrollmentEventEnrolled,
+	}
+	if fromLessonID != nil {
+		event.Type = entities.EnrollmentEventTransferred
+	}
+	return tx.Create(event).Error
 }
 
-func (r *LessonRepository) GetLessonStudents(lessonID uint) ([]entities.Student, error) {
-	var lesson entities.Lesson
-	if err := common.DB.Preload("Students").First(&lesson, lessonID).Error; err != nil {
+// setLeadTeacher makes teacherID the lesson's only lead, replacing the previous lead
+func setLeadTeacher(tx *gorm.DB, lessonID uint, teacherID uint) error {
+	if err := tx.First(&entities.Teacher{}, teacherID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Model(&entities.Lesson{}).Where("id = ?", lessonID).Update("teacher_id", teacherID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("lesson_id = ? AND role = ? AND teacher_id <> ?", lessonID, entities.LessonTeacherLead, teacherID).
+		Delete(&entities.LessonTeacher{}).Error; err != nil {
+		return err
+	}
+
+	lead := &entities.LessonTeacher{
+		LessonID:  lessonID,
+		TeacherID: teacherID,
+		Role:      entities.LessonTeacherLead,
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "lesson_id"}, {Name: "teacher_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"role"}),
+	}).Create(lead).Error
+}
+
+// GetCurrentTerm returns the term running at the given time, or nil when no term is running
+func (r *LessonRepository) GetCurrentTerm(now time.Time) (*entities.Term, error) {
+	var terms []*entities.Term
+	result := r.db().
+		Where("starts_on <= ? AND ends_on > ?", now, now).
+		Order("starts_on DESC").
+		Limit(1).
+		Find(&terms)
+	if result.Error != nil || len(terms) == 0 {
+		return nil, result.Error
+	}
+	return terms[0], nil
+}
+
+// FileCopy is a stored file a cloned row refers to, which still has to be copied to its new key
+type FileCopy struct {
+	From        string
+	To          string
+	ContentType string
+}
+
+// CloneLesson creates clone with the source's teachers, prerequisites, tags, schedule and materials, and optionally
+// its roster. A lead teacher set on clone replaces the source's lead. Copied file materials get keys of their own,
+// returned so the caller can copy the stored files before the transaction commits.
+func (r *LessonRepository) CloneLesson(source *entities.Lesson, clone *entities.Lesson, includeRoster bool) ([]FileCopy, error) {
+	var files []FileCopy
+	err := r.db().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(clone).Error; err != nil {
+			return err
+		}
+
+		if clone.TeacherID != 0 {
+			if err := setLeadTeacher(tx, clone.ID, clone.TeacherID); err != nil {
+				return err
+			}
+		}
+		for _, teacher := range source.Teachers {
+			if teacher.Role == entities.LessonTeacherLead || teacher.TeacherID == clone.TeacherID {
+				continue
+			}
+			lessonTeacher := &entities.LessonTeacher{
+				LessonID:  clone.ID,
+				TeacherID: teacher.TeacherID,
+				Role:      teacher.Role,
+			}
+			if err := tx.Create(lessonTeacher).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, prerequisite := range source.Prerequisites {
+			if err := tx.Exec(
+				"INSERT INTO lesson_prerequisites (lesson_id, prerequisite_id) VALUES (?, ?)",
+				clone.ID, prerequisite.ID,
+			).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(source.Tags) > 0 {
+			if err := tx.Model(clone).Association("Tags").Append(source.Tags); err != nil {
+				return err
+			}
+		}
+
+		// The schedule carries over as fresh events; cancelled sessions are left behind
+		var sessions []entities.LessonSession
+		if err := tx.Where("lesson_id = ? AND status = ?", source.ID, entities.SessionScheduled).Find(&sessions).Error; err != nil {
+			return err
+		}
+		for _, session := range sessions {
+			copied := &entities.LessonSession{
+				LessonID: clone.ID,
+				StartsAt: session.StartsAt,
+				EndsAt:   session.EndsAt,
+				Location: session.Location,
+				Status:   entities.SessionScheduled,
+			}
+			if err := tx.Create(copied).Error; err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if files, err = cloneMaterials(tx, source.ID, clone.ID); err != nil {
+			return err
+		}
+
+		if !includeRoster || len(source.Students) == 0 {
+			return nil
+		}
+
+		enrollments := make([]entities.Enrollment, 0, len(source.Students))
+		events := make([]entities.EnrollmentEvent, 0, len(source.Students))
+		for _, student := range source.Students {
+			enrollments = append(enrollments, entities.Enrollment{
+				LessonID:  clone.ID,
+				StudentID: student.ID,
+				Status:    entities.EnrollmentActive,
+			})
+			events = append(events, entities.EnrollmentEvent{
+				LessonID:  clone.ID,
+				StudentID: student.ID,
+				Type:      entities.EnrollmentEventEnrolled,
+			})
+		}
+		if err := tx.Create(&enrollments).Error; err != nil {
+			return err
+		}
+		return tx.Create(&events).Error
+	})
+	if err != nil {
 		return nil, err
 	}
-	return lesson.Students, nil
+
+	return files, nil
+}
+
+// cloneMaterials copies the source lesson's sections and materials into the clone, keeping their order
+func cloneMaterials(tx *gorm.DB, sourceID uint, cloneID uint) ([]FileCopy, error) {
+	var sections []entities.MaterialSection
+	if err := tx.Where("lesson_id = ?", sourceID).Order("position, id").Find(&sections).Error; err != nil {
+		return nil, err
+	}
+
+	sectionIDs := make(map[uint]uint, len(sections))
+	for _, section := range sections {
+		copied := &entities.MaterialSection{
+			LessonID: cloneID,
+			Title:    section.Title,
+			Position: section.Position,
+		}
+		if err := tx.Create(copied).Error; err != nil {
+			return nil, err
+		}
+		sectionIDs[section.ID] = copied.ID
+	}
+
+	var materials []entities.Material
+	if err := tx.Where("lesson_id = ?", sourceID).Order("position, id").Find(&materials).Error; err != nil {
+		return nil, err
+	}
+
+	var files []FileCopy
+	for _, material := range materials {
+		copied := material
+		copied.ID = 0
+		copied.LessonID = cloneID
+		copied.CreatedAt = time.Time{}
+		copied.UpdatedAt = time.Time{}
+		if material.SectionID != nil {
+			sectionID := sectionIDs[*material.SectionID]
+			copied.SectionID = &sectionID
+		}
+
+		// Each copy owns its file, so deleting one lesson's material can't take the other's with it
+		if material.StorageKey != "" {
+			key, err := storage.RandomKey(fmt.Sprintf("materials/%d", cloneID))
+			if err != nil {
+				return nil, err
+			}
+			copied.StorageKey = key
+			files = append(files, FileCopy{From: material.StorageKey, To: key, ContentType: material.ContentType})
+		}
+
+		if err := tx.Create(&copied).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return files, nil
+}
+
+// SearchLessons ranks lessons matching a to_tsquery expression and highlights the matches
+func (r *LessonRepository) SearchLessons(tsquery string, filter LessonFilter, page pagination.Params) ([]models.LessonSearchResult, pagination.Page, error) {
+	language, err := common.SearchLanguage()
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+	document := common.LessonSearchDocument(language)
+	query := fmt.Sprintf("to_tsquery('%s'::regconfig, ?)", language)
+
+	matches := filter.apply(r.db().Model(&entities.Lesson{})).
+		Select(fmt.Sprintf("lessons.id, lessons.title, lessons.description, ts_rank(%s, %s) AS rank", document, query), tsquery).
+		Where(fmt.Sprintf("%s @@ %s", document, query), tsquery)
+
+	results := r.db().Table("(?) AS results", matches).Session(&gorm.Session{})
+
+	var total int64
+	if err := results.Count(&total).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	// Headlines are only computed for the page; the markers are swapped for <mark> after escaping
+	var rows []searchRow
+	err = page.Apply(results).
+		Select(fmt.Sprintf(
+			"results.id, results.rank, "+
+				"ts_headline('%[1]s'::regconfig, results.title, %[2]s, ?) AS title_highlight, "+
+				"ts_headline('%[1]s'::regconfig, results.description, %[2]s, ?) AS snippet",
+			language, query,
+		), tsquery, titleHeadlineOptions, tsquery, snippetHeadlineOptions).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	rows, next := pagination.Trim(rows, page, func(row searchRow) pagination.Cursor {
+		return pagination.Cursor{Value: strconv.FormatFloat(float64(row.Rank), 'g', -1, 32), ID: row.ID}
+	})
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	var lessons []*entities.Lesson
+	if len(ids) > 0 {
+		if err := r.db().Preload("Teacher").Find(&lessons, ids).Error; err != nil {
+			return nil, pagination.Page{}, err
+		}
+	}
+	byID := make(map[uint]*entities.Lesson, len(lessons))
+	for _, lesson := range lessons {
+		byID[lesson.ID] = lesson
+	}
+
+	hits := make([]models.LessonSearchResult, 0, len(rows))
+	for _, row := range rows {
+		hits = append(hits, models.LessonSearchResult{
+			Lesson:         byID[row.ID],
+			Rank:           row.Rank,
+			TitleHighlight: highlight(row.TitleHighlight),
+			Snippet:        highlight(row.Snippet),
+		})
+	}
+	return hits, pagination.Page{Total: total, NextCursor: next}, nil
+}
+
+type searchRow struct {
+	ID             uint
+	Rank           float32
+	TitleHighlight string
+	Snippet        string
+}
+
+const (
+	highlightStart = "\x02"
+	highlightStop  = "\x03"
+
+	titleHeadlineOptions   = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true"
+	snippetHeadlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxFragments=2, MaxWords=30, MinWords=10"
+)
+
+var highlighter = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// highlight escapes a ts_headline result and turns its match markers into <mark> tags
+func highlight(headline string) string {
+	return highlighter.Replace(html.EscapeString(headline))
 }
@@ -15,6 +15,7 @@ func InitRoutes(router *mux.Router, handler *LessonHandler, authService auth.IAu
 	// Public endpoints (no auth required)
 	router.HandleFunc("/api/lessons/{lessonID:[0-9]+}", handler.Get).Methods(http.MethodGet)
 	router.HandleFunc("/api/lessons", handler.List).Methods(http.MethodGet)
+	router.HandleFunc("/api/lessons/search", handler.Search).Methods(http.MethodGet)
 
 	// Admin-only endpoints
 	adminRoutes := router.PathPrefix("/api/lessons").Subrouter()
@@ -24,13 +25,41 @@ func InitRoutes(router *mux.Router, handler *LessonHandler, authService auth.IAu
 	adminRoutes.HandleFunc("/{lessonID:[0-9]+}", handler.Update).Methods(http.MethodPut)
 	adminRoutes.HandleFunc("/{lessonID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
 	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/assign-teacher", handler.AssignTeacher).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/teachers", handler.AddTeacher).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/teachers/{teacherID:[0-9]+}", handler.RemoveTeacher).Methods(http.MethodDelete)
 	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/enroll-student", handler.EnrollStudent).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/prerequisites", handler.SetPrerequisites).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/tags", handler.SetTags).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/status", handler.ChangeStatus).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{lessonID:[0-9]+}/clone", handler.Clone).Methods(http.MethodPost)
+
+	// Admin views including unpublished lessons
+	adminLessonRoutes := router.PathPrefix("/api/admin/lessons").Subrouter()
+	adminLessonRoutes.Use(authMiddleware)
+	adminLessonRoutes.Use(middleware.RequireRole("admin"))
+	adminLessonRoutes.HandleFunc("", handler.ListAll).Methods(http.MethodGet)
+	adminLessonRoutes.HandleFunc("/{lessonID:[0-9]+}", handler.GetAny).Methods(http.MethodGet)
+	adminLessonRoutes.HandleFunc("/trash", handler.ListTrash).Methods(http.MethodGet)
+	adminLessonRoutes.HandleFunc("/{lessonID:[0-9]+}/restore", handler.Restore).Methods(http.MethodPost)
+	adminLessonRoutes.HandleFunc("/{lessonID:[0-9]+}/purge", handler.Purge).Methods(http.MethodDelete)
+
+	// Admin bulk enrollment operations
+	adminEnrollmentRoutes := router.PathPrefix("/api/admin/enrollments").Subrouter()
+	adminEnrollmentRoutes.Use(authMiddleware)
+	adminEnrollmentRoutes.Use(middleware.RequireRole("admin"))
+	adminEnrollmentRoutes.HandleFunc("/batch", handler.BatchEnrollment).Methods(http.MethodPost)
+	adminEnrollmentRoutes.HandleFunc("/transfer", handler.TransferStudent).Methods(http.MethodPost)
+	adminEnrollmentRoutes.HandleFunc("/history", handler.GetEnrollmentHistory).Methods(http.MethodGet)
 
 	// Teacher-only endpoints
 	teacherRoutes := router.PathPrefix("/api/teacher").Subrouter()
 	teacherRoutes.Use(authMiddleware)
 	teacherRoutes.Use(middleware.RequireRole("teacher"))
 	teacherRoutes.HandleFunc("/lessons", handler.GetTeacherLessons).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}", handler.GetForTeacher).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/enrollment-requests", handler.GetLessonEnrollmentRequests).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/enrollment-requests/{requestID:[0-9]+}/approve", handler.ApproveEnrollmentRequest).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/enrollment-requests/{requestID:[0-9]+}/reject", handler.RejectEnrollmentRequest).Methods(http.MethodPost)
 
 	lessonStudentRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/students").Subrouter()
 	lessonStudentRoutes.Use(authMiddleware)
@@ -38,10 +67,15 @@ func InitRoutes(router *mux.Router, handler *LessonHandler, authService auth.IAu
 	lessonStudentRoutes.HandleFunc("", handler.GetLessonStudents).Methods(http.MethodGet)
 	lessonStudentRoutes.HandleFunc("", handler.AddStudentToLesson).Methods(http.MethodPost)
 	lessonStudentRoutes.HandleFunc("/{studentID:[0-9]+}", handler.RemoveStudentFromLesson).Methods(http.MethodDelete)
+	lessonStudentRoutes.HandleFunc("/{studentID:[0-9]+}/complete", handler.CompleteEnrollment).Methods(http.MethodPost)
 
 	// Student-only endpoints
 	studentRoutes := router.PathPrefix("/api/student").Subrouter()
 	studentRoutes.Use(authMiddleware)
 	studentRoutes.Use(middleware.RequireRole("student"))
 	studentRoutes.HandleFunc("/lessons", handler.GetStudentLessons).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}", handler.GetForStudent).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/enroll", handler.SelfEnroll).Methods(http.MethodPost)
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/eligibility", handler.CheckEligibility).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/enrollment-requests", handler.GetStudentEnrollmentRequests).Methods(http.MethodGet)
 }
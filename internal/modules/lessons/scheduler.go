@@ -0,0 +1,55 @@
+package lessons
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// defaultPurgeAfterDays is how long deleted lessons stay in the trash unless LESSON_PURGE_AFTER_DAYS is set
+const defaultPurgeAfterDays = 30
+
+// StartScheduler periodically applies scheduled lesson transitions and empties the trash in the background
+func StartScheduler(service ILessonService, interval time.Duration) {
+	purgeAfter := purgeAfterFromEnv()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := service.ApplyScheduledTransitions(time.Now()); err != nil {
+				log.Println("⚠️  Failed to apply scheduled lesson transitions:", err)
+			}
+
+			if purgeAfter > 0 {
+				purged, err := service.PurgeDeletedLessons(purgeAfter)
+				if err != nil {
+					log.Println("⚠️  Failed to purge deleted lessons:", err)
+				} else if purged > 0 {
+					log.Println("🗑️  Purged deleted lessons:", purged)
+				}
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// purgeAfterFromEnv reads the trash retention period; zero or negative disables purging
+func purgeAfterFromEnv() time.Duration {
+	days := defaultPurgeAfterDays
+	if value := os.Getenv("LESSON_PURGE_AFTER_DAYS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			log.Println("⚠️  Invalid LESSON_PURGE_AFTER_DAYS, using default:", value)
+		} else {
+			days = parsed
+		}
+	}
+
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -2,31 +2,119 @@ package lessons
 
 import (
 	"errors"
+	"fmt"
 	"lesson-management/entities"
 	"lesson-management/models"
+	"lesson-management/pkg/pagination"
+	"lesson-management/pkg/storage"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotLessonTeacher       = errors.New("lesson does not belong to this teacher")
+	ErrNotLessonStudent       = errors.New("you are not enrolled in this lesson")
+	ErrAlreadyEnrolled        = errors.New("student already enrolled in this lesson")
+	ErrSelfEnrollmentDisabled = errors.New("lesson is not open for self-enrollment")
+	ErrEnrollmentClosed       = errors.New("enrollment window is closed")
+	ErrRequestAlreadyPending  = errors.New("enrollment request already pending")
+	ErrRequestNotPending      = errors.New("enrollment request is not pending")
+	ErrPrerequisiteCycle      = errors.New("prerequisites would create a cycle")
+	ErrOverrideReasonRequired = errors.New("override reason is required")
+	ErrLessonArchived         = errors.New("archived lessons are read-only")
+	ErrInvalidStatusChange    = errors.New("lesson status change is not allowed")
+	ErrInvalidTeacherRole     = errors.New("teacher role must be lead or co_teacher")
+	ErrLeadTeacherRequired    = errors.New("lesson must keep a lead teacher")
+	ErrEmptySearchQuery       = errors.New("search query is required")
+	ErrNotEnrolled            = errors.New("student is not enrolled in this lesson")
+	ErrLessonFull             = errors.New("lesson has reached its capacity")
+	ErrSameLesson             = errors.New("source and target lesson must differ")
+	ErrEmptyBatch             = errors.New("batch must contain at least one operation")
+	ErrInvalidOperation       = errors.New("invalid operation")
+	ErrBatchTooLarge          = fmt.Errorf("batches are limited to %d operations", MaxBatchOperations)
+	ErrInvalidFormat          = errors.New("format must be markdown or html")
 )
 
+// lessonTransitions lists the statuses each lesson status may move to
+var lessonTransitions = map[string][]string{
+	entities.LessonDraft:     {entities.LessonPublished, entities.LessonArchived},
+	entities.LessonPublished: {entities.LessonDraft, entities.LessonArchived},
+	entities.LessonArchived:  {},
+}
+
+// IneligibleError is returned when a student has not completed a lesson's prerequisites
+type IneligibleError struct {
+	Unmet []models.UnmetPrerequisite
+}
+
+func (e *IneligibleError) Error() string {
+	return "student has not completed the required prerequisites"
+}
+
 type ILessonService interface {
 	GetLesson(id uint64) (*entities.Lesson, error)
-	GetAllLessons() ([]*entities.Lesson, error)
+	GetAllLessons(filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
 	CreateLesson(lesson *models.CreateLessonRequest, teacherID uint) (*entities.Lesson, error)
 	UpdateLesson(lesson *models.PatchLessonRequest, id uint64) (*entities.Lesson, error)
 	DeleteLesson(id uint64) error
-	GetTeacherLessons(teacherID uint) ([]*entities.Lesson, error)
-	GetStudentLessons(studentID uint) ([]*entities.Lesson, error)
+	GetTeacherLessons(teacherID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
+	GetStudentLessons(studentID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
 	AssignTeacherToLesson(lessonID uint64, teacherID uint) error
 	EnrollStudentInLesson(lessonID uint64, studentID uint) error
-	RemoveStudentFromLesson(lessonID uint64, studentID uint) error
-	GetLessonStudents(lessonID uint64, teacherID uint) ([]entities.Student, error)
+	AddStudentToLesson(lessonID uint64, studentID uint, teacherID uint) error
+	RemoveStudentFromLesson(lessonID uint64, studentID uint, teacherID uint) error
+	UnenrollStudent(lessonID uint64, studentID uint) error
+	TransferStudent(fromLessonID uint64, toLessonID uint64, studentID uint) error
+	GetEnrollmentHistory(filter EnrollmentEventFilter, page pagination.Params) ([]entities.EnrollmentEvent, pagination.Page, error)
+	BatchEnrollment(request *models.BatchEnrollmentRequest) (*models.BatchEnrollmentResponse, error)
+	GetLessonStudents(lessonID uint64, teacherID uint, page pagination.Params) ([]entities.Student, pagination.Page, error)
+	SelfEnroll(lessonID uint64, studentID uint) (*entities.EnrollmentRequest, error)
+	GetStudentEnrollmentRequests(studentID uint) ([]*entities.EnrollmentRequest, error)
+	GetLessonEnrollmentRequests(lessonID uint64, teacherID uint, status string) ([]*entities.EnrollmentRequest, error)
+	ApproveEnrollmentRequest(requestID uint64, teacherID uint) (*entities.EnrollmentRequest, error)
+	RejectEnrollmentRequest(requestID uint64, teacherID uint) (*entities.EnrollmentRequest, error)
+	SetPrerequisites(lessonID uint64, prerequisiteIDs []uint) (*entities.Lesson, error)
+	SetLessonTags(lessonID uint64, names []string) (*entities.Lesson, error)
+	CheckEligibility(lessonID uint64, studentID uint) (*models.EligibilityResponse, error)
+	EnrollStudentWithOverride(lessonID uint64, studentID uint, adminID uint, reason string) error
+	CompleteEnrollment(lessonID uint64, studentID uint, teacherID uint) error
+	AutoCompleteEnrollment(lessonID uint64, studentID uint) error
+	GetPublishedLesson(id uint64) (*entities.Lesson, error)
+	RenderDescription(lesson *entities.Lesson, audience string) (string, error)
+	GetStudentLesson(lessonID uint64, studentID uint) (*entities.Lesson, error)
+	GetPublishedLessons(filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error)
+	ChangeLessonStatus(id uint64, status string) (*entities.Lesson, error)
+	ApplyScheduledTransitions(now time.Time) error
+	GetDeletedLessons() ([]*entities.Lesson, error)
+	RestoreLesson(id uint64) error
+	PurgeLesson(id uint64) error
+	PurgeDeletedLessons(olderThan time.Duration) (int64, error)
+	AddLessonTeacher(lessonID uint64, teacherID uint, role string) (*entities.Lesson, error)
+	RemoveLessonTeacher(lessonID uint64, teacherID uint) (*entities.Lesson, error)
+	IsLessonTeacher(lessonID uint64, teacherID uint) (bool, error)
+	TeacherLesson(lessonID uint64, teacherID uint) (*entities.Lesson, error)
+	EditableLesson(lessonID uint64, teacherID uint) (*entities.Lesson, error)
+	IsStudentEnrolled(lessonID uint64, studentID uint) (bool, error)
+	RequireEnrollment(lessonID uint64, studentID uint) error
+	GetEnrolledStudents(lessonID uint64) ([]entities.Student, error)
+	GetCurrentTerm() (*entities.Term, error)
+	CloneLesson(lessonID uint64, request *models.CloneLessonRequest) (*entities.Lesson, error)
+	SearchLessons(input string, filter LessonFilter, page pagination.Params) ([]models.LessonSearchResult, pagination.Page, error)
+	Transaction(fn func(service ILessonService) error) error
 }
 
 type LessonService struct {
-	repo ILessonRepository
+	repo  ILessonRepository
+	files storage.Storage
 }
 
-func NewLessonService(repo ILessonRepository) ILessonService {
+func NewLessonService(repo ILessonRepository, files storage.Storage) ILessonService {
 	return &LessonService{
-		repo: repo,
+		repo:  repo,
+		files: files,
 	}
 }
 
@@ -39,20 +127,132 @@ func (s *LessonService) GetLesson(id uint64) (*entities.Lesson, error) {
 	return &lesson, nil
 }
 
-func (s *LessonService) GetAllLessons() ([]*entities.Lesson, error) {
-	lessons, err := s.repo.GetAllLessons()
+func (s *LessonService) GetAllLessons(filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	return s.repo.GetAllLessons(filter, page)
+}
+
+// GetPublishedLesson hides drafts and archived lessons from public readers
+func (s *LessonService) GetPublishedLesson(id uint64) (*entities.Lesson, error) {
+	lesson, err := s.GetLesson(id)
 	if err != nil {
 		return nil, err
 	}
 
-	return lessons, err
+	if lesson.Status != entities.LessonPublished {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return lesson, nil
+}
+
+func (s *LessonService) GetPublishedLessons(filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	filter.Status = entities.LessonPublished
+	return s.repo.GetAllLessons(filter, page)
+}
+
+// GetCurrentTerm returns the running term, or nil when none is configured for today
+func (s *LessonService) GetCurrentTerm() (*entities.Term, error) {
+	return s.repo.GetCurrentTerm(time.Now())
+}
+
+func (s *LessonService) ChangeLessonStatus(id uint64, status string) (*entities.Lesson, error) {
+	lesson, err := s.repo.GetLesson(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransition(lesson.Status, status) {
+		return nil, ErrInvalidStatusChange
+	}
+
+	now := time.Now()
+	lesson.Status = status
+	switch status {
+	case entities.LessonPublished:
+		lesson.PublishedAt = &now
+		lesson.PublishAt = nil
+	case entities.LessonDraft:
+		lesson.UnpublishAt = nil
+	case entities.LessonArchived:
+		lesson.ArchivedAt = &now
+		lesson.PublishAt = nil
+		lesson.UnpublishAt = nil
+	}
+
+	err = s.repo.Transaction(func(repo ILessonRepository) error {
+		if err := repo.UpdateLesson(&lesson); err != nil {
+			return err
+		}
+		if status != entities.LessonPublished {
+			return nil
+		}
+
+		service := &LessonService{repo: repo, files: s.files}
+		return service.enrollCourseStudents(&lesson)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lesson, nil
+}
+
+// ApplyScheduledTransitions publishes and unpublishes lessons whose scheduled times have passed
+func (s *LessonService) ApplyScheduledTransitions(now time.Time) error {
+	err := s.repo.Transaction(func(repo ILessonRepository) error {
+		ids, err := repo.PublishScheduledLessons(now)
+		if err != nil {
+			return err
+		}
+
+		service := &LessonService{repo: repo, files: s.files}
+		for _, id := range ids {
+			lesson, err := repo.GetLesson(id)
+			if err != nil {
+				return err
+			}
+			if err := service.enrollCourseStudents(&lesson); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.UnpublishScheduledLessons(now)
+	return err
 }
 
 func (s *LessonService) CreateLesson(lessonRequest *models.CreateLessonRequest, teacherID uint) (*entities.Lesson, error) {
+	termID := lessonRequest.TermID
+	if termID == nil {
+		current, err := s.GetCurrentTerm()
+		if err != nil {
+			return nil, err
+		}
+		if current != nil {
+			termID = &current.ID
+		}
+	}
+
 	lesson := &entities.Lesson{
 		Title:       lessonRequest.Title,
 		Description: lessonRequest.Description,
 		TeacherID:   teacherID,
+		TermID:      termID,
+		SubjectID:   lessonRequest.SubjectID,
+
+		SelfEnrollment:     lessonRequest.SelfEnrollment,
+		RequiresApproval:   lessonRequest.RequiresApproval,
+		EnrollmentOpensAt:  lessonRequest.EnrollmentOpensAt,
+		EnrollmentClosesAt: lessonRequest.EnrollmentClosesAt,
+		Capacity:           lessonRequest.Capacity,
+
+		Status:      entities.LessonDraft,
+		PublishAt:   lessonRequest.PublishAt,
+		UnpublishAt: lessonRequest.UnpublishAt,
 	}
 
 	err := s.repo.CreateLesson(lesson)
@@ -64,9 +264,14 @@ func (s *LessonService) CreateLesson(lessonRequest *models.CreateLessonRequest,
 }
 
 func (s *LessonService) UpdateLesson(lessonRequest *models.PatchLessonRequest, id uint64) (*entities.Lesson, error) {
-	var lesson entities.Lesson
+	lesson, err := s.repo.GetLesson(uint(id))
+	if err != nil {
+		return nil, err
+	}
 
-	lesson.ID = uint(id)
+	if err := ensureEditable(&lesson); err != nil {
+		return nil, err
+	}
 
 	if lessonRequest.Title != nil {
 		lesson.Title = *lessonRequest.Title
@@ -77,12 +282,49 @@ func (s *LessonService) UpdateLesson(lessonRequest *models.PatchLessonRequest, i
 	if lessonRequest.TeacherID != nil {
 		lesson.TeacherID = *lessonRequest.TeacherID
 	}
+	if lessonRequest.TermID != nil {
+		lesson.TermID = lessonRequest.TermID
+		lesson.Term = nil
+	}
+	if lessonRequest.SubjectID != nil {
+		lesson.SubjectID = lessonRequest.SubjectID
+		lesson.Subject = nil
+	}
+	if lessonRequest.SelfEnrollment != nil {
+		lesson.SelfEnrollment = *lessonRequest.SelfEnrollment
+	}
+	if lessonRequest.RequiresApproval != nil {
+		lesson.RequiresApproval = *lessonRequest.RequiresApproval
+	}
+	if lessonRequest.EnrollmentOpensAt != nil {
+		lesson.EnrollmentOpensAt = lessonRequest.EnrollmentOpensAt
+	}
+	if lessonRequest.EnrollmentClosesAt != nil {
+		lesson.EnrollmentClosesAt = lessonRequest.EnrollmentClosesAt
+	}
+	if lessonRequest.Capacity != nil {
+		lesson.Capacity = *lessonRequest.Capacity
+	}
+	if lessonRequest.PublishAt != nil {
+		lesson.PublishAt = lessonRequest.PublishAt
+	}
+	if lessonRequest.UnpublishAt != nil {
+		lesson.UnpublishAt = lessonRequest.UnpublishAt
+	}
 
-	err := s.repo.UpdateLesson(&lesson)
+	err = s.repo.UpdateLesson(&lesson)
 	if err != nil {
 		return nil, err
 	}
 
+	// Keep the lead teacher assignment in sync with TeacherID
+	if lessonRequest.TeacherID != nil && *lessonRequest.TeacherID != 0 {
+		if err := s.repo.AssignTeacherToLesson(lesson.ID, lesson.TeacherID); err != nil {
+			return nil, err
+		}
+		return s.GetLesson(id)
+	}
+
 	return &lesson, nil
 }
 
@@ -90,36 +332,824 @@ func (s *LessonService) DeleteLesson(id uint64) error {
 	return s.repo.DeleteLesson(uint(id))
 }
 
-func (s *LessonService) GetTeacherLessons(teacherID uint) ([]*entities.Lesson, error) {
-	return s.repo.GetLessonsByTeacherID(teacherID)
+func (s *LessonService) GetDeletedLessons() ([]*entities.Lesson, error) {
+	return s.repo.GetDeletedLessons()
+}
+
+func (s *LessonService) RestoreLesson(id uint64) error {
+	return s.repo.RestoreLesson(uint(id))
+}
+
+func (s *LessonService) PurgeLesson(id uint64) error {
+	keys, err := s.repo.PurgeLesson(uint(id))
+	if err != nil {
+		return err
+	}
+
+	// The rows are gone either way, so files that fail to delete are only logged
+	s.discardFiles(keys)
+	return nil
+}
+
+// PurgeDeletedLessons permanently removes lessons that have been in the trash longer than olderThan
+func (s *LessonService) PurgeDeletedLessons(olderThan time.Duration) (int64, error) {
+	purged, keys, err := s.repo.PurgeDeletedLessons(time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+
+	s.discardFiles(keys)
+	return purged, nil
 }
 
-func (s *LessonService) GetStudentLessons(studentID uint) ([]*entities.Lesson, error) {
-	return s.repo.GetLessonsByStudentID(studentID)
+func (s *LessonService) GetTeacherLessons(teacherID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	return s.repo.GetLessonsByTeacherID(teacherID, filter, page)
+}
+
+func (s *LessonService) GetStudentLessons(studentID uint, filter LessonFilter, page pagination.Params) ([]*entities.Lesson, pagination.Page, error) {
+	return s.repo.GetLessonsByStudentID(studentID, filter, page)
 }
 
 func (s *LessonService) AssignTeacherToLesson(lessonID uint64, teacherID uint) error {
-	return s.repo.AssignTeacherToLesson(uint(lessonID), teacherID)
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return err
+	}
+
+	return s.repo.AssignTeacherToLesson(lesson.ID, teacherID)
 }
 
 func (s *LessonService) EnrollStudentInLesson(lessonID uint64, studentID uint) error {
-	return s.repo.EnrollStudentInLesson(uint(lessonID), studentID)
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return err
+	}
+
+	if err := s.checkPrerequisites(&lesson, studentID); err != nil {
+		return err
+	}
+
+	return s.repo.EnrollStudentInLesson(lesson.ID, studentID)
+}
+
+// AddStudentToLesson enrolls a student on behalf of one of the lesson's teachers
+func (s *LessonService) AddStudentToLesson(lessonID uint64, studentID uint, teacherID uint) error {
+	if err := s.ensureLessonTeacher(uint(lessonID), teacherID); err != nil {
+		return err
+	}
+
+	return s.EnrollStudentInLesson(lessonID, studentID)
+}
+
+func (s *LessonService) RemoveStudentFromLesson(lessonID uint64, studentID uint, teacherID uint) error {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureLessonTeacher(lesson.ID, teacherID); err != nil {
+		return err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return err
+	}
+
+	return s.repo.RemoveStudentFromLesson(lesson.ID, studentID)
+}
+
+// UnenrollStudent removes a student from a lesson on behalf of an admin
+func (s *LessonService) UnenrollStudent(lessonID uint64, studentID uint) error {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return err
+	}
+
+	enrolled, err := s.repo.IsStudentEnrolled(lesson.ID, studentID)
+	if err != nil {
+		return err
+	}
+	if !enrolled {
+		return ErrNotEnrolled
+	}
+
+	return s.repo.RemoveStudentFromLesson(lesson.ID, studentID)
 }
 
-func (s *LessonService) RemoveStudentFromLesson(lessonID uint64, studentID uint) error {
-	return s.repo.RemoveStudentFromLesson(uint(lessonID), studentID)
+// TransferStudent moves a student between lessons in one transaction. The target's
+// capacity and prerequisites apply as they would to a fresh enrollment
+func (s *LessonService) TransferStudent(fromLessonID uint64, toLessonID uint64, studentID uint) error {
+	if fromLessonID == toLessonID {
+		return ErrSameLesson
+	}
+
+	source, err := s.repo.GetLesson(uint(fromLessonID))
+	if err != nil {
+		return err
+	}
+	if err := ensureEditable(&source); err != nil {
+		return err
+	}
+
+	target, err := s.repo.GetLesson(uint(toLessonID))
+	if err != nil {
+		return err
+	}
+	if err := ensureEditable(&target); err != nil {
+		return err
+	}
+
+	if err := s.checkPrerequisites(&target, studentID); err != nil {
+		return err
+	}
+
+	return s.repo.TransferStudent(source.ID, target.ID, studentID)
 }
 
-func (s *LessonService) GetLessonStudents(lessonID uint64, teacherID uint) ([]entities.Student, error) {
+// GetEnrollmentHistory lists enrollment events, newest first by default
+func (s *LessonService) GetEnrollmentHistory(filter EnrollmentEventFilter, page pagination.Params) ([]entities.EnrollmentEvent, pagination.Page, error) {
+	return s.repo.GetEnrollmentEvents(filter, page)
+}
+
+// Transaction runs fn against a service whose reads and writes share one database transaction
+func (s *LessonService) Transaction(fn func(service ILessonService) error) error {
+	return s.repo.Transaction(func(repo ILessonRepository) error {
+		return fn(&LessonService{repo: repo, files: s.files})
+	})
+}
+
+func (s *LessonService) GetLessonStudents(lessonID uint64, teacherID uint, page pagination.Params) ([]entities.Student, pagination.Page, error) {
 	// Verify lesson belongs to teacher
 	lesson, err := s.repo.GetLesson(uint(lessonID))
 	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	if err := s.ensureLessonTeacher(lesson.ID, teacherID); err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	return s.repo.GetLessonStudents(uint(lessonID), page)
+}
+
+// SelfEnroll enrolls a student directly, or queues a request when the lesson requires approval.
+// The returned request is nil when the student was enrolled immediately.
+func (s *LessonService) SelfEnroll(lessonID uint64, studentID uint) (*entities.EnrollmentRequest, error) {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	if lesson.Status == entities.LessonDraft {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := ensureEditable(&lesson); err != nil {
+		return nil, err
+	}
+
+	if !lesson.SelfEnrollment {
+		return nil, ErrSelfEnrollmentDisabled
+	}
+	if !lesson.EnrollmentOpen(time.Now()) {
+		return nil, ErrEnrollmentClosed
+	}
+
+	enrolled, err := s.repo.IsStudentEnrolled(lesson.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, ErrAlreadyEnrolled
+	}
+
+	if err := s.checkPrerequisites(&lesson, studentID); err != nil {
+		return nil, err
+	}
+
+	if !lesson.RequiresApproval {
+		return nil, s.repo.EnrollStudentInLesson(lesson.ID, studentID)
+	}
+
+	pending, err := s.repo.GetPendingEnrollmentRequest(lesson.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrRequestAlreadyPending
+	}
+
+	request := &entities.EnrollmentRequest{
+		LessonID:  lesson.ID,
+		StudentID: studentID,
+		Status:    entities.EnrollmentRequestPending,
+	}
+	if err := s.repo.CreateEnrollmentRequest(request); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *LessonService) GetStudentEnrollmentRequests(studentID uint) ([]*entities.EnrollmentRequest, error) {
+	return s.repo.GetEnrollmentRequestsByStudentID(studentID)
+}
+
+func (s *LessonService) GetLessonEnrollmentRequests(lessonID uint64, teacherID uint, status string) ([]*entities.EnrollmentRequest, error) {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureLessonTeacher(lesson.ID, teacherID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetEnrollmentRequestsByLessonID(lesson.ID, status)
+}
+
+func (s *LessonService) ApproveEnrollmentRequest(requestID uint64, teacherID uint) (*entities.EnrollmentRequest, error) {
+	request, err := s.getTeacherEnrollmentRequest(requestID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	lesson, err := s.repo.GetLesson(request.LessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return nil, err
+	}
+
+	// Prerequisites may have changed since the request was queued
+	if err := s.checkPrerequisites(&lesson, request.StudentID); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ApproveEnrollmentRequest(request, teacherID); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *LessonService) RejectEnrollmentRequest(requestID uint64, teacherID uint) (*entities.EnrollmentRequest, error) {
+	request, err := s.getTeacherEnrollmentRequest(requestID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RejectEnrollmentRequest(request, teacherID); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// getTeacherEnrollmentRequest loads a pending request and verifies it targets one of the teacher's lessons
+func (s *LessonService) getTeacherEnrollmentRequest(requestID uint64, teacherID uint) (*entities.EnrollmentRequest, error) {
+	request, err := s.repo.GetEnrollmentRequest(uint(requestID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureLessonTeacher(request.LessonID, teacherID); err != nil {
+		return nil, err
+	}
+
+	if request.Status != entities.EnrollmentRequestPending {
+		return nil, ErrRequestNotPending
+	}
+
+	return &request, nil
+}
+
+func (s *LessonService) SetPrerequisites(lessonID uint64, prerequisiteIDs []uint) (*entities.Lesson, error) {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(prerequisiteIDs))
+	seen := make(map[uint]bool)
+	for _, id := range prerequisiteIDs {
+		if id == lesson.ID {
+			return nil, ErrPrerequisiteCycle
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	graph, err := s.repo.GetPrerequisiteGraph()
+	if err != nil {
+		return nil, err
+	}
+	if createsPrerequisiteCycle(graph, lesson.ID, ids) {
+		return nil, ErrPrerequisiteCycle
+	}
+
+	if err := s.repo.SetPrerequisites(lesson.ID, ids); err != nil {
+		return nil, err
+	}
+
+	return s.GetLesson(lessonID)
+}
+
+// SetLessonTags replaces the lesson's tags; names are normalized and deduplicated
+func (s *LessonService) SetLessonTags(lessonID uint64, names []string) (*entities.Lesson, error) {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetLessonTags(lesson.ID, normalizeTagNames(names)); err != nil {
+		return nil, err
+	}
+
+	return s.GetLesson(lessonID)
+}
+
+func (s *LessonService) CheckEligibility(lessonID uint64, studentID uint) (*models.EligibilityResponse, error) {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	unmet, err := s.unmetPrerequisites(&lesson, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.EligibilityResponse{
+		Eligible:           len(unmet) == 0,
+		UnmetPrerequisites: unmet,
+	}, nil
+}
+
+// EnrollStudentWithOverride lets an admin bypass prerequisites; the reason is kept for auditing
+func (s *LessonService) EnrollStudentWithOverride(lessonID uint64, studentID uint, adminID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrOverrideReasonRequired
+	}
+
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return err
+	}
+
+	unmet, err := s.unmetPrerequisites(&lesson, studentID)
+	if err != nil {
+		return err
+	}
+	if len(unmet) == 0 {
+		return s.repo.EnrollStudentInLesson(lesson.ID, studentID)
+	}
+
+	override := &entities.PrerequisiteOverride{
+		LessonID:  lesson.ID,
+		StudentID: studentID,
+		AdminID:   adminID,
+		Reason:    reason,
+	}
+	return s.repo.EnrollStudentWithOverride(lesson.ID, studentID, override)
+}
+
+func (s *LessonService) CompleteEnrollment(lessonID uint64, studentID uint, teacherID uint) error {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return err
+	}
+
+	if err := s.ensureLessonTeacher(lesson.ID, teacherID); err != nil {
+		return err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return err
+	}
+
+	return s.repo.CompleteEnrollment(lesson.ID, studentID)
+}
+
+// AutoCompleteEnrollment marks the student's enrollment completed once they have worked through
+// enough of the lesson. Unlike CompleteEnrollment it leaves enrollments that are no longer
+// active as they are
+func (s *LessonService) AutoCompleteEnrollment(lessonID uint64, studentID uint) error {
+	enrollments, err := s.repo.GetStudentEnrollments(studentID, []uint{uint(lessonID)})
+	if err != nil {
+		return err
+	}
+
+	if len(enrollments) == 0 || enrollments[0].Status != entities.EnrollmentActive {
+		return nil
+	}
+
+	return s.repo.CompleteEnrollment(uint(lessonID), studentID)
+}
+
+// enrollCourseStudents gives a lesson that was just published the students of every course it
+// belongs to, as if they had enrolled in the course now. Students who don't meet its
+// prerequisites are left out, and so is everyone once the lesson is full
+func (s *LessonService) enrollCourseStudents(lesson *entities.Lesson) error {
+	studentIDs, err := s.repo.GetCourseStudentIDs(lesson.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, studentID := range studentIDs {
+		unmet, err := s.unmetPrerequisites(lesson, studentID)
+		if err != nil {
+			return err
+		}
+		if len(unmet) > 0 {
+			continue
+		}
+
+		err = s.repo.EnrollCourseStudent(lesson.ID, studentID)
+		if errors.Is(err, ErrLessonFull) {
+			fmt.Println("Lesson is full, remaining course students were not enrolled: ", lesson.ID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *LessonService) checkPrerequisites(lesson *entities.Lesson, studentID uint) error {
+	unmet, err := s.unmetPrerequisites(lesson, studentID)
+	if err != nil {
+		return err
+	}
+
+	if len(unmet) > 0 {
+		return &IneligibleError{Unmet: unmet}
+	}
+
+	return nil
+}
+
+func (s *LessonService) unmetPrerequisites(lesson *entities.Lesson, studentID uint) ([]models.UnmetPrerequisite, error) {
+	unmet := []models.UnmetPrerequisite{}
+	if len(lesson.Prerequisites) == 0 {
+		return unmet, nil
+	}
+
+	ids := make([]uint, 0, len(lesson.Prerequisites))
+	for _, prerequisite := range lesson.Prerequisites {
+		ids = append(ids, prerequisite.ID)
+	}
+
+	enrollments, err := s.repo.GetStudentEnrollments(studentID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[uint]string, len(enrollments))
+	for _, enrollment := range enrollments {
+		statuses[enrollment.LessonID] = enrollment.Status
+	}
+
+	for _, prerequisite := range lesson.Prerequisites {
+		status, enrolled := statuses[prerequisite.ID]
+		switch {
+		case !enrolled:
+			unmet = append(unmet, models.UnmetPrerequisite{LessonID: prerequisite.ID, Title: prerequisite.Title, Reason: "not_enrolled"})
+		case status != entities.EnrollmentCompleted:
+			unmet = append(unmet, models.UnmetPrerequisite{LessonID: prerequisite.ID, Title: prerequisite.Title, Reason: "not_completed"})
+		}
+	}
+
+	return unmet, nil
+}
+
+func (s *LessonService) AddLessonTeacher(lessonID uint64, teacherID uint, role string) (*entities.Lesson, error) {
+	if role == "" {
+		role = entities.LessonTeacherCo
+	}
+	if role != entities.LessonTeacherLead && role != entities.LessonTeacherCo {
+		return nil, ErrInvalidTeacherRole
+	}
+
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
+		return nil, err
+	}
+
+	// Demoting the lead would leave the lesson without one
+	if role == entities.LessonTeacherCo && lesson.TeacherID == teacherID {
+		return nil, ErrLeadTeacherRequired
+	}
+
+	lessonTeacher := &entities.LessonTeacher{
+		LessonID:  lesson.ID,
+		TeacherID: teacherID,
+		Role:      role,
+	}
+	if err := s.repo.AddLessonTeacher(lessonTeacher); err != nil {
+		return nil, err
+	}
+
+	return s.GetLesson(lessonID)
+}
+
+func (s *LessonService) RemoveLessonTeacher(lessonID uint64, teacherID uint) (*entities.Lesson, error) {
+	lesson, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureEditable(&lesson); err != nil {
 		return nil, err
 	}
 
-	if lesson.TeacherID != teacherID {
-		return nil, errors.New("lesson does not belong to this teacher")
+	if lesson.TeacherID == teacherID {
+		return nil, ErrLeadTeacherRequired
+	}
+
+	if err := s.repo.RemoveLessonTeacher(lesson.ID, teacherID); err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetLessonStudents(uint(lessonID))
+	return s.GetLesson(lessonID)
+}
+
+// CloneLesson deep-copies a lesson into a new draft, applying any overrides from the request
+func (s *LessonService) CloneLesson(lessonID uint64, request *models.CloneLessonRequest) (*entities.Lesson, error) {
+	source, err := s.repo.GetLesson(uint(lessonID))
+	if err != nil {
+		return nil, err
+	}
+
+	clone := &entities.Lesson{
+		Title:             source.Title,
+		Description:       source.Description,
+		TeacherID:         source.TeacherID,
+		TermID:            source.TermID,
+		SubjectID:         source.SubjectID,
+		SelfEnrollment:    source.SelfEnrollment,
+		RequiresApproval:  source.RequiresApproval,
+		Capacity:          source.Capacity,
+		GradingScaleID:    source.GradingScaleID,
+		CompletionPercent: source.CompletionPercent,
+		Status:            entities.LessonDraft,
+	}
+	if request.Title != nil {
+		clone.Title = *request.Title
+	}
+	if request.Description != nil {
+		clone.Description = *request.Description
+	}
+	if request.TeacherID != nil {
+		clone.TeacherID = *request.TeacherID
+	}
+	if request.TermID != nil {
+		clone.TermID = request.TermID
+	}
+
+	// Files are copied inside the transaction so a failed copy leaves no clone behind
+	var copied []string
+	err = s.repo.Transaction(func(repo ILessonRepository) error {
+		files, err := repo.CloneLesson(&source, clone, request.IncludeRoster)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range files {
+			if err := s.copyFile(file); err != nil {
+				return err
+			}
+			copied = append(copied, file.To)
+		}
+		return nil
+	})
+	if err != nil {
+		s.discardFiles(copied)
+		return nil, err
+	}
+
+	return s.GetLesson(uint64(clone.ID))
+}
+
+func (s *LessonService) copyFile(file FileCopy) error {
+	object, err := s.files.Open(file.From)
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+
+	return s.files.Put(file.To, object, file.ContentType)
+}
+
+// discardFiles deletes stored files whose rows are gone; failures are only logged
+func (s *LessonService) discardFiles(keys []string) {
+	for _, key := range keys {
+		if err := s.files.Delete(key); err != nil {
+			fmt.Println("Error while deleting lesson file: ", err)
+		}
+	}
+}
+
+// IsLessonTeacher reports whether the teacher holds any role on the lesson
+func (s *LessonService) IsLessonTeacher(lessonID uint64, teacherID uint) (bool, error) {
+	return s.repo.IsLessonTeacher(uint(lessonID), teacherID)
+}
+
+// TeacherLesson loads the lesson for one of its teachers. Other modules use it to guard
+// their teacher endpoints
+func (s *LessonService) TeacherLesson(lessonID uint64, teacherID uint) (*entities.Lesson, error) {
+	lesson, err := s.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureLessonTeacher(lesson.ID, teacherID); err != nil {
+		return nil, err
+	}
+
+	return lesson, nil
+}
+
+// EditableLesson is TeacherLesson for changes, which archived lessons no longer accept
+func (s *LessonService) EditableLesson(lessonID uint64, teacherID uint) (*entities.Lesson, error) {
+	lesson, err := s.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureEditable(lesson); err != nil {
+		return nil, err
+	}
+
+	return lesson, nil
+}
+
+// IsStudentEnrolled reports whether the student is enrolled in the lesson, whether or not the enrollment is completed
+func (s *LessonService) IsStudentEnrolled(lessonID uint64, studentID uint) (bool, error) {
+	return s.repo.IsStudentEnrolled(uint(lessonID), studentID)
+}
+
+// GetStudentLesson loads the lesson for one of its enrolled students
+func (s *LessonService) GetStudentLesson(lessonID uint64, studentID uint) (*entities.Lesson, error) {
+	lesson, err := s.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.RequireEnrollment(lessonID, studentID); err != nil {
+		return nil, err
+	}
+
+	// Students see the lesson, not their classmates
+	lesson.Students = nil
+	return lesson, nil
+}
+
+// RequireEnrollment fails with ErrNotLessonStudent unless the student is enrolled in the lesson
+func (s *LessonService) RequireEnrollment(lessonID uint64, studentID uint) error {
+	enrolled, err := s.IsStudentEnrolled(lessonID, studentID)
+	if err != nil {
+		return err
+	}
+
+	if !enrolled {
+		return ErrNotLessonStudent
+	}
+	return nil
+}
+
+// GetEnrolledStudents returns everyone enrolled in the lesson, ordered by name, for rosters
+// that list the whole class at once
+func (s *LessonService) GetEnrolledStudents(lessonID uint64) ([]entities.Student, error) {
+	return s.repo.GetEnrolledStudents(uint(lessonID))
+}
+
+func (s *LessonService) ensureLessonTeacher(lessonID uint, teacherID uint) error {
+	ok, err := s.repo.IsLessonTeacher(lessonID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrNotLessonTeacher
+	}
+	return nil
+}
+
+func ensureEditable(lesson *entities.Lesson) error {
+	if lesson.Status == entities.LessonArchived {
+		return ErrLessonArchived
+	}
+	return nil
+}
+
+func canTransition(from string, to string) bool {
+	for _, allowed := range lessonTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// createsPrerequisiteCycle reports whether lessonID is reachable from any of the given prerequisites
+func createsPrerequisiteCycle(graph map[uint][]uint, lessonID uint, prerequisiteIDs []uint) bool {
+	visited := make(map[uint]bool)
+	stack := append([]uint{}, prerequisiteIDs...)
+
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if id == lessonID {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, graph[id]...)
+	}
+
+	return false
+}
+
+// maxSearchTerms keeps generated tsquery expressions small
+const maxSearchTerms = 10
+
+// SearchLessons runs a prefix-matching full-text search over published lessons
+func (s *LessonService) SearchLessons(input string, filter LessonFilter, page pagination.Params) ([]models.LessonSearchResult, pagination.Page, error) {
+	tsquery := prefixQuery(input)
+	if tsquery == "" {
+		return nil, pagination.Page{}, ErrEmptySearchQuery
+	}
+
+	filter.Status = entities.LessonPublished
+	return s.repo.SearchLessons(tsquery, filter, page)
+}
+
+// prefixQuery turns free text into a to_tsquery expression where every word matches as a prefix.
+// Anything that is not a letter or digit is dropped so user input can never break the tsquery syntax
+func prefixQuery(input string) string {
+	words := strings.FieldsFunc(input, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, word+":*")
+	}
+	return strings.Join(terms, " & ")
+}
+
+// normalizeTagNames normalizes tag names, dropping blanks and duplicates
+func normalizeTagNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = entities.NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
 }
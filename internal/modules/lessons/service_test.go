@@ -0,0 +1,65 @@
+package lessons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCreatesPrerequisiteCycle(t *testing.T) {
+	// 2 requires 1, 3 requires 2, 4 requires 2 and 3
+	graph := map[uint][]uint{
+		2: {1},
+		3: {2},
+		4: {2, 3},
+	}
+
+	for _, test := range []struct {
+		name            string
+		lessonID        uint
+		prerequisiteIDs []uint
+		want            bool
+	}{
+		{"no prerequisites", 1, nil, false},
+		{"requires itself", 5, []uint{5}, true},
+		{"direct cycle", 1, []uint{2}, true},
+		{"indirect cycle", 1, []uint{4}, true},
+		{"shared ancestor is not a cycle", 5, []uint{3, 4}, false},
+		{"replacing an edge of the chain", 3, []uint{1}, false},
+		{"unknown prerequisite", 1, []uint{99}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := createsPrerequisiteCycle(graph, test.lessonID, test.prerequisiteIDs); got != test.want {
+				t.Errorf("createsPrerequisiteCycle(%d, %v) = %v, want %v", test.lessonID, test.prerequisiteIDs, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPrefixQuery(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"single word", "algebra", "algebra:*"},
+		{"several words", "  linear   algebra ", "linear:* & algebra:*"},
+		{"operators are dropped", "a & b | !c <-> (d):* 'e'", "a:* & b:* & c:* & d:* & e:*"},
+		{"letters and digits", "Größe 101", "Größe:* & 101:*"},
+		{"punctuation splits words", "c++/go-lang", "c:* & go:* & lang:*"},
+		{"nothing searchable", " &|!():* ", ""},
+		{"empty", "", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := prefixQuery(test.input); got != test.want {
+				t.Errorf("prefixQuery(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPrefixQueryLimitsTerms(t *testing.T) {
+	input := strings.Repeat("word ", maxSearchTerms+5)
+	if got := strings.Count(prefixQuery(input), ":*"); got != maxSearchTerms {
+		t.Errorf("prefixQuery() kept %d terms, want %d", got, maxSearchTerms)
+	}
+}
@@ -0,0 +1,638 @@
+package materials
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"lesson-management/pkg/storage"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+const (
+	// multipartOverheadBytes allows for the form fields, boundaries and headers around an uploaded file
+	multipartOverheadBytes = 256 << 10
+	// maxFieldBytes bounds a single text field of a material upload
+	maxFieldBytes = 64 << 10
+)
+
+var errInvalidForm = errors.New("invalid material upload")
+
+type MaterialHandler struct {
+	service        IMaterialService
+	maxUploadBytes int64
+}
+
+func NewMaterialHandler(service IMaterialService, maxUploadBytes int64) *MaterialHandler {
+	return &MaterialHandler{
+		service:        service,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// Teacher handlers
+func (h *MaterialHandler) List(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	materials, err := h.service.GetLessonMaterials(lessonID, teacherID)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch lesson materials", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching lesson materials: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(materials)
+}
+
+func (h *MaterialHandler) CreateSection(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.MaterialSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	section, err := h.service.CreateSection(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create material section", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating material section: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(section)
+}
+
+func (h *MaterialHandler) UpdateSection(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	sectionID, ok := pathID(w, r, "sectionID", "Invalid section ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.MaterialSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	section, err := h.service.UpdateSection(lessonID, sectionID, teacherID, &requestBody)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update material section", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating material section: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(section)
+}
+
+func (h *MaterialHandler) DeleteSection(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	sectionID, ok := pathID(w, r, "sectionID", "Invalid section ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteSection(lessonID, sectionID, teacherID); err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete material section", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting material section: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MaterialHandler) ReorderSections(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.ReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	materials, err := h.service.ReorderSections(lessonID, teacherID, requestBody.IDs)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to reorder material sections", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while reordering material sections: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(materials)
+}
+
+// ReorderMaterials orders the materials of the section in the URL, or those outside any section
+func (h *MaterialHandler) ReorderMaterials(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	var sectionID *uint64
+	if _, inSection := mux.Vars(r)["sectionID"]; inSection {
+		id, ok := pathID(w, r, "sectionID", "Invalid section ID")
+		if !ok {
+			return
+		}
+		sectionID = &id
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.ReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	materials, err := h.service.ReorderMaterials(lessonID, sectionID, teacherID, requestBody.IDs)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to reorder materials", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while reordering materials: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(materials)
+}
+
+func (h *MaterialHandler) CreateLink(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.CreateLinkMaterialRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	material, err := h.service.CreateLink(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create link material", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating link material: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(material)
+}
+
+// CreateFile accepts a multipart upload with optional title, description, section_id and
+// release_at (RFC 3339) fields followed by the "file" field. The file is streamed straight to
+// storage, so the other fields must come before it
+func (h *MaterialHandler) CreateFile(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadBytes+multipartOverheadBytes)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		http.Error(w, "Content-Type must be multipart/form-data", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var requestBody models.CreateFileMaterialRequest
+	part, err := readFileForm(reader, &requestBody)
+	if err != nil {
+		http.Error(w, "Multipart upload must send title, description, section_id and release_at before a file field", http.StatusBadRequest)
+		return
+	}
+	defer part.Close()
+
+	material, err := h.service.CreateFile(lessonID, teacherID, &requestBody, part.FileName(), part)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to upload material", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while uploading material: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(material)
+}
+
+func (h *MaterialHandler) Update(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	materialID, ok := pathID(w, r, "materialID", "Invalid material ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.PatchMaterialRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	material, err := h.service.UpdateMaterial(lessonID, materialID, teacherID, &requestBody)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update material", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating material: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(material)
+}
+
+func (h *MaterialHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	materialID, ok := pathID(w, r, "materialID", "Invalid material ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteMaterial(lessonID, materialID, teacherID); err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete material", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting material: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *MaterialHandler) Download(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	materialID, ok := pathID(w, r, "materialID", "Invalid material ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	material, object, err := h.service.OpenMaterial(lessonID, materialID, teacherID)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to open material", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while opening material: ", err)
+		return
+	}
+	defer object.Close()
+
+	storage.Serve(w, r, object, material.FileName, material.ContentType, material.UpdatedAt, material.Checksum)
+}
+
+func (h *MaterialHandler) GetDownloadStats(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	materialID, ok := pathID(w, r, "materialID", "Invalid material ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	stats, err := h.service.GetDownloadStats(lessonID, materialID, teacherID)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch material downloads", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching material downloads: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(stats)
+}
+
+// Student handlers
+func (h *MaterialHandler) ListForStudent(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	materials, err := h.service.GetStudentMaterials(lessonID, studentID)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch student materials", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student materials: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(materials)
+}
+
+// Open serves a file material, or redirects to a link material, and records the download
+func (h *MaterialHandler) Open(w http.ResponseWriter, r *http.Request) {
+	materialID, ok := pathID(w, r, "materialID", "Invalid material ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	material, object, err := h.service.OpenStudentMaterial(materialID, studentID)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to open material", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while opening material: ", err)
+		return
+	}
+
+	if object == nil {
+		http.Redirect(w, r, material.URL, http.StatusFound)
+		return
+	}
+	defer object.Close()
+
+	storage.Serve(w, r, object, material.FileName, material.ContentType, material.UpdatedAt, material.Checksum)
+}
+
+func (h *MaterialHandler) GetDownloadURL(w http.ResponseWriter, r *http.Request) {
+	materialID, ok := pathID(w, r, "materialID", "Invalid material ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	link, err := h.service.GetStudentMaterialURL(materialID, studentID)
+	if err != nil {
+		if status := materialErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to sign material URL", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while signing material URL: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(link)
+}
+
+// readFileForm reads the text fields into request and returns the file part that follows them
+func readFileForm(reader *multipart.Reader, request *models.CreateFileMaterialRequest) (*multipart.Part, error) {
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == "file" && part.FileName() != "" {
+			return part, nil
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxFieldBytes))
+		part.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		switch part.FormName() {
+		case "title":
+			request.Title = string(value)
+		case "description":
+			request.Description = string(value)
+		case "section_id":
+			if len(value) == 0 {
+				continue
+			}
+			id, err := strconv.ParseUint(string(value), 10, 64)
+			if err != nil {
+				return nil, errInvalidForm
+			}
+			sectionID := uint(id)
+			request.SectionID = &sectionID
+		case "release_at":
+			if len(value) == 0 {
+				continue
+			}
+			releaseAt, err := time.Parse(time.RFC3339, string(value))
+			if err != nil {
+				return nil, errInvalidForm
+			}
+			request.ReleaseAt = &releaseAt
+		}
+	}
+}
+
+// pathID parses a numeric route variable, answering 400 with message when it is invalid
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		http.Error(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// materialErrorStatus maps service errors to HTTP status codes
+func materialErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, storage.ErrFileTooLarge),
+		errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, ErrMaterialFileMissing):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher),
+		errors.Is(err, lessons.ErrNotLessonStudent):
+		return http.StatusForbidden
+	case errors.Is(err, ErrSectionTitleRequired),
+		errors.Is(err, ErrInvalidOrder),
+		errors.Is(err, ErrTitleRequired),
+		errors.Is(err, ErrInvalidURL),
+		errors.Is(err, ErrNotLink),
+		errors.Is(err, ErrNotFile),
+		errors.Is(err, storage.ErrEmptyFile):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSectionNotEmpty),
+		errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
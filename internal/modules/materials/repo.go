@@ -0,0 +1,257 @@
+package materials
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/models"
+	"lesson-management/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IMaterialRepository interface {
+	GetSections(lessonID uint, releasedAt *time.Time) ([]entities.MaterialSection, error)
+	GetSection(id uint) (entities.MaterialSection, error)
+	CreateSection(section *entities.MaterialSection) error
+	UpdateSection(section *entities.MaterialSection) error
+	DeleteSection(id uint) error
+	ReorderSections(lessonID uint, sectionIDs []uint) error
+	GetUnsectionedMaterials(lessonID uint, releasedAt *time.Time) ([]entities.Material, error)
+	GetMaterial(id uint) (entities.Material, error)
+	CreateMaterial(material *entities.Material) error
+	UpdateMaterial(material *entities.Material, moved bool) error
+	DeleteMaterial(id uint) error
+	ReorderMaterials(lessonID uint, sectionID *uint, materialIDs []uint) error
+	RecordDownload(download *entities.MaterialDownload) error
+	GetDownloadStats(lessonID uint, materialID uint) ([]models.MaterialDownloadStat, error)
+}
+
+type MaterialRepository struct{}
+
+func NewMaterialRepository() IMaterialRepository {
+	return &MaterialRepository{}
+}
+
+// GetSections returns the lesson's sections with their materials in order. With releasedAt
+// set, only materials released by then are included and sections left empty are dropped
+func (r *MaterialRepository) GetSections(lessonID uint, releasedAt *time.Time) ([]entities.MaterialSection, error) {
+	var sections []entities.MaterialSection
+	result := common.DB.
+		Preload("Materials", func(db *gorm.DB) *gorm.DB {
+			return released(db, releasedAt).Order("position, id")
+		}).
+		Where("lesson_id = ?", lessonID).
+		Order("position, id").
+		Find(&sections)
+	if result.Error != nil || releasedAt == nil {
+		return sections, result.Error
+	}
+
+	visible := sections[:0]
+	for _, section := range sections {
+		if len(section.Materials) > 0 {
+			visible = append(visible, section)
+		}
+	}
+	return visible, nil
+}
+
+func (r *MaterialRepository) GetSection(id uint) (entities.MaterialSection, error) {
+	var section entities.MaterialSection
+	result := common.DB.First(&section, id)
+	return section, result.Error
+}
+
+// CreateSection appends the section to the end of its lesson
+func (r *MaterialRepository) CreateSection(section *entities.MaterialSection) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&entities.MaterialSection{}).
+			Where("lesson_id = ?", section.LessonID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		section.Position = last + 1
+		return tx.Omit(clause.Associations).Create(section).Error
+	})
+}
+
+func (r *MaterialRepository) UpdateSection(section *entities.MaterialSection) error {
+	result := common.DB.Omit(clause.Associations).Save(section)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteSection refuses to delete a section that still holds materials
+func (r *MaterialRepository) DeleteSection(id uint) error {
+	var count int64
+	if err := common.DB.Model(&entities.Material{}).Where("section_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSectionNotEmpty
+	}
+
+	return common.DB.Delete(&entities.MaterialSection{}, id).Error
+}
+
+func (r *MaterialRepository) ReorderSections(lessonID uint, sectionIDs []uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&entities.MaterialSection{}).Where("lesson_id = ?", lessonID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !common.SameIDs(existing, sectionIDs) {
+			return ErrInvalidOrder
+		}
+
+		for position, id := range sectionIDs {
+			if err := tx.Model(&entities.MaterialSection{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetUnsectionedMaterials returns the lesson's materials outside any section, released by
+// releasedAt when it is set
+func (r *MaterialRepository) GetUnsectionedMaterials(lessonID uint, releasedAt *time.Time) ([]entities.Material, error) {
+	var materials []entities.Material
+	result := released(common.DB, releasedAt).
+		Where("lesson_id = ? AND section_id IS NULL", lessonID).
+		Order("position, id").
+		Find(&materials)
+	return materials, result.Error
+}
+
+func (r *MaterialRepository) GetMaterial(id uint) (entities.Material, error) {
+	var material entities.Material
+	result := common.DB.First(&material, id)
+	return material, result.Error
+}
+
+// CreateMaterial appends the material to the end of its section
+func (r *MaterialRepository) CreateMaterial(material *entities.Material) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		position, err := nextMaterialPosition(tx, material.LessonID, material.SectionID)
+		if err != nil {
+			return err
+		}
+
+		material.Position = position
+		return tx.Create(material).Error
+	})
+}
+
+// UpdateMaterial saves the material; a material moved to another section goes to its end
+func (r *MaterialRepository) UpdateMaterial(material *entities.Material, moved bool) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if moved {
+			position, err := nextMaterialPosition(tx, material.LessonID, material.SectionID)
+			if err != nil {
+				return err
+			}
+			material.Position = position
+		}
+
+		result := tx.Save(material)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no rows affected")
+		}
+		return nil
+	})
+}
+
+// DeleteMaterial removes the material together with its download history
+func (r *MaterialRepository) DeleteMaterial(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("material_id = ?", id).Delete(&entities.MaterialDownload{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entities.Material{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ReorderMaterials orders the materials of one section, or those outside any section when sectionID is nil
+func (r *MaterialRepository) ReorderMaterials(lessonID uint, sectionID *uint, materialIDs []uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := inSection(tx.Model(&entities.Material{}), lessonID, sectionID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !common.SameIDs(existing, materialIDs) {
+			return ErrInvalidOrder
+		}
+
+		for position, id := range materialIDs {
+			if err := tx.Model(&entities.Material{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *MaterialRepository) RecordDownload(download *entities.MaterialDownload) error {
+	return common.DB.Create(download).Error
+}
+
+// GetDownloadStats lists every enrolled student with how often they opened the material
+func (r *MaterialRepository) GetDownloadStats(lessonID uint, materialID uint) ([]models.MaterialDownloadStat, error) {
+	var stats []models.MaterialDownloadStat
+	result := common.DB.Model(&entities.Student{}).
+		Select("students.id AS student_id, students.name, COUNT(material_downloads.id) AS downloads, "+
+			"MIN(material_downloads.downloaded_at) AS first_downloaded_at, MAX(material_downloads.downloaded_at) AS last_downloaded_at").
+		Joins("JOIN lesson_students ON lesson_students.student_id = students.id AND lesson_students.lesson_id = ?", lessonID).
+		Joins("LEFT JOIN material_downloads ON material_downloads.student_id = students.id AND material_downloads.material_id = ?", materialID).
+		Group("students.id, students.name").
+		Order("students.name, students.id").
+		Scan(&stats)
+	return stats, result.Error
+}
+
+func nextMaterialPosition(tx *gorm.DB, lessonID uint, sectionID *uint) (int, error) {
+	var last int
+	err := inSection(tx.Model(&entities.Material{}), lessonID, sectionID).
+		Select("COALESCE(MAX(position), -1)").
+		Scan(&last).Error
+	return last + 1, err
+}
+
+func inSection(query *gorm.DB, lessonID uint, sectionID *uint) *gorm.DB {
+	if sectionID == nil {
+		return query.Where("lesson_id = ? AND section_id IS NULL", lessonID)
+	}
+	return query.Where("lesson_id = ? AND section_id = ?", lessonID, *sectionID)
+}
+
+func released(query *gorm.DB, releasedAt *time.Time) *gorm.DB {
+	if releasedAt == nil {
+		return query
+	}
+	return query.Where("(release_at IS NULL OR release_at <= ?)", *releasedAt)
+}
@@ -0,0 +1,40 @@
+package materials
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *MaterialHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/materials").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.List).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/order", handler.ReorderMaterials).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/links", handler.CreateLink).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/files", handler.CreateFile).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/sections", handler.CreateSection).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/sections/order", handler.ReorderSections).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/sections/{sectionID:[0-9]+}", handler.UpdateSection).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/sections/{sectionID:[0-9]+}", handler.DeleteSection).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/sections/{sectionID:[0-9]+}/order", handler.ReorderMaterials).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{materialID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{materialID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/{materialID:[0-9]+}/file", handler.Download).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{materialID:[0-9]+}/downloads", handler.GetDownloadStats).Methods(http.MethodGet)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/materials", handler.ListForStudent).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/materials/{materialID:[0-9]+}/open", handler.Open).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/materials/{materialID:[0-9]+}/url", handler.GetDownloadURL).Methods(http.MethodGet)
+}
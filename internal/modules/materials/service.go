@@ -0,0 +1,551 @@
+package materials
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/progress"
+	"lesson-management/models"
+	"lesson-management/pkg/storage"
+	"net/url"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSectionTitleRequired = errors.New("section title is required")
+	ErrSectionNotEmpty      = errors.New("section still holds materials")
+	ErrInvalidOrder         = errors.New("order must list every item exactly once")
+	ErrTitleRequired        = errors.New("material title is required")
+	ErrInvalidURL           = errors.New("link must be an absolute http or https URL")
+	ErrNotLink              = errors.New("only link materials have a URL")
+	ErrNotFile              = errors.New("material is not a file")
+	ErrMaterialFileMissing  = errors.New("material file is no longer available")
+)
+
+type IMaterialService interface {
+	GetLessonMaterials(lessonID uint64, teacherID uint) (*models.LessonMaterialsResponse, error)
+	CreateSection(lessonID uint64, teacherID uint, request *models.MaterialSectionRequest) (*entities.MaterialSection, error)
+	UpdateSection(lessonID uint64, sectionID uint64, teacherID uint, request *models.MaterialSectionRequest) (*entities.MaterialSection, error)
+	DeleteSection(lessonID uint64, sectionID uint64, teacherID uint) error
+	ReorderSections(lessonID uint64, teacherID uint, sectionIDs []uint) (*models.LessonMaterialsResponse, error)
+	CreateLink(lessonID uint64, teacherID uint, request *models.CreateLinkMaterialRequest) (*entities.Material, error)
+	CreateFile(lessonID uint64, teacherID uint, request *models.CreateFileMaterialRequest, fileName string, content io.Reader) (*entities.Material, error)
+	UpdateMaterial(lessonID uint64, materialID uint64, teacherID uint, request *models.PatchMaterialRequest) (*entities.Material, error)
+	DeleteMaterial(lessonID uint64, materialID uint64, teacherID uint) error
+	ReorderMaterials(lessonID uint64, sectionID *uint64, teacherID uint, materialIDs []uint) (*models.LessonMaterialsResponse, error)
+	OpenMaterial(lessonID uint64, materialID uint64, teacherID uint) (*entities.Material, storage.Object, error)
+	GetDownloadStats(lessonID uint64, materialID uint64, teacherID uint) ([]models.MaterialDownloadStat, error)
+	GetStudentMaterials(lessonID uint64, studentID uint) (*models.LessonMaterialsResponse, error)
+	OpenStudentMaterial(materialID uint64, studentID uint) (*entities.Material, storage.Object, error)
+	GetStudentMaterialURL(materialID uint64, studentID uint) (*models.DownloadURLResponse, error)
+}
+
+type MaterialService struct {
+	repo            IMaterialRepository
+	lessonService   lessons.ILessonService
+	progressService progress.IProgressService
+	uploads         *storage.Uploads
+}
+
+func NewMaterialService(repo IMaterialRepository, lessonService lessons.ILessonService, progressService progress.IProgressService, uploads *storage.Uploads) IMaterialService {
+	return &MaterialService{
+		repo:            repo,
+		lessonService:   lessonService,
+		progressService: progressService,
+		uploads:         uploads,
+	}
+}
+
+// GetLessonMaterials returns every section and material of the lesson, released or not
+func (s *MaterialService) GetLessonMaterials(lessonID uint64, teacherID uint) (*models.LessonMaterialsResponse, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.lessonMaterials(lesson.ID, nil)
+}
+
+func (s *MaterialService) CreateSection(lessonID uint64, teacherID uint, request *models.MaterialSectionRequest) (*entities.MaterialSection, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return nil, ErrSectionTitleRequired
+	}
+
+	section := &entities.MaterialSection{
+		LessonID:  lesson.ID,
+		Title:     title,
+		Materials: []entities.Material{},
+	}
+	if err := s.repo.CreateSection(section); err != nil {
+		return nil, err
+	}
+
+	return section, nil
+}
+
+func (s *MaterialService) UpdateSection(lessonID uint64, sectionID uint64, teacherID uint, request *models.MaterialSectionRequest) (*entities.MaterialSection, error) {
+	section, err := s.lessonSection(lessonID, sectionID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		return nil, ErrSectionTitleRequired
+	}
+
+	section.Title = title
+	if err := s.repo.UpdateSection(section); err != nil {
+		return nil, err
+	}
+
+	return section, nil
+}
+
+func (s *MaterialService) DeleteSection(lessonID uint64, sectionID uint64, teacherID uint) error {
+	section, err := s.lessonSection(lessonID, sectionID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSection(section.ID)
+}
+
+func (s *MaterialService) ReorderSections(lessonID uint64, teacherID uint, sectionIDs []uint) (*models.LessonMaterialsResponse, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderSections(lesson.ID, sectionIDs); err != nil {
+		return nil, err
+	}
+
+	return s.lessonMaterials(lesson.ID, nil)
+}
+
+func (s *MaterialService) CreateLink(lessonID uint64, teacherID uint, request *models.CreateLinkMaterialRequest) (*entities.Material, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	material := &entities.Material{
+		LessonID:    lesson.ID,
+		SectionID:   request.SectionID,
+		Kind:        entities.MaterialLink,
+		Title:       strings.TrimSpace(request.Title),
+		Description: request.Description,
+		URL:         strings.TrimSpace(request.URL),
+		ReleaseAt:   request.ReleaseAt,
+		CreatedBy:   teacherID,
+	}
+	if err := s.validateMaterial(material); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateMaterial(material); err != nil {
+		return nil, err
+	}
+
+	return material, nil
+}
+
+// CreateFile stores the uploaded file and attaches it to the lesson
+func (s *MaterialService) CreateFile(lessonID uint64, teacherID uint, request *models.CreateFileMaterialRequest, fileName string, content io.Reader) (*entities.Material, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	material := &entities.Material{
+		LessonID:    lesson.ID,
+		SectionID:   request.SectionID,
+		Kind:        entities.MaterialFile,
+		Title:       strings.TrimSpace(request.Title),
+		Description: request.Description,
+		FileName:    storage.CleanFileName(fileName, "material"),
+		ReleaseAt:   request.ReleaseAt,
+		CreatedBy:   teacherID,
+	}
+	if material.Title == "" {
+		material.Title = material.FileName
+	}
+	if err := s.validateMaterial(material); err != nil {
+		return nil, err
+	}
+
+	key, err := materialKey(lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+	upload, err := storage.Save(s.uploads.Storage, key, content, s.uploads.MaxUploadBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	material.ContentType = upload.ContentType
+	material.Size = upload.Size
+	material.Checksum = upload.Checksum
+	material.StorageKey = upload.Key
+	if err := s.repo.CreateMaterial(material); err != nil {
+		s.discardFile(key)
+		return nil, err
+	}
+
+	return material, nil
+}
+
+func (s *MaterialService) UpdateMaterial(lessonID uint64, materialID uint64, teacherID uint, request *models.PatchMaterialRequest) (*entities.Material, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	material, err := s.lessonMaterial(lesson, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := false
+	if request.SectionID != nil {
+		var sectionID *uint
+		if *request.SectionID != 0 {
+			sectionID = request.SectionID
+		}
+		moved = !sameSection(material.SectionID, sectionID)
+		material.SectionID = sectionID
+	}
+	if request.Title != nil {
+		material.Title = strings.TrimSpace(*request.Title)
+	}
+	if request.Description != nil {
+		material.Description = *request.Description
+	}
+	if request.URL != nil {
+		if material.Kind != entities.MaterialLink {
+			return nil, ErrNotLink
+		}
+		material.URL = strings.TrimSpace(*request.URL)
+	}
+	if request.ReleaseAt != nil {
+		material.ReleaseAt = request.ReleaseAt
+	}
+	if err := s.validateMaterial(material); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateMaterial(material, moved); err != nil {
+		return nil, err
+	}
+
+	return material, nil
+}
+
+// DeleteMaterial removes the material, its download history and its stored file
+func (s *MaterialService) DeleteMaterial(lessonID uint64, materialID uint64, teacherID uint) error {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	material, err := s.lessonMaterial(lesson, materialID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteMaterial(material.ID); err != nil {
+		return err
+	}
+
+	if material.StorageKey != "" {
+		// The row is gone either way, so a file that fails to delete is only logged
+		s.discardFile(material.StorageKey)
+	}
+
+	return nil
+}
+
+// ReorderMaterials orders the materials of a section, or those outside any section when sectionID is nil
+func (s *MaterialService) ReorderMaterials(lessonID uint64, sectionID *uint64, teacherID uint, materialIDs []uint) (*models.LessonMaterialsResponse, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	var section *uint
+	if sectionID != nil {
+		found, err := s.repo.GetSection(uint(*sectionID))
+		if err != nil {
+			return nil, err
+		}
+		if found.LessonID != lesson.ID {
+			return nil, gorm.ErrRecordNotFound
+		}
+		section = &found.ID
+	}
+
+	if err := s.repo.ReorderMaterials(lesson.ID, section, materialIDs); err != nil {
+		return nil, err
+	}
+
+	return s.lessonMaterials(lesson.ID, nil)
+}
+
+// OpenMaterial opens a file material for one of the lesson's teachers; teacher downloads are not tracked
+func (s *MaterialService) OpenMaterial(lessonID uint64, materialID uint64, teacherID uint) (*entities.Material, storage.Object, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	material, err := s.lessonMaterial(lesson, materialID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if material.Kind != entities.MaterialFile {
+		return nil, nil, ErrNotFile
+	}
+
+	return s.openFile(material)
+}
+
+func (s *MaterialService) GetDownloadStats(lessonID uint64, materialID uint64, teacherID uint) ([]models.MaterialDownloadStat, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	material, err := s.lessonMaterial(lesson, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetDownloadStats(lesson.ID, material.ID)
+}
+
+// GetStudentMaterials returns the released materials of a lesson the student is enrolled in
+func (s *MaterialService) GetStudentMaterials(lessonID uint64, studentID uint) (*models.LessonMaterialsResponse, error) {
+	lesson, err := s.lessonService.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(lesson.ID), studentID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return s.lessonMaterials(lesson.ID, &now)
+}
+
+// OpenStudentMaterial records the download and opens the file. Links come back without a
+// file, for the caller to redirect to
+func (s *MaterialService) OpenStudentMaterial(materialID uint64, studentID uint) (*entities.Material, storage.Object, error) {
+	material, err := s.studentMaterial(materialID, studentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if material.Kind == entities.MaterialLink {
+		if err := s.recordDownload(material, studentID); err != nil {
+			return nil, nil, err
+		}
+		return material, nil, nil
+	}
+
+	material, object, err := s.openFile(material)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.recordDownload(material, studentID); err != nil {
+		object.Close()
+		return nil, nil, err
+	}
+
+	return material, object, nil
+}
+
+// GetStudentMaterialURL records the download and returns a short-lived link to the file
+func (s *MaterialService) GetStudentMaterialURL(materialID uint64, studentID uint) (*models.DownloadURLResponse, error) {
+	material, err := s.studentMaterial(materialID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if material.Kind != entities.MaterialFile {
+		return nil, ErrNotFile
+	}
+
+	if err := s.recordDownload(material, studentID); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(storage.DownloadURLTTL).Truncate(time.Second)
+	return &models.DownloadURLResponse{
+		URL: s.uploads.Signer.Sign(storage.SignedFile{
+			Key:         material.StorageKey,
+			FileName:    material.FileName,
+			ContentType: material.ContentType,
+			ExpiresAt:   expiresAt,
+		}),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *MaterialService) lessonMaterials(lessonID uint, releasedAt *time.Time) (*models.LessonMaterialsResponse, error) {
+	sections, err := s.repo.GetSections(lessonID, releasedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	materials, err := s.repo.GetUnsectionedMaterials(lessonID, releasedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LessonMaterialsResponse{
+		LessonID:  lessonID,
+		Sections:  sections,
+		Materials: materials,
+	}, nil
+}
+
+// recordDownload logs the download and counts the material as viewed towards the student's progress
+func (s *MaterialService) recordDownload(material *entities.Material, studentID uint) error {
+	err := s.repo.RecordDownload(&entities.MaterialDownload{
+		LessonID:     material.LessonID,
+		MaterialID:   material.ID,
+		StudentID:    studentID,
+		DownloadedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := s.progressService.Record(material.LessonID, studentID, entities.ProgressMaterialViewed, material.ID); err != nil {
+		fmt.Println("Error while recording progress: ", err)
+	}
+	return nil
+}
+
+func (s *MaterialService) openFile(material *entities.Material) (*entities.Material, storage.Object, error) {
+	object, err := s.uploads.Storage.Open(material.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrMaterialFileMissing
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return material, object, nil
+}
+
+func (s *MaterialService) discardFile(key string) {
+	if err := s.uploads.Storage.Delete(key); err != nil {
+		fmt.Println("Error while deleting material file: ", err)
+	}
+}
+
+// validateMaterial checks the fields and that the section belongs to the same lesson
+func (s *MaterialService) validateMaterial(material *entities.Material) error {
+	if material.Title == "" {
+		return ErrTitleRequired
+	}
+	if material.Kind == entities.MaterialLink && !validLink(material.URL) {
+		return ErrInvalidURL
+	}
+
+	if material.SectionID != nil {
+		section, err := s.repo.GetSection(*material.SectionID)
+		if err != nil {
+			return err
+		}
+		if section.LessonID != material.LessonID {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	return nil
+}
+
+// studentMaterial loads a released material from a lesson the student is enrolled in.
+// Unreleased materials are reported as not found
+func (s *MaterialService) studentMaterial(materialID uint64, studentID uint) (*entities.Material, error) {
+	material, err := s.repo.GetMaterial(uint(materialID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(material.LessonID), studentID); err != nil {
+		return nil, err
+	}
+
+	if !material.IsReleased(time.Now()) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &material, nil
+}
+
+// lessonSection loads a section of an editable lesson, treating one from another lesson as not found
+func (s *MaterialService) lessonSection(lessonID uint64, sectionID uint64, teacherID uint) (*entities.MaterialSection, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	section, err := s.repo.GetSection(uint(sectionID))
+	if err != nil {
+		return nil, err
+	}
+	if section.LessonID != lesson.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &section, nil
+}
+
+// lessonMaterial loads a material, treating one from another lesson as not found
+func (s *MaterialService) lessonMaterial(lesson *entities.Lesson, materialID uint64) (*entities.Material, error) {
+	material, err := s.repo.GetMaterial(uint(materialID))
+	if err != nil {
+		return nil, err
+	}
+
+	if material.LessonID != lesson.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &material, nil
+}
+
+func validLink(link string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func sameSection(a *uint, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// materialKey names a new material file under its lesson
+func materialKey(lessonID uint) (string, error) {
+	return storage.RandomKey(fmt.Sprintf("materials/%d", lessonID))
+}
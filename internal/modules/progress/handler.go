@@ -0,0 +1,182 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/students"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"lesson-management/pkg/pagination"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type ProgressHandler struct {
+	service IProgressService
+}
+
+func NewProgressHandler(service IProgressService) *ProgressHandler {
+	return &ProgressHandler{
+		service: service,
+	}
+}
+
+// Teacher handlers
+func (h *ProgressHandler) GetLessonProgress(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	page, err := pagination.FromRequest(r, students.StudentSorts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	progress, pageInfo, err := h.service.GetLessonProgress(lessonID, teacherID, page)
+	if err != nil {
+		if status := progressErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch lesson progress", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching lesson progress: ", err)
+		return
+	}
+
+	pagination.WriteHeaders(w, r, page, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(progress)
+}
+
+func (h *ProgressHandler) GetStudentProgress(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := pathID(w, r, "studentID", "Invalid student ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	progress, err := h.service.GetStudentProgress(lessonID, uint(studentID), teacherID)
+	if err != nil {
+		if status := progressErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch student progress", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student progress: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(progress)
+}
+
+func (h *ProgressHandler) SetCompletionPercent(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.SetCompletionPercentRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetCompletionPercent(lessonID, teacherID, requestBody.CompletionPercent); err != nil {
+		if status := progressErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to set completion percent", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while setting completion percent: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Student handlers
+func (h *ProgressHandler) GetOwnProgress(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	progress, err := h.service.GetOwnProgress(lessonID, studentID)
+	if err != nil {
+		if status := progressErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch progress", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching progress: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(progress)
+}
+
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		http.Error(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// progressErrorStatus maps service errors to HTTP status codes
+func progressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher),
+		errors.Is(err, lessons.ErrNotLessonStudent):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInvalidCompletionPercent):
+		return http.StatusBadRequest
+	case errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,101 @@
+package progress
+
+import (
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IProgressRepository interface {
+	RecordProgress(record *entities.ProgressRecord) error
+	GetRecords(lessonID uint, studentIDs []uint) ([]entities.ProgressRecord, error)
+	GetReleasedMaterials(lessonID uint, at time.Time) ([]entities.Material, error)
+	GetPublishedQuizzes(lessonID uint) ([]entities.Quiz, error)
+	GetAssignments(lessonID uint) ([]entities.Assignment, error)
+	GetEnrollments(lessonID uint, studentIDs []uint) ([]entities.Enrollment, error)
+	GetStudent(id uint) (entities.Student, error)
+	SetCompletionPercent(lessonID uint, percent int) error
+}
+
+type ProgressRepository struct{}
+
+func NewProgressRepository() IProgressRepository {
+	return &ProgressRepository{}
+}
+
+// RecordProgress inserts the record unless the student already has one for the same item
+func (r *ProgressRepository) RecordProgress(record *entities.ProgressRecord) error {
+	return common.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(record).Error
+}
+
+// GetRecords returns the lesson's progress records, only for the given students when studentIDs is not nil
+func (r *ProgressRepository) GetRecords(lessonID uint, studentIDs []uint) ([]entities.ProgressRecord, error) {
+	var records []entities.ProgressRecord
+	query := common.DB.Where("lesson_id = ?", lessonID)
+	if studentIDs != nil {
+		if len(studentIDs) == 0 {
+			return records, nil
+		}
+		query = query.Where("student_id IN ?", studentIDs)
+	}
+	result := query.Order("student_id, recorded_at, id").Find(&records)
+	return records, result.Error
+}
+
+// GetReleasedMaterials returns the materials students can see at the given time
+func (r *ProgressRepository) GetReleasedMaterials(lessonID uint, at time.Time) ([]entities.Material, error) {
+	var materials []entities.Material
+	result := common.DB.
+		Where("lesson_id = ? AND (release_at IS NULL OR release_at <= ?)", lessonID, at).
+		Order("position, id").
+		Find(&materials)
+	return materials, result.Error
+}
+
+func (r *ProgressRepository) GetPublishedQuizzes(lessonID uint) ([]entities.Quiz, error) {
+	var quizzes []entities.Quiz
+	result := common.DB.Where("lesson_id = ? AND published = ?", lessonID, true).Order("id").Find(&quizzes)
+	return quizzes, result.Error
+}
+
+func (r *ProgressRepository) GetAssignments(lessonID uint) ([]entities.Assignment, error) {
+	var assignments []entities.Assignment
+	result := common.DB.Where("lesson_id = ?", lessonID).Order("due_at, id").Find(&assignments)
+	return assignments, result.Error
+}
+
+// GetEnrollments returns the lesson's enrollments, only for the given students when studentIDs is not nil
+func (r *ProgressRepository) GetEnrollments(lessonID uint, studentIDs []uint) ([]entities.Enrollment, error) {
+	var enrollments []entities.Enrollment
+	query := common.DB.Where("lesson_id = ?", lessonID)
+	if studentIDs != nil {
+		if len(studentIDs) == 0 {
+			return enrollments, nil
+		}
+		query = query.Where("student_id IN ?", studentIDs)
+	}
+	result := query.Find(&enrollments)
+	return enrollments, result.Error
+}
+
+func (r *ProgressRepository) GetStudent(id uint) (entities.Student, error) {
+	var student entities.Student
+	result := common.DB.First(&student, id)
+	return student, result.Error
+}
+
+func (r *ProgressRepository) SetCompletionPercent(lessonID uint, percent int) error {
+	result := common.DB.Model(&entities.Lesson{}).Where("id = ?", lessonID).Update("completion_percent", percent)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
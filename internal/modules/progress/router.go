@@ -0,0 +1,28 @@
+package progress
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *ProgressHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/progress").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.GetLessonProgress).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/completion", handler.SetCompletionPercent).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/students/{studentID:[0-9]+}", handler.GetStudentProgress).Methods(http.MethodGet)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/progress", handler.GetOwnProgress).Methods(http.MethodGet)
+}
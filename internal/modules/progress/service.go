@@ -0,0 +1,279 @@
+package progress
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/pagination"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCompletionPercent = errors.New("completion percent must be between 0 and 100")
+)
+
+type IProgressService interface {
+	GetLessonProgress(lessonID uint64, teacherID uint, page pagination.Params) ([]models.StudentProgress, pagination.Page, error)
+	GetStudentProgress(lessonID uint64, studentID uint, teacherID uint) (*models.StudentProgress, error)
+	GetOwnProgress(lessonID uint64, studentID uint) (*models.StudentProgress, error)
+	SetCompletionPercent(lessonID uint64, teacherID uint, percent int) error
+	Record(lessonID uint, studentID uint, kind string, itemID uint) error
+}
+
+type ProgressService struct {
+	repo          IProgressRepository
+	lessonService lessons.ILessonService
+}
+
+func NewProgressService(repo IProgressRepository, lessonService lessons.ILessonService) IProgressService {
+	return &ProgressService{
+		repo:          repo,
+		lessonService: lessonService,
+	}
+}
+
+// GetLessonProgress returns a page of the lesson's students, as listed by GetLessonStudents, with their progress
+func (s *ProgressService) GetLessonProgress(lessonID uint64, teacherID uint, page pagination.Params) ([]models.StudentProgress, pagination.Page, error) {
+	students, pageInfo, err := s.lessonService.GetLessonStudents(lessonID, teacherID, page)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	lesson, err := s.lessonService.GetLesson(lessonID)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	content, err := s.lessonContent(lesson.ID)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	studentIDs := make([]uint, 0, len(students))
+	for _, student := range students {
+		studentIDs = append(studentIDs, student.ID)
+	}
+
+	records, err := s.repo.GetRecords(lesson.ID, studentIDs)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+	recordsByStudent := make(map[uint][]entities.ProgressRecord, len(students))
+	for _, record := range records {
+		recordsByStudent[record.StudentID] = append(recordsByStudent[record.StudentID], record)
+	}
+
+	enrollments, err := s.repo.GetEnrollments(lesson.ID, studentIDs)
+	if err != nil {
+		return nil, pagination.Page{}, err
+	}
+	enrollmentsByStudent := make(map[uint]entities.Enrollment, len(enrollments))
+	for _, enrollment := range enrollments {
+		enrollmentsByStudent[enrollment.StudentID] = enrollment
+	}
+
+	progress := make([]models.StudentProgress, 0, len(students))
+	for _, student := range students {
+		progress = append(progress, summarize(student, enrollmentsByStudent[student.ID], content, recordsByStudent[student.ID], false))
+	}
+
+	return progress, pageInfo, nil
+}
+
+// GetStudentProgress returns one student's progress with every item of the lesson
+func (s *ProgressService) GetStudentProgress(lessonID uint64, studentID uint, teacherID uint) (*models.StudentProgress, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.enrollment(lesson.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return s.studentProgress(lesson, enrollment)
+}
+
+func (s *ProgressService) GetOwnProgress(lessonID uint64, studentID uint) (*models.StudentProgress, error) {
+	lesson, err := s.lessonService.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.enrollment(lesson.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if enrollment == nil {
+		return nil, lessons.ErrNotLessonStudent
+	}
+
+	return s.studentProgress(lesson, enrollment)
+}
+
+// SetCompletionPercent sets the lesson's completion threshold and completes the enrollment of
+// every active student who already meets it
+func (s *ProgressService) SetCompletionPercent(lessonID uint64, teacherID uint, percent int) error {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	if percent < 0 || percent > 100 {
+		return ErrInvalidCompletionPercent
+	}
+
+	if err := s.repo.SetCompletionPercent(lesson.ID, percent); err != nil {
+		return err
+	}
+	lesson.CompletionPercent = percent
+	if percent == 0 {
+		return nil
+	}
+
+	content, err := s.lessonContent(lesson.ID)
+	if err != nil {
+		return err
+	}
+
+	enrollments, err := s.repo.GetEnrollments(lesson.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	records, err := s.repo.GetRecords(lesson.ID, nil)
+	if err != nil {
+		return err
+	}
+	recordsByStudent := make(map[uint][]entities.ProgressRecord, len(enrollments))
+	for _, record := range records {
+		recordsByStudent[record.StudentID] = append(recordsByStudent[record.StudentID], record)
+	}
+
+	for _, enrollment := range enrollments {
+		student := entities.Student{ID: enrollment.StudentID}
+		summary := summarize(student, enrollment, content, recordsByStudent[enrollment.StudentID], false)
+		if !reachesCompletion(lesson, summary) {
+			continue
+		}
+		if err := s.lessonService.AutoCompleteEnrollment(uint64(lesson.ID), enrollment.StudentID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Record notes that the student viewed a material, passed a quiz or submitted an assignment,
+// then completes their enrollment if that brought them up to the lesson's threshold. Recording
+// the same item again changes nothing
+func (s *ProgressService) Record(lessonID uint, studentID uint, kind string, itemID uint) error {
+	err := s.repo.RecordProgress(&entities.ProgressRecord{
+		LessonID:   lessonID,
+		StudentID:  studentID,
+		Kind:       kind,
+		ItemID:     itemID,
+		RecordedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	lesson, err := s.lessonService.GetLesson(uint64(lessonID))
+	if err != nil {
+		return err
+	}
+	if lesson.CompletionPercent == 0 {
+		return nil
+	}
+
+	enrollment, err := s.enrollment(lesson.ID, studentID)
+	if err != nil {
+		return err
+	}
+	if enrollment == nil || enrollment.Status != entities.EnrollmentActive {
+		return nil
+	}
+
+	content, err := s.lessonContent(lesson.ID)
+	if err != nil {
+		return err
+	}
+
+	records, err := s.repo.GetRecords(lesson.ID, []uint{studentID})
+	if err != nil {
+		return err
+	}
+
+	summary := summarize(entities.Student{ID: studentID}, *enrollment, content, records, false)
+	if !reachesCompletion(lesson, summary) {
+		return nil
+	}
+
+	return s.lessonService.AutoCompleteEnrollment(uint64(lesson.ID), studentID)
+}
+
+func (s *ProgressService) studentProgress(lesson *entities.Lesson, enrollment *entities.Enrollment) (*models.StudentProgress, error) {
+	student, err := s.repo.GetStudent(enrollment.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := s.lessonContent(lesson.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.GetRecords(lesson.ID, []uint{student.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarize(student, *enrollment, content, records, true)
+	if summary.Items == nil {
+		summary.Items = []models.ProgressItem{}
+	}
+
+	return &summary, nil
+}
+
+// lessonContent loads the released materials, published quizzes and assignments that progress is measured against
+func (s *ProgressService) lessonContent(lessonID uint) (*lessonContent, error) {
+	materials, err := s.repo.GetReleasedMaterials(lessonID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	quizzes, err := s.repo.GetPublishedQuizzes(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.repo.GetAssignments(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lessonContent{
+		materials:   materials,
+		quizzes:     quizzes,
+		assignments: assignments,
+	}, nil
+}
+
+// enrollment returns the student's enrollment in the lesson, or nil when they are not enrolled
+func (s *ProgressService) enrollment(lessonID uint, studentID uint) (*entities.Enrollment, error) {
+	enrollments, err := s.repo.GetEnrollments(lessonID, []uint{studentID})
+	if err != nil || len(enrollments) == 0 {
+		return nil, err
+	}
+
+	return &enrollments[0], nil
+}
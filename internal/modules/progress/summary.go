@@ -0,0 +1,83 @@
+package progress
+
+import (
+	"lesson-management/entities"
+	"lesson-management/models"
+	"math"
+	"time"
+)
+
+// lessonContent is what students' progress through a lesson is measured against
+type lessonContent struct {
+	materials   []entities.Material
+	quizzes     []entities.Quiz
+	assignments []entities.Assignment
+}
+
+type itemKey struct {
+	kind   string
+	itemID uint
+}
+
+// summarize counts the student's records against the lesson's current content. Records for
+// items that have since been removed or unpublished are ignored, so the percentage always
+// reflects what students can work through now
+func summarize(student entities.Student, enrollment entities.Enrollment, content *lessonContent, records []entities.ProgressRecord, withItems bool) models.StudentProgress {
+	recorded := make(map[itemKey]time.Time, len(records))
+	for _, record := range records {
+		recorded[itemKey{record.Kind, record.ItemID}] = record.RecordedAt
+	}
+
+	summary := models.StudentProgress{
+		StudentID:        student.ID,
+		Name:             student.Name,
+		EnrollmentStatus: enrollment.Status,
+		CompletedAt:      enrollment.CompletedAt,
+		MaterialsTotal:   len(content.materials),
+		QuizzesTotal:     len(content.quizzes),
+		AssignmentsTotal: len(content.assignments),
+	}
+
+	track := func(kind string, itemID uint, title string) bool {
+		at, done := recorded[itemKey{kind, itemID}]
+		if withItems {
+			item := models.ProgressItem{Kind: kind, ItemID: itemID, Title: title, Completed: done}
+			if done {
+				item.CompletedAt = &at
+			}
+			summary.Items = append(summary.Items, item)
+		}
+		return done
+	}
+
+	for _, material := range content.materials {
+		if track(entities.ProgressMaterialViewed, material.ID, material.Title) {
+			summary.MaterialsViewed++
+		}
+	}
+	for _, quiz := range content.quizzes {
+		if track(entities.ProgressQuizPassed, quiz.ID, quiz.Title) {
+			summary.QuizzesPassed++
+		}
+	}
+	for _, assignment := range content.assignments {
+		if track(entities.ProgressAssignmentSubmitted, assignment.ID, assignment.Title) {
+			summary.AssignmentsSubmitted++
+		}
+	}
+
+	total := summary.MaterialsTotal + summary.QuizzesTotal + summary.AssignmentsTotal
+	done := summary.MaterialsViewed + summary.QuizzesPassed + summary.AssignmentsSubmitted
+	if total > 0 {
+		summary.Percent = math.Round(float64(done)/float64(total)*10000) / 100
+	}
+
+	return summary
+}
+
+// reachesCompletion reports whether an active enrollment should now be marked completed
+func reachesCompletion(lesson *entities.Lesson, summary models.StudentProgress) bool {
+	return lesson.CompletionPercent > 0 &&
+		summary.EnrollmentStatus == entities.EnrollmentActive &&
+		summary.Percent >= float64(lesson.CompletionPercent)
+}
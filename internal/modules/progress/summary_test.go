@@ -0,0 +1,91 @@
+package progress
+
+import (
+	"lesson-management/entities"
+	"lesson-management/models"
+	"testing"
+	"time"
+)
+
+func testContent() *lessonContent {
+	return &lessonContent{
+		materials:   []entities.Material{{ID: 1, Title: "Slides"}, {ID: 2, Title: "Reading"}},
+		quizzes:     []entities.Quiz{{ID: 3, Title: "Quiz"}},
+		assignments: []entities.Assignment{{ID: 4, Title: "Essay"}, {ID: 5, Title: "Lab"}, {ID: 6, Title: "Project"}},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	viewedAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	records := []entities.ProgressRecord{
+		{Kind: entities.ProgressMaterialViewed, ItemID: 1, RecordedAt: viewedAt},
+		{Kind: entities.ProgressQuizPassed, ItemID: 3, RecordedAt: viewedAt},
+		{Kind: entities.ProgressAssignmentSubmitted, ItemID: 4, RecordedAt: viewedAt},
+		// Removed material and a record whose ID belongs to another kind of item
+		{Kind: entities.ProgressMaterialViewed, ItemID: 99, RecordedAt: viewedAt},
+		{Kind: entities.ProgressMaterialViewed, ItemID: 5, RecordedAt: viewedAt},
+	}
+	enrollment := entities.Enrollment{Status: entities.EnrollmentActive}
+
+	summary := summarize(entities.Student{ID: 7, Name: "Ada"}, enrollment, testContent(), records, true)
+
+	if summary.MaterialsViewed != 1 || summary.MaterialsTotal != 2 ||
+		summary.QuizzesPassed != 1 || summary.QuizzesTotal != 1 ||
+		summary.AssignmentsSubmitted != 1 || summary.AssignmentsTotal != 3 {
+		t.Errorf("counts = %+v, want 1/2 materials, 1/1 quizzes, 1/3 assignments", summary)
+	}
+	if summary.Percent != 50 {
+		t.Errorf("percent = %v, want 50", summary.Percent)
+	}
+	if len(summary.Items) != 6 {
+		t.Fatalf("got %d items, want 6", len(summary.Items))
+	}
+	first := summary.Items[0]
+	if !first.Completed || first.CompletedAt == nil || !first.CompletedAt.Equal(viewedAt) || first.Title != "Slides" {
+		t.Errorf("first item = %+v, want Slides completed at %v", first, viewedAt)
+	}
+	if second := summary.Items[1]; second.Completed || second.CompletedAt != nil {
+		t.Errorf("second item = %+v, want it incomplete", second)
+	}
+}
+
+func TestSummarizeWithoutItems(t *testing.T) {
+	records := []entities.ProgressRecord{{Kind: entities.ProgressMaterialViewed, ItemID: 1}}
+
+	summary := summarize(entities.Student{}, entities.Enrollment{}, testContent(), records, false)
+	if summary.Items != nil {
+		t.Errorf("items = %+v, want none", summary.Items)
+	}
+	if summary.Percent != 16.67 {
+		t.Errorf("percent = %v, want 16.67", summary.Percent)
+	}
+
+	empty := summarize(entities.Student{}, entities.Enrollment{}, &lessonContent{}, records, false)
+	if empty.Percent != 0 {
+		t.Errorf("percent of a lesson without content = %v, want 0", empty.Percent)
+	}
+}
+
+func TestReachesCompletion(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		threshold int
+		status    string
+		percent   float64
+		want      bool
+	}{
+		{"reached", 80, entities.EnrollmentActive, 80, true},
+		{"exceeded", 80, entities.EnrollmentActive, 100, true},
+		{"below", 80, entities.EnrollmentActive, 79.99, false},
+		{"no threshold", 0, entities.EnrollmentActive, 100, false},
+		{"already completed", 80, entities.EnrollmentCompleted, 100, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			lesson := &entities.Lesson{CompletionPercent: test.threshold}
+			summary := models.StudentProgress{EnrollmentStatus: test.status, Percent: test.percent}
+			if got := reachesCompletion(lesson, summary); got != test.want {
+				t.Errorf("reachesCompletion() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
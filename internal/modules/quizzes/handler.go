@@ -0,0 +1,688 @@
+package quizzes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type QuizHandler struct {
+	service IQuizService
+}
+
+func NewQuizHandler(service IQuizService) *QuizHandler {
+	return &QuizHandler{
+		service: service,
+	}
+}
+
+// Teacher handlers
+func (h *QuizHandler) List(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	quizzes, err := h.service.GetQuizzes(lessonID, teacherID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch quizzes", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching quizzes: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quizzes)
+}
+
+func (h *QuizHandler) Get(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	quiz, err := h.service.GetQuiz(lessonID, quizID, teacherID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch quiz", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching quiz: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quiz)
+}
+
+func (h *QuizHandler) Create(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.CreateQuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	quiz, err := h.service.CreateQuiz(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create quiz", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating quiz: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(quiz)
+}
+
+func (h *QuizHandler) Update(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.PatchQuizRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	quiz, err := h.service.UpdateQuiz(lessonID, quizID, teacherID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update quiz", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating quiz: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quiz)
+}
+
+func (h *QuizHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteQuiz(lessonID, quizID, teacherID); err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete quiz", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting quiz: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *QuizHandler) AddQuestion(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.QuizQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	question, err := h.service.AddQuestion(lessonID, quizID, teacherID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to add quiz question", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while adding quiz question: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(question)
+}
+
+func (h *QuizHandler) UpdateQuestion(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	questionID, ok := pathID(w, r, "questionID", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.QuizQuestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	question, err := h.service.UpdateQuestion(lessonID, quizID, questionID, teacherID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update quiz question", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating quiz question: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(question)
+}
+
+func (h *QuizHandler) DeleteQuestion(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	questionID, ok := pathID(w, r, "questionID", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteQuestion(lessonID, quizID, questionID, teacherID); err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete quiz question", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting quiz question: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *QuizHandler) ReorderQuestions(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.ReorderRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	quiz, err := h.service.ReorderQuestions(lessonID, quizID, teacherID, requestBody.IDs)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to reorder quiz questions", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while reordering quiz questions: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quiz)
+}
+
+// ListAttempts returns each enrolled student's attempts and the score that counts
+func (h *QuizHandler) ListAttempts(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	summaries, err := h.service.GetAttemptSummaries(lessonID, quizID, teacherID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch quiz attempts", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching quiz attempts: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(summaries)
+}
+
+func (h *QuizHandler) GetAttempt(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	attemptID, ok := pathID(w, r, "attemptID", "Invalid attempt ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	review, err := h.service.GetAttemptReview(lessonID, quizID, attemptID, teacherID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch quiz attempt", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching quiz attempt: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(review)
+}
+
+func (h *QuizHandler) ReviewResponse(w http.ResponseWriter, r *http.Request) {
+	lessonID, quizID, ok := quizIDs(w, r)
+	if !ok {
+		return
+	}
+
+	attemptID, ok := pathID(w, r, "attemptID", "Invalid attempt ID")
+	if !ok {
+		return
+	}
+
+	questionID, ok := pathID(w, r, "questionID", "Invalid question ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.ReviewQuizResponseRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.service.ReviewResponse(lessonID, quizID, attemptID, questionID, teacherID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to review quiz response", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while reviewing quiz response: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(review)
+}
+
+// Student handlers
+func (h *QuizHandler) ListForStudent(w http.ResponseWriter, r *http.Request) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	quizzes, err := h.service.GetStudentQuizzes(lessonID, studentID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch student quizzes", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student quizzes: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quizzes)
+}
+
+func (h *QuizHandler) GetForStudent(w http.ResponseWriter, r *http.Request) {
+	quizID, ok := pathID(w, r, "quizID", "Invalid quiz ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	quiz, err := h.service.GetStudentQuiz(quizID, studentID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch student quiz", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching student quiz: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(quiz)
+}
+
+// StartAttempt answers 201 with a new attempt, or 200 with the attempt already in progress
+func (h *QuizHandler) StartAttempt(w http.ResponseWriter, r *http.Request) {
+	quizID, ok := pathID(w, r, "quizID", "Invalid quiz ID")
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attempt, started, err := h.service.StartAttempt(quizID, studentID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to start quiz attempt", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while starting quiz attempt: ", err)
+		return
+	}
+
+	status := http.StatusOK
+	if started {
+		status = http.StatusCreated
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(attempt)
+}
+
+func (h *QuizHandler) GetOwnAttempt(w http.ResponseWriter, r *http.Request) {
+	quizID, attemptID, ok := attemptIDs(w, r)
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attempt, err := h.service.GetOwnAttempt(quizID, attemptID, studentID)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch quiz attempt", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching quiz attempt: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attempt)
+}
+
+func (h *QuizHandler) SaveAnswers(w http.ResponseWriter, r *http.Request) {
+	quizID, attemptID, ok := attemptIDs(w, r)
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.SaveQuizAnswersRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	attempt, err := h.service.SaveAnswers(quizID, attemptID, studentID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to save quiz answers", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while saving quiz answers: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attempt)
+}
+
+// SubmitAttempt scores and closes the attempt. The body is optional and may carry final answers
+func (h *QuizHandler) SubmitAttempt(w http.ResponseWriter, r *http.Request) {
+	quizID, attemptID, ok := attemptIDs(w, r)
+	if !ok {
+		return
+	}
+
+	studentID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.SaveQuizAnswersRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	attempt, err := h.service.SubmitAttempt(quizID, attemptID, studentID, &requestBody)
+	if err != nil {
+		if status := quizErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to submit quiz attempt", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while submitting quiz attempt: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attempt)
+}
+
+// quizIDs parses the lesson and quiz IDs from the URL
+func quizIDs(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	lessonID, ok := pathID(w, r, "lessonID", "Invalid lesson ID")
+	if !ok {
+		return 0, 0, false
+	}
+
+	quizID, ok := pathID(w, r, "quizID", "Invalid quiz ID")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return lessonID, quizID, true
+}
+
+// attemptIDs parses the quiz and attempt IDs from the URL
+func attemptIDs(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	quizID, ok := pathID(w, r, "quizID", "Invalid quiz ID")
+	if !ok {
+		return 0, 0, false
+	}
+
+	attemptID, ok := pathID(w, r, "attemptID", "Invalid attempt ID")
+	if !ok {
+		return 0, 0, false
+	}
+
+	return quizID, attemptID, true
+}
+
+// pathID parses a numeric route variable, answering 400 with message when it is invalid
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		http.Error(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// quizErrorStatus maps service errors to HTTP status codes
+func quizErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher),
+		errors.Is(err, lessons.ErrNotLessonStudent):
+		return http.StatusForbidden
+	case errors.Is(err, ErrTitleRequired),
+		errors.Is(err, ErrInvalidTimeLimit),
+		errors.Is(err, ErrInvalidMaxAttempts),
+		errors.Is(err, ErrInvalidScorePolicy),
+		errors.Is(err, ErrInvalidPassPercent),
+		errors.Is(err, ErrInvalidWindow),
+		errors.Is(err, ErrInvalidKind),
+		errors.Is(err, ErrPromptRequired),
+		errors.Is(err, ErrInvalidPoints),
+		errors.Is(err, ErrInvalidOptions),
+		errors.Is(err, ErrInvalidCorrectOptions),
+		errors.Is(err, ErrAnswerRequired),
+		errors.Is(err, ErrAcceptedAnswerRequired),
+		errors.Is(err, ErrInvalidOrder),
+		errors.Is(err, ErrInvalidAnswer),
+		errors.Is(err, ErrInvalidReviewPoints):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrNoQuestions),
+		errors.Is(err, ErrQuizHasAttempts),
+		errors.Is(err, ErrQuizClosed),
+		errors.Is(err, ErrNoAttemptsLeft),
+		errors.Is(err, ErrAttemptInProgress),
+		errors.Is(err, ErrAttemptSubmitted),
+		errors.Is(err, ErrAttemptExpired),
+		errors.Is(err, ErrAttemptNotSubmitted),
+		errors.Is(err, ErrGradeItemLinked),
+		errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,344 @@
+package quizzes
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IQuizRepository interface {
+	GetQuizzes(lessonID uint, publishedOnly bool) ([]entities.Quiz, error)
+	GetQuiz(id uint) (entities.Quiz, error)
+	CreateQuiz(quiz *entities.Quiz) error
+	UpdateQuiz(quiz *entities.Quiz) error
+	DeleteQuiz(id uint) error
+	GetQuestion(id uint) (entities.QuizQuestion, error)
+	CreateQuestion(question *entities.QuizQuestion) error
+	UpdateQuestion(question *entities.QuizQuestion) error
+	DeleteQuestion(id uint) error
+	ReorderQuestions(quizID uint, questionIDs []uint) error
+	HasAttempts(quizID uint) (bool, error)
+	GradeItemLinked(itemID uint, quizID uint) (bool, error)
+	GetAttempts(quizID uint, studentID *uint) ([]entities.QuizAttempt, error)
+	GetAttempt(id uint) (entities.QuizAttempt, error)
+	CreateAttempt(attempt *entities.QuizAttempt, maxAttempts int) error
+	SaveResponses(responses []entities.QuizResponse) error
+	FinishAttempt(attempt *entities.QuizAttempt) error
+	ReviewResponse(response *entities.QuizResponse) error
+	GetStudent(id uint) (entities.Student, error)
+	SaveGrade(grade *entities.Grade) error
+}
+
+type QuizRepository struct{}
+
+func NewQuizRepository() IQuizRepository {
+	return &QuizRepository{}
+}
+
+func (r *QuizRepository) GetQuizzes(lessonID uint, publishedOnly bool) ([]entities.Quiz, error) {
+	query := common.DB.Where("lesson_id = ?", lessonID)
+	if publishedOnly {
+		query = query.Where("published = ?", true)
+	}
+
+	var quizzes []entities.Quiz
+	result := query.Order("opens_at NULLS FIRST, id").Find(&quizzes)
+	return quizzes, result.Error
+}
+
+// GetQuiz loads the quiz with its questions and their options in order
+func (r *QuizRepository) GetQuiz(id uint) (entities.Quiz, error) {
+	var quiz entities.Quiz
+	result := common.DB.
+		Preload("Questions", orderByPosition).
+		Preload("Questions.Options", orderByPosition).
+		First(&quiz, id)
+	return quiz, result.Error
+}
+
+func (r *QuizRepository) CreateQuiz(quiz *entities.Quiz) error {
+	return common.DB.Omit(clause.Associations).Create(quiz).Error
+}
+
+func (r *QuizRepository) UpdateQuiz(quiz *entities.Quiz) error {
+	result := common.DB.Omit(clause.Associations).Save(quiz)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteQuiz removes the quiz with its questions and every attempt at it. Grades already
+// written into the gradebook are kept
+func (r *QuizRepository) DeleteQuiz(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		attempts := tx.Model(&entities.QuizAttempt{}).Select("id").Where("quiz_id = ?", id)
+		if err := tx.Where("attempt_id IN (?)", attempts).Delete(&entities.QuizResponse{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("quiz_id = ?", id).Delete(&entities.QuizAttempt{}).Error; err != nil {
+			return err
+		}
+
+		questions := tx.Model(&entities.QuizQuestion{}).Select("id").Where("quiz_id = ?", id)
+		if err := tx.Where("question_id IN (?)", questions).Delete(&entities.QuizOption{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("quiz_id = ?", id).Delete(&entities.QuizQuestion{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entities.Quiz{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *QuizRepository) GetQuestion(id uint) (entities.QuizQuestion, error) {
+	var question entities.QuizQuestion
+	result := common.DB.Preload("Options", orderByPosition).First(&question, id)
+	return question, result.Error
+}
+
+// CreateQuestion appends the question with its options to the end of its quiz
+func (r *QuizRepository) CreateQuestion(question *entities.QuizQuestion) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&entities.QuizQuestion{}).
+			Where("quiz_id = ?", question.QuizID).
+			Select("COALESCE(MAX(position), -1)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		question.Position = last + 1
+		if err := tx.Omit(clause.Associations).Create(question).Error; err != nil {
+			return err
+		}
+		return createOptions(tx, question)
+	})
+}
+
+// UpdateQuestion saves the question and replaces its options in one transaction
+func (r *QuizRepository) UpdateQuestion(question *entities.QuizQuestion) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Save(question)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no rows affected")
+		}
+
+		if err := tx.Where("question_id = ?", question.ID).Delete(&entities.QuizOption{}).Error; err != nil {
+			return err
+		}
+		return createOptions(tx, question)
+	})
+}
+
+func (r *QuizRepository) DeleteQuestion(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("question_id = ?", id).Delete(&entities.QuizOption{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&entities.QuizQuestion{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *QuizRepository) ReorderQuestions(quizID uint, questionIDs []uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var existing []uint
+		if err := tx.Model(&entities.QuizQuestion{}).Where("quiz_id = ?", quizID).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		if !common.SameIDs(existing, questionIDs) {
+			return ErrInvalidOrder
+		}
+
+		for position, id := range questionIDs {
+			if err := tx.Model(&entities.QuizQuestion{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *QuizRepository) HasAttempts(quizID uint) (bool, error) {
+	var count int64
+	result := common.DB.Model(&entities.QuizAttempt{}).Where("quiz_id = ?", quizID).Count(&count)
+	return count > 0, result.Error
+}
+
+// GradeItemLinked reports whether a quiz other than quizID or any assignment already feeds the grade item
+func (r *QuizRepository) GradeItemLinked(itemID uint, quizID uint) (bool, error) {
+	var quizzes int64
+	result := common.DB.Model(&entities.Quiz{}).Where("grade_item_id = ? AND id <> ?", itemID, quizID).Count(&quizzes)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	var assignments int64
+	result = common.DB.Model(&entities.Assignment{}).Where("grade_item_id = ?", itemID).Count(&assignments)
+	return quizzes+assignments > 0, result.Error
+}
+
+// GetAttempts lists the quiz's attempts by student and attempt number, without their responses
+func (r *QuizRepository) GetAttempts(quizID uint, studentID *uint) ([]entities.QuizAttempt, error) {
+	query := common.DB.Where("quiz_id = ?", quizID)
+	if studentID != nil {
+		query = query.Where("student_id = ?", *studentID)
+	}
+
+	var attempts []entities.QuizAttempt
+	result := query.Order("student_id, number").Find(&attempts)
+	return attempts, result.Error
+}
+
+func (r *QuizRepository) GetAttempt(id uint) (entities.QuizAttempt, error) {
+	var attempt entities.QuizAttempt
+	result := common.DB.Preload("Responses").First(&attempt, id)
+	return attempt, result.Error
+}
+
+// CreateAttempt starts the student's next attempt. The quiz row is locked so concurrent starts
+// cannot open two attempts at once or exceed maxAttempts, where 0 means no limit
+func (r *QuizRepository) CreateAttempt(attempt *entities.QuizAttempt, maxAttempts int) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		var quiz entities.Quiz
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&quiz, attempt.QuizID).Error; err != nil {
+			return err
+		}
+
+		var open int64
+		if err := tx.Model(&entities.QuizAttempt{}).
+			Where("quiz_id = ? AND student_id = ? AND submitted_at IS NULL", attempt.QuizID, attempt.StudentID).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return ErrAttemptInProgress
+		}
+
+		var taken int
+		if err := tx.Model(&entities.QuizAttempt{}).
+			Where("quiz_id = ? AND student_id = ?", attempt.QuizID, attempt.StudentID).
+			Select("COALESCE(MAX(number), 0)").
+			Scan(&taken).Error; err != nil {
+			return err
+		}
+		if maxAttempts > 0 && taken >= maxAttempts {
+			return ErrNoAttemptsLeft
+		}
+
+		attempt.Number = taken + 1
+		return tx.Omit(clause.Associations).Create(attempt).Error
+	})
+}
+
+// SaveResponses stores the answers, replacing earlier answers to the same questions
+func (r *QuizRepository) SaveResponses(responses []entities.QuizResponse) error {
+	if len(responses) == 0 {
+		return nil
+	}
+	return common.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "attempt_id"}, {Name: "question_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"option_ids", "number", "text", "updated_at"}),
+	}).Create(&responses).Error
+}
+
+// FinishAttempt stores the scored responses and closes the attempt. It fails with
+// ErrAttemptSubmitted when the attempt was closed in the meantime
+func (r *QuizRepository) FinishAttempt(attempt *entities.QuizAttempt) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.QuizAttempt{}).
+			Where("id = ? AND submitted_at IS NULL", attempt.ID).
+			Updates(map[string]interface{}{
+				"submitted_at": attempt.SubmittedAt,
+				"score":        attempt.Score,
+				"max_score":    attempt.MaxScore,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAttemptSubmitted
+		}
+
+		for i := range attempt.Responses {
+			if err := tx.Save(&attempt.Responses[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReviewResponse saves a teacher's points and feedback, then recomputes the attempt's score
+func (r *QuizRepository) ReviewResponse(response *entities.QuizResponse) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(response).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(
+			"UPDATE quiz_attempts SET score = (SELECT COALESCE(SUM(points), 0) FROM quiz_responses WHERE attempt_id = ?), updated_at = NOW() WHERE id = ?",
+			response.AttemptID, response.AttemptID,
+		).Error
+	})
+}
+
+func (r *QuizRepository) GetStudent(id uint) (entities.Student, error) {
+	var student entities.Student
+	result := common.DB.First(&student, id)
+	return student, result.Error
+}
+
+// SaveGrade writes the grade's points, replacing the points of any earlier grade for the same
+// item and student. A teacher's feedback and excusal are kept
+func (r *QuizRepository) SaveGrade(grade *entities.Grade) error {
+	return common.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "grade_item_id"}, {Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"points", "graded_by", "updated_at"}),
+	}).Create(grade).Error
+}
+
+func createOptions(tx *gorm.DB, question *entities.QuizQuestion) error {
+	if len(question.Options) == 0 {
+		return nil
+	}
+	for i := range question.Options {
+		question.Options[i].ID = 0
+		question.Options[i].LessonID = question.LessonID
+		question.Options[i].QuestionID = question.ID
+		question.Options[i].Position = i
+	}
+	return tx.Create(&question.Options).Error
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
@@ -0,0 +1,42 @@
+package quizzes
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *QuizHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/quizzes").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.List).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("", handler.Create).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}", handler.Get).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/questions", handler.AddQuestion).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/questions/order", handler.ReorderQuestions).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/questions/{questionID:[0-9]+}", handler.UpdateQuestion).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/questions/{questionID:[0-9]+}", handler.DeleteQuestion).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/attempts", handler.ListAttempts).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/attempts/{attemptID:[0-9]+}", handler.GetAttempt).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{quizID:[0-9]+}/attempts/{attemptID:[0-9]+}/responses/{questionID:[0-9]+}", handler.ReviewResponse).Methods(http.MethodPut)
+
+	// Student-only endpoints
+	studentRoutes := router.PathPrefix("/api/student").Subrouter()
+	studentRoutes.Use(authMiddleware)
+	studentRoutes.Use(middleware.RequireRole("student"))
+	studentRoutes.HandleFunc("/lessons/{lessonID:[0-9]+}/quizzes", handler.ListForStudent).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/quizzes/{quizID:[0-9]+}", handler.GetForStudent).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/quizzes/{quizID:[0-9]+}/attempts", handler.StartAttempt).Methods(http.MethodPost)
+	studentRoutes.HandleFunc("/quizzes/{quizID:[0-9]+}/attempts/{attemptID:[0-9]+}", handler.GetOwnAttempt).Methods(http.MethodGet)
+	studentRoutes.HandleFunc("/quizzes/{quizID:[0-9]+}/attempts/{attemptID:[0-9]+}/answers", handler.SaveAnswers).Methods(http.MethodPut)
+	studentRoutes.HandleFunc("/quizzes/{quizID:[0-9]+}/attempts/{attemptID:[0-9]+}/submit", handler.SubmitAttempt).Methods(http.MethodPost)
+}
@@ -0,0 +1,112 @@
+package quizzes
+
+import (
+	"lesson-management/entities"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// scoreResponse marks the response correct and awards the question's points when it matches
+// the question's answer. Choice questions are all or nothing: every correct option and no
+// other must be chosen
+func scoreResponse(question *entities.QuizQuestion, response *entities.QuizResponse) {
+	response.Correct = isCorrect(question, response)
+	response.Points = 0
+	if response.Correct {
+		response.Points = question.Points
+	}
+}
+
+func isCorrect(question *entities.QuizQuestion, response *entities.QuizResponse) bool {
+	switch question.Kind {
+	case entities.QuestionSingleChoice, entities.QuestionMultipleChoice:
+		chosen := make(map[uint]bool, len(response.OptionIDs))
+		for _, id := range response.OptionIDs {
+			chosen[id] = true
+		}
+		if len(chosen) == 0 {
+			return false
+		}
+		for _, option := range question.Options {
+			if option.Correct != chosen[option.ID] {
+				return false
+			}
+		}
+		return true
+	case entities.QuestionNumeric:
+		if response.Number == nil || question.Answer == nil {
+			return false
+		}
+		// Allow for floating point error at the edge of the tolerance
+		return math.Abs(*response.Number-*question.Answer) <= question.Tolerance+1e-9
+	case entities.QuestionShortText:
+		answer := normalizeText(response.Text, question.CaseSensitive)
+		if answer == "" {
+			return false
+		}
+		for _, option := range question.Options {
+			if normalizeText(option.Text, question.CaseSensitive) == answer {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// normalizeText trims the text and collapses runs of whitespace, so short text answers
+// don't fail on spacing. Unless caseSensitive is set the comparison also ignores case
+func normalizeText(text string, caseSensitive bool) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if !caseSensitive {
+		text = strings.ToLower(text)
+	}
+	return text
+}
+
+// maxScore is the sum of the points of every question
+func maxScore(questions []entities.QuizQuestion) float64 {
+	var total float64
+	for _, question := range questions {
+		total += question.Points
+	}
+	return total
+}
+
+// dealQuestions returns the questions in the order the attempt shows them. A shuffled quiz
+// is shuffled with the attempt's seed, so the order stays the same every time it is loaded
+func dealQuestions(quiz *entities.Quiz, attempt *entities.QuizAttempt) []entities.QuizQuestion {
+	questions := make([]entities.QuizQuestion, len(quiz.Questions))
+	copy(questions, quiz.Questions)
+	if quiz.ShuffleQuestions {
+		shuffler := rand.New(rand.NewSource(attempt.Seed))
+		shuffler.Shuffle(len(questions), func(i, j int) {
+			questions[i], questions[j] = questions[j], questions[i]
+		})
+	}
+	return questions
+}
+
+// countedAttempt picks the submitted attempt whose score counts under the quiz's score
+// policy, or nil when the student has not finished any
+func countedAttempt(policy string, attempts []entities.QuizAttempt) *entities.QuizAttempt {
+	var counted *entities.QuizAttempt
+	for i := range attempts {
+		attempt := &attempts[i]
+		if !attempt.IsSubmitted() {
+			continue
+		}
+
+		switch {
+		case counted == nil:
+			counted = attempt
+		case policy == entities.QuizScoreLatest && attempt.Number > counted.Number:
+			counted = attempt
+		case policy != entities.QuizScoreLatest && attempt.Score > counted.Score:
+			counted = attempt
+		}
+	}
+	return counted
+}
@@ -0,0 +1,146 @@
+package quizzes
+
+import (
+	"lesson-management/entities"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func TestIsCorrect(t *testing.T) {
+	single := &entities.QuizQuestion{
+		Kind:    entities.QuestionSingleChoice,
+		Options: []entities.QuizOption{{ID: 1, Correct: true}, {ID: 2}},
+	}
+	multiple := &entities.QuizQuestion{
+		Kind:    entities.QuestionMultipleChoice,
+		Options: []entities.QuizOption{{ID: 1, Correct: true}, {ID: 2, Correct: true}, {ID: 3}},
+	}
+	numeric := &entities.QuizQuestion{Kind: entities.QuestionNumeric, Answer: float(3.14), Tolerance: 0.01}
+	shortText := &entities.QuizQuestion{
+		Kind:    entities.QuestionShortText,
+		Options: []entities.QuizOption{{Text: "Photo synthesis"}, {Text: "photosynthesis"}},
+	}
+	caseSensitive := &entities.QuizQuestion{
+		Kind:          entities.QuestionShortText,
+		CaseSensitive: true,
+		Options:       []entities.QuizOption{{Text: "NaCl"}},
+	}
+
+	for _, test := range []struct {
+		name     string
+		question *entities.QuizQuestion
+		response entities.QuizResponse
+		want     bool
+	}{
+		{"single choice correct", single, entities.QuizResponse{OptionIDs: []uint{1}}, true},
+		{"single choice wrong", single, entities.QuizResponse{OptionIDs: []uint{2}}, false},
+		{"single choice both", single, entities.QuizResponse{OptionIDs: []uint{1, 2}}, false},
+		{"no options chosen", single, entities.QuizResponse{}, false},
+		{"multiple choice all correct", multiple, entities.QuizResponse{OptionIDs: []uint{2, 1}}, true},
+		{"multiple choice repeated option", multiple, entities.QuizResponse{OptionIDs: []uint{1, 2, 2}}, true},
+		{"multiple choice partial", multiple, entities.QuizResponse{OptionIDs: []uint{1}}, false},
+		{"multiple choice extra", multiple, entities.QuizResponse{OptionIDs: []uint{1, 2, 3}}, false},
+		{"numeric exact", numeric, entities.QuizResponse{Number: float(3.14)}, true},
+		{"numeric at tolerance", numeric, entities.QuizResponse{Number: float(3.15)}, true},
+		{"numeric outside tolerance", numeric, entities.QuizResponse{Number: float(3.16)}, false},
+		{"numeric unanswered", numeric, entities.QuizResponse{}, false},
+		{"short text spacing and case", shortText, entities.QuizResponse{Text: "  photo   SYNTHESIS "}, true},
+		{"short text other answer", shortText, entities.QuizResponse{Text: "Photosynthesis"}, true},
+		{"short text wrong", shortText, entities.QuizResponse{Text: "respiration"}, false},
+		{"short text blank", shortText, entities.QuizResponse{Text: "   "}, false},
+		{"case sensitive match", caseSensitive, entities.QuizResponse{Text: "NaCl"}, true},
+		{"case sensitive mismatch", caseSensitive, entities.QuizResponse{Text: "nacl"}, false},
+		{"unknown kind", &entities.QuizQuestion{Kind: "essay"}, entities.QuizResponse{Text: "x"}, false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := isCorrect(test.question, &test.response); got != test.want {
+				t.Errorf("isCorrect() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScoreResponse(t *testing.T) {
+	question := &entities.QuizQuestion{Kind: entities.QuestionNumeric, Answer: float(2), Points: 4}
+
+	response := entities.QuizResponse{Number: float(2), Points: 1}
+	scoreResponse(question, &response)
+	if !response.Correct || response.Points != 4 {
+		t.Errorf("correct response scored %v with %v points, want true with 4", response.Correct, response.Points)
+	}
+
+	response = entities.QuizResponse{Number: float(3), Points: 4}
+	scoreResponse(question, &response)
+	if response.Correct || response.Points != 0 {
+		t.Errorf("wrong response scored %v with %v points, want false with 0", response.Correct, response.Points)
+	}
+}
+
+func TestCountedAttempt(t *testing.T) {
+	submitted := time.Now()
+	attempts := []entities.QuizAttempt{
+		{ID: 1, Number: 1, Score: 8, SubmittedAt: &submitted},
+		{ID: 2, Number: 2, Score: 5, SubmittedAt: &submitted},
+		{ID: 3, Number: 3, Score: 10},
+	}
+
+	for _, test := range []struct {
+		name     string
+		policy   string
+		attempts []entities.QuizAttempt
+		want     uint
+	}{
+		{"best ignores unsubmitted", entities.QuizScoreBest, attempts, 1},
+		{"latest submitted", entities.QuizScoreLatest, attempts, 2},
+		{"none submitted", entities.QuizScoreBest, attempts[2:], 0},
+		{"no attempts", entities.QuizScoreLatest, nil, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			counted := countedAttempt(test.policy, test.attempts)
+			var got uint
+			if counted != nil {
+				got = counted.ID
+			}
+			if got != test.want {
+				t.Errorf("countedAttempt() = attempt %d, want attempt %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDealQuestions(t *testing.T) {
+	quiz := &entities.Quiz{}
+	for i := uint(1); i <= 10; i++ {
+		quiz.Questions = append(quiz.Questions, entities.QuizQuestion{ID: i})
+	}
+	ids := func(questions []entities.QuizQuestion) []uint {
+		var ids []uint
+		for _, question := range questions {
+			ids = append(ids, question.ID)
+		}
+		return ids
+	}
+	original := ids(quiz.Questions)
+
+	if got := ids(dealQuestions(quiz, &entities.QuizAttempt{Seed: 42})); !reflect.DeepEqual(got, original) {
+		t.Errorf("unshuffled quiz dealt %v, want %v", got, original)
+	}
+
+	quiz.ShuffleQuestions = true
+	first := ids(dealQuestions(quiz, &entities.QuizAttempt{Seed: 42}))
+	again := ids(dealQuestions(quiz, &entities.QuizAttempt{Seed: 42}))
+	if !reflect.DeepEqual(first, again) {
+		t.Errorf("same seed dealt %v then %v", first, again)
+	}
+	if reflect.DeepEqual(first, original) {
+		t.Errorf("shuffled quiz kept its order %v", first)
+	}
+	if !reflect.DeepEqual(ids(quiz.Questions), original) {
+		t.Errorf("dealing reordered the quiz's own questions to %v", ids(quiz.Questions))
+	}
+}
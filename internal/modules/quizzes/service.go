@@ -0,0 +1,1120 @@
+package quizzes
+
+import (
+	"errors"
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/gradebook"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/internal/modules/progress"
+	"lesson-management/models"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// attemptGracePeriod allows for answers sent just before the deadline arriving just after it
+const attemptGracePeriod = 30 * time.Second
+
+var questionKinds = map[string]bool{
+	entities.QuestionSingleChoice:   true,
+	entities.QuestionMultipleChoice: true,
+	entities.QuestionNumeric:        true,
+	entities.QuestionShortText:      true,
+}
+
+var (
+	ErrTitleRequired          = errors.New("quiz title is required")
+	ErrInvalidTimeLimit       = errors.New("time limit must not be negative")
+	ErrInvalidMaxAttempts     = errors.New("max attempts must not be negative")
+	ErrInvalidScorePolicy     = errors.New("score policy must be best or latest")
+	ErrInvalidPassPercent     = errors.New("pass percent must be between 0 and 100")
+	ErrInvalidWindow          = errors.New("quiz must close after it opens")
+	ErrNoQuestions            = errors.New("a published quiz needs at least one question")
+	ErrInvalidKind            = errors.New("question kind must be single_choice, multiple_choice, numeric or short_text")
+	ErrPromptRequired         = errors.New("question prompt is required")
+	ErrInvalidPoints          = errors.New("question points must be greater than zero")
+	ErrInvalidOptions         = errors.New("choice questions need at least two options, each with text")
+	ErrInvalidCorrectOptions  = errors.New("single choice questions need exactly one correct option and multiple choice questions at least one")
+	ErrAnswerRequired         = errors.New("numeric questions need an answer and a tolerance that is not negative")
+	ErrAcceptedAnswerRequired = errors.New("short text questions need at least one accepted answer")
+	ErrInvalidOrder           = errors.New("order must list every question exactly once")
+	ErrQuizHasAttempts        = errors.New("questions can't be changed once students have attempted the quiz")
+	ErrQuizClosed             = errors.New("quiz is not open for attempts")
+	ErrNoAttemptsLeft         = errors.New("no attempts left for this quiz")
+	ErrAttemptInProgress      = errors.New("an attempt at this quiz is already in progress")
+	ErrAttemptSubmitted       = errors.New("attempt has already been submitted")
+	ErrAttemptExpired         = errors.New("time is up, the attempt was submitted with the answers saved before the deadline")
+	ErrAttemptNotSubmitted    = errors.New("attempt can only be reviewed once it is submitted")
+	ErrInvalidAnswer          = errors.New("each answer must be for a different question of the quiz and fit its kind")
+	ErrInvalidReviewPoints    = errors.New("points must be between zero and the question's points")
+	ErrGradeItemLinked        = errors.New("grade item is already linked to another quiz or assignment")
+)
+
+type IQuizService interface {
+	GetQuizzes(lessonID uint64, teacherID uint) ([]entities.Quiz, error)
+	GetQuiz(lessonID uint64, quizID uint64, teacherID uint) (*entities.Quiz, error)
+	CreateQuiz(lessonID uint64, teacherID uint, request *models.CreateQuizRequest) (*entities.Quiz, error)
+	UpdateQuiz(lessonID uint64, quizID uint64, teacherID uint, request *models.PatchQuizRequest) (*entities.Quiz, error)
+	DeleteQuiz(lessonID uint64, quizID uint64, teacherID uint) error
+	AddQuestion(lessonID uint64, quizID uint64, teacherID uint, request *models.QuizQuestionRequest) (*entities.QuizQuestion, error)
+	UpdateQuestion(lessonID uint64, quizID uint64, questionID uint64, teacherID uint, request *models.QuizQuestionRequest) (*entities.QuizQuestion, error)
+	DeleteQuestion(lessonID uint64, quizID uint64, questionID uint64, teacherID uint) error
+	ReorderQuestions(lessonID uint64, quizID uint64, teacherID uint, questionIDs []uint) (*entities.Quiz, error)
+	GetAttemptSummaries(lessonID uint64, quizID uint64, teacherID uint) ([]models.QuizAttemptSummary, error)
+	GetAttemptReview(lessonID uint64, quizID uint64, attemptID uint64, teacherID uint) (*models.QuizAttemptReview, error)
+	ReviewResponse(lessonID uint64, quizID uint64, attemptID uint64, questionID uint64, teacherID uint, request *models.ReviewQuizResponseRequest) (*models.QuizAttemptReview, error)
+	GetStudentQuizzes(lessonID uint64, studentID uint) ([]models.StudentQuiz, error)
+	GetStudentQuiz(quizID uint64, studentID uint) (*models.StudentQuiz, error)
+	StartAttempt(quizID uint64, studentID uint) (*models.QuizAttemptView, bool, error)
+	GetOwnAttempt(quizID uint64, attemptID uint64, studentID uint) (*models.QuizAttemptView, error)
+	SaveAnswers(quizID uint64, attemptID uint64, studentID uint, request *models.SaveQuizAnswersRequest) (*models.QuizAttemptView, error)
+	SubmitAttempt(quizID uint64, attemptID uint64, studentID uint, request *models.SaveQuizAnswersRequest) (*models.QuizAttemptView, error)
+}
+
+type QuizService struct {
+	repo             IQuizRepository
+	lessonService    lessons.ILessonService
+	gradebookService gradebook.IGradebookService
+	progressService  progress.IProgressService
+}
+
+func NewQuizService(repo IQuizRepository, lessonService lessons.ILessonService, gradebookService gradebook.IGradebookService, progressService progress.IProgressService) IQuizService {
+	return &QuizService{
+		repo:             repo,
+		lessonService:    lessonService,
+		gradebookService: gradebookService,
+		progressService:  progressService,
+	}
+}
+
+func (s *QuizService) GetQuizzes(lessonID uint64, teacherID uint) ([]entities.Quiz, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetQuizzes(lesson.ID, false)
+}
+
+// GetQuiz returns the quiz with its questions, including the correct answers
+func (s *QuizService) GetQuiz(lessonID uint64, quizID uint64, teacherID uint) (*entities.Quiz, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.lessonQuiz(lesson, quizID)
+}
+
+// CreateQuiz creates an unpublished quiz, so questions can be added before students see it
+func (s *QuizService) CreateQuiz(lessonID uint64, teacherID uint, request *models.CreateQuizRequest) (*entities.Quiz, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz := &entities.Quiz{
+		LessonID:         lesson.ID,
+		Title:            strings.TrimSpace(request.Title),
+		Description:      request.Description,
+		GradeItemID:      request.GradeItemID,
+		TimeLimitMinutes: request.TimeLimitMinutes,
+		MaxAttempts:      request.MaxAttempts,
+		ShuffleQuestions: request.ShuffleQuestions,
+		ScorePolicy:      request.ScorePolicy,
+		PassPercent:      request.PassPercent,
+		OpensAt:          request.OpensAt,
+		ClosesAt:         request.ClosesAt,
+		CreatedBy:        teacherID,
+	}
+	if quiz.ScorePolicy == "" {
+		quiz.ScorePolicy = entities.QuizScoreBest
+	}
+	if err := s.validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateQuiz(quiz); err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// UpdateQuiz changes the quiz's settings. Linking a grade item or changing the score policy
+// rewrites the grades of every student who has finished an attempt
+func (s *QuizService) UpdateQuiz(lessonID uint64, quizID uint64, teacherID uint, request *models.PatchQuizRequest) (*entities.Quiz, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := s.lessonQuiz(lesson, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Title != nil {
+		quiz.Title = strings.TrimSpace(*request.Title)
+	}
+	if request.Description != nil {
+		quiz.Description = *request.Description
+	}
+	if request.GradeItemID != nil {
+		quiz.GradeItemID = request.GradeItemID
+		if *request.GradeItemID == 0 {
+			quiz.GradeItemID = nil
+		}
+	}
+	if request.TimeLimitMinutes != nil {
+		quiz.TimeLimitMinutes = *request.TimeLimitMinutes
+	}
+	if request.MaxAttempts != nil {
+		quiz.MaxAttempts = *request.MaxAttempts
+	}
+	if request.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *request.ShuffleQuestions
+	}
+	if request.ScorePolicy != nil {
+		quiz.ScorePolicy = *request.ScorePolicy
+	}
+	if request.PassPercent != nil {
+		quiz.PassPercent = *request.PassPercent
+	}
+	if request.Published != nil {
+		quiz.Published = *request.Published
+	}
+	if request.OpensAt != nil {
+		quiz.OpensAt = request.OpensAt
+	}
+	if request.ClosesAt != nil {
+		quiz.ClosesAt = request.ClosesAt
+	}
+	if err := s.validateQuiz(quiz); err != nil {
+		return nil, err
+	}
+	if quiz.Published && len(quiz.Questions) == 0 {
+		return nil, ErrNoQuestions
+	}
+
+	if err := s.repo.UpdateQuiz(quiz); err != nil {
+		return nil, err
+	}
+
+	if request.GradeItemID != nil || request.ScorePolicy != nil {
+		s.recordGrades(quiz)
+	}
+
+	return quiz, nil
+}
+
+// DeleteQuiz removes the quiz with its questions and attempts. Grades it already wrote stay in the gradebook
+func (s *QuizService) DeleteQuiz(lessonID uint64, quizID uint64, teacherID uint) error {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	quiz, err := s.lessonQuiz(lesson, quizID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteQuiz(quiz.ID)
+}
+
+func (s *QuizService) AddQuestion(lessonID uint64, quizID uint64, teacherID uint, request *models.QuizQuestionRequest) (*entities.QuizQuestion, error) {
+	quiz, err := s.changeableQuiz(lessonID, quizID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	question := &entities.QuizQuestion{
+		LessonID: quiz.LessonID,
+		QuizID:   quiz.ID,
+	}
+	if err := applyQuestion(question, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateQuestion(question); err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+// UpdateQuestion replaces the question, including all of its options
+func (s *QuizService) UpdateQuestion(lessonID uint64, quizID uint64, questionID uint64, teacherID uint, request *models.QuizQuestionRequest) (*entities.QuizQuestion, error) {
+	quiz, err := s.changeableQuiz(lessonID, quizID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := s.quizQuestion(quiz, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyQuestion(question, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateQuestion(question); err != nil {
+		return nil, err
+	}
+
+	return question, nil
+}
+
+func (s *QuizService) DeleteQuestion(lessonID uint64, quizID uint64, questionID uint64, teacherID uint) error {
+	quiz, err := s.changeableQuiz(lessonID, quizID, teacherID)
+	if err != nil {
+		return err
+	}
+
+	question, err := s.quizQuestion(quiz, questionID)
+	if err != nil {
+		return err
+	}
+	if quiz.Published && len(quiz.Questions) == 1 {
+		return ErrNoQuestions
+	}
+
+	return s.repo.DeleteQuestion(question.ID)
+}
+
+// ReorderQuestions sets the order questions appear in when the quiz is not shuffled
+func (s *QuizService) ReorderQuestions(lessonID uint64, quizID uint64, teacherID uint, questionIDs []uint) (*entities.Quiz, error) {
+	quiz, err := s.changeableQuiz(lessonID, quizID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ReorderQuestions(quiz.ID, questionIDs); err != nil {
+		return nil, err
+	}
+
+	reordered, err := s.repo.GetQuiz(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reordered, nil
+}
+
+// GetAttemptSummaries lists every enrolled student with their attempts and counted score
+func (s *QuizService) GetAttemptSummaries(lessonID uint64, quizID uint64, teacherID uint) ([]models.QuizAttemptSummary, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := s.lessonQuiz(lesson, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.lessonService.GetEnrolledStudents(uint64(lesson.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.repo.GetAttempts(quiz.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint][]entities.QuizAttempt, len(students))
+	now := time.Now()
+	for i := range attempts {
+		if err := s.finishIfExpired(quiz, &attempts[i], now); err != nil {
+			return nil, err
+		}
+		byStudent[attempts[i].StudentID] = append(byStudent[attempts[i].StudentID], attempts[i])
+	}
+
+	total := maxScore(quiz.Questions)
+	summaries := make([]models.QuizAttemptSummary, 0, len(students))
+	for _, student := range students {
+		summary := models.QuizAttemptSummary{
+			StudentID: student.ID,
+			Name:      student.Name,
+			Attempts:  byStudent[student.ID],
+			MaxScore:  total,
+		}
+		if summary.Attempts == nil {
+			summary.Attempts = []entities.QuizAttempt{}
+		}
+		if counted := countedAttempt(quiz.ScorePolicy, summary.Attempts); counted != nil {
+			score := counted.Score
+			summary.Score = &score
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
+
+// GetAttemptReview returns a student's attempt with their responses next to the full questions
+func (s *QuizService) GetAttemptReview(lessonID uint64, quizID uint64, attemptID uint64, teacherID uint) (*models.QuizAttemptReview, error) {
+	lesson, err := s.lessonService.TeacherLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := s.lessonQuiz(lesson, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := s.quizAttempt(quiz, attemptID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.review(quiz, attempt)
+}
+
+// ReviewResponse lets a teacher override the points of one answer, for example to accept a
+// short text answer the quiz did not list. The attempt's score and the grade follow
+func (s *QuizService) ReviewResponse(lessonID uint64, quizID uint64, attemptID uint64, questionID uint64, teacherID uint, request *models.ReviewQuizResponseRequest) (*models.QuizAttemptReview, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := s.lessonQuiz(lesson, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt, err := s.quizAttempt(quiz, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if !attempt.IsSubmitted() {
+		return nil, ErrAttemptNotSubmitted
+	}
+
+	question, err := s.quizQuestion(quiz, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Points < 0 || request.Points > question.Points {
+		return nil, ErrInvalidReviewPoints
+	}
+
+	response := &entities.QuizResponse{
+		LessonID:   attempt.LessonID,
+		AttemptID:  attempt.ID,
+		QuestionID: question.ID,
+	}
+	for i := range attempt.Responses {
+		if attempt.Responses[i].QuestionID == question.ID {
+			response = &attempt.Responses[i]
+			break
+		}
+	}
+	response.Points = request.Points
+	response.Correct = request.Points == question.Points
+	response.Feedback = strings.TrimSpace(request.Feedback)
+	response.ReviewedBy = &teacherID
+
+	if err := s.repo.ReviewResponse(response); err != nil {
+		return nil, err
+	}
+	s.recordGrade(quiz, attempt.StudentID)
+
+	reviewed, err := s.repo.GetAttempt(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordPass(quiz, &reviewed)
+
+	return s.review(quiz, &reviewed)
+}
+
+// GetStudentQuizzes lists the published quizzes of a lesson the student is enrolled in
+func (s *QuizService) GetStudentQuizzes(lessonID uint64, studentID uint) ([]models.StudentQuiz, error) {
+	lesson, err := s.lessonService.GetLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(lesson.ID), studentID); err != nil {
+		return nil, err
+	}
+
+	quizzes, err := s.repo.GetQuizzes(lesson.ID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	studentQuizzes := make([]models.StudentQuiz, 0, len(quizzes))
+	for _, listed := range quizzes {
+		quiz, err := s.repo.GetQuiz(listed.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		studentQuiz, err := s.studentQuiz(&quiz, studentID)
+		if err != nil {
+			return nil, err
+		}
+		studentQuizzes = append(studentQuizzes, *studentQuiz)
+	}
+
+	return studentQuizzes, nil
+}
+
+func (s *QuizService) GetStudentQuiz(quizID uint64, studentID uint) (*models.StudentQuiz, error) {
+	quiz, err := s.studentQuizEntity(quizID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.studentQuiz(quiz, studentID)
+}
+
+// StartAttempt starts the student's next attempt, or resumes the one in progress. The
+// returned flag tells whether a new attempt was started
+func (s *QuizService) StartAttempt(quizID uint64, studentID uint) (*models.QuizAttemptView, bool, error) {
+	quiz, err := s.studentQuizEntity(quizID, studentID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	lesson, err := s.lessonService.GetLesson(uint64(quiz.LessonID))
+	if err != nil {
+		return nil, false, err
+	}
+	if lesson.Status == entities.LessonArchived {
+		return nil, false, lessons.ErrLessonArchived
+	}
+
+	attempts, err := s.repo.GetAttempts(quiz.ID, &studentID)
+	if err != nil {
+		return nil, false, err
+	}
+	now := time.Now()
+	for i := range attempts {
+		if err := s.finishIfExpired(quiz, &attempts[i], now); err != nil {
+			return nil, false, err
+		}
+		if !attempts[i].IsSubmitted() {
+			resumed, err := s.repo.GetAttempt(attempts[i].ID)
+			if err != nil {
+				return nil, false, err
+			}
+			return attemptView(quiz, &resumed), false, nil
+		}
+	}
+
+	if !quiz.IsOpen(now) {
+		return nil, false, ErrQuizClosed
+	}
+
+	attempt := &entities.QuizAttempt{
+		LessonID:   quiz.LessonID,
+		QuizID:     quiz.ID,
+		StudentID:  studentID,
+		Seed:       rand.Int63(),
+		StartedAt:  now,
+		DeadlineAt: attemptDeadline(quiz, now),
+		MaxScore:   maxScore(quiz.Questions),
+	}
+	if err := s.repo.CreateAttempt(attempt, quiz.MaxAttempts); err != nil {
+		return nil, false, err
+	}
+
+	return attemptView(quiz, attempt), true, nil
+}
+
+func (s *QuizService) GetOwnAttempt(quizID uint64, attemptID uint64, studentID uint) (*models.QuizAttemptView, error) {
+	quiz, attempt, err := s.ownAttempt(quizID, attemptID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.finishIfExpired(quiz, attempt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return attemptView(quiz, attempt), nil
+}
+
+// SaveAnswers stores answers while the attempt is in progress, without scoring them
+func (s *QuizService) SaveAnswers(quizID uint64, attemptID uint64, studentID uint, request *models.SaveQuizAnswersRequest) (*models.QuizAttemptView, error) {
+	quiz, attempt, err := s.ownAttempt(quizID, attemptID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireInProgress(quiz, attempt); err != nil {
+		return nil, err
+	}
+
+	responses, err := buildResponses(quiz, attempt, request.Answers)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SaveResponses(responses); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.repo.GetAttempt(attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return attemptView(quiz, &saved), nil
+}
+
+// SubmitAttempt scores the attempt with the saved answers and any sent along, closes it and
+// writes the student's counted score into the gradebook
+func (s *QuizService) SubmitAttempt(quizID uint64, attemptID uint64, studentID uint, request *models.SaveQuizAnswersRequest) (*models.QuizAttemptView, error) {
+	quiz, attempt, err := s.ownAttempt(quizID, attemptID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireInProgress(quiz, attempt); err != nil {
+		return nil, err
+	}
+
+	responses, err := buildResponses(quiz, attempt, request.Answers)
+	if err != nil {
+		return nil, err
+	}
+	for _, response := range responses {
+		merged := false
+		for i := range attempt.Responses {
+			if attempt.Responses[i].QuestionID == response.QuestionID {
+				response.ID = attempt.Responses[i].ID
+				response.CreatedAt = attempt.Responses[i].CreatedAt
+				attempt.Responses[i] = response
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			attempt.Responses = append(attempt.Responses, response)
+		}
+	}
+
+	now := time.Now()
+	attempt.SubmittedAt = &now
+	if err := s.finish(quiz, attempt); err != nil {
+		return nil, err
+	}
+
+	return attemptView(quiz, attempt), nil
+}
+
+// requireInProgress fails unless the attempt still takes answers. An attempt found past its
+// deadline is submitted on the spot with the answers saved so far
+func (s *QuizService) requireInProgress(quiz *entities.Quiz, attempt *entities.QuizAttempt) error {
+	if attempt.IsSubmitted() {
+		return ErrAttemptSubmitted
+	}
+
+	if err := s.finishIfExpired(quiz, attempt, time.Now()); err != nil {
+		return err
+	}
+	if attempt.IsSubmitted() {
+		return ErrAttemptExpired
+	}
+
+	return nil
+}
+
+// finishIfExpired submits an attempt left open past its deadline, as of the deadline
+func (s *QuizService) finishIfExpired(quiz *entities.Quiz, attempt *entities.QuizAttempt, now time.Time) error {
+	if attempt.IsSubmitted() || attempt.DeadlineAt == nil || now.Before(attempt.DeadlineAt.Add(attemptGracePeriod)) {
+		return nil
+	}
+
+	expired, err := s.repo.GetAttempt(attempt.ID)
+	if err != nil {
+		return err
+	}
+	expired.SubmittedAt = expired.DeadlineAt
+
+	err = s.finish(quiz, &expired)
+	if errors.Is(err, ErrAttemptSubmitted) {
+		// Submitted by another request in the meantime
+		expired, err = s.repo.GetAttempt(attempt.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	*attempt = expired
+	return nil
+}
+
+// finish scores the attempt's responses and closes it, then updates the student's grade
+func (s *QuizService) finish(quiz *entities.Quiz, attempt *entities.QuizAttempt) error {
+	questions := make(map[uint]*entities.QuizQuestion, len(quiz.Questions))
+	for i := range quiz.Questions {
+		questions[quiz.Questions[i].ID] = &quiz.Questions[i]
+	}
+
+	attempt.Score = 0
+	for i := range attempt.Responses {
+		question, ok := questions[attempt.Responses[i].QuestionID]
+		if !ok {
+			continue
+		}
+		scoreResponse(question, &attempt.Responses[i])
+		attempt.Score += attempt.Responses[i].Points
+	}
+	attempt.Score = gradebook.RoundPoints(attempt.Score)
+	attempt.MaxScore = maxScore(quiz.Questions)
+
+	if err := s.repo.FinishAttempt(attempt); err != nil {
+		return err
+	}
+
+	s.recordGrade(quiz, attempt.StudentID)
+	s.recordPass(quiz, attempt)
+	return nil
+}
+
+// recordPass counts the quiz towards the student's progress once an attempt passes it
+func (s *QuizService) recordPass(quiz *entities.Quiz, attempt *entities.QuizAttempt) {
+	if !quiz.Passes(attempt) {
+		return
+	}
+
+	if err := s.progressService.Record(quiz.LessonID, attempt.StudentID, entities.ProgressQuizPassed, quiz.ID); err != nil {
+		fmt.Println("Error while recording progress: ", err)
+	}
+}
+
+// recordGrades rewrites the grade of every student who has finished an attempt
+func (s *QuizService) recordGrades(quiz *entities.Quiz) {
+	if quiz.GradeItemID == nil {
+		return
+	}
+
+	attempts, err := s.repo.GetAttempts(quiz.ID, nil)
+	if err != nil {
+		fmt.Println("Error while writing quiz grades: ", err)
+		return
+	}
+
+	seen := make(map[uint]bool, len(attempts))
+	for _, attempt := range attempts {
+		if !seen[attempt.StudentID] {
+			seen[attempt.StudentID] = true
+			s.recordGrade(quiz, attempt.StudentID)
+		}
+	}
+}
+
+// recordGrade writes the student's counted score into the quiz's grade item, scaled to the
+// item's max points. The attempt is already stored, so a failure here is only logged
+func (s *QuizService) recordGrade(quiz *entities.Quiz, studentID uint) {
+	if quiz.GradeItemID == nil {
+		return
+	}
+
+	if err := s.writeGrade(quiz, studentID); err != nil {
+		fmt.Println("Error while writing quiz grade: ", err)
+	}
+}
+
+func (s *QuizService) writeGrade(quiz *entities.Quiz, studentID uint) error {
+	item, err := s.gradebookService.GetLessonItem(quiz.LessonID, *quiz.GradeItemID)
+	if err != nil {
+		return err
+	}
+
+	attempts, err := s.repo.GetAttempts(quiz.ID, &studentID)
+	if err != nil {
+		return err
+	}
+	counted := countedAttempt(quiz.ScorePolicy, attempts)
+	if counted == nil || counted.MaxScore <= 0 {
+		return nil
+	}
+
+	return s.repo.SaveGrade(&entities.Grade{
+		LessonID:    quiz.LessonID,
+		GradeItemID: item.ID,
+		StudentID:   studentID,
+		Points:      gradebook.RoundPoints(counted.Score / counted.MaxScore * item.MaxPoints),
+	})
+}
+
+// studentQuiz summarizes the quiz for the student, submitting any attempt left past its deadline
+func (s *QuizService) studentQuiz(quiz *entities.Quiz, studentID uint) (*models.StudentQuiz, error) {
+	attempts, err := s.repo.GetAttempts(quiz.ID, &studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range attempts {
+		if err := s.finishIfExpired(quiz, &attempts[i], now); err != nil {
+			return nil, err
+		}
+		attempts[i].Responses = nil
+	}
+	if attempts == nil {
+		attempts = []entities.QuizAttempt{}
+	}
+
+	studentQuiz := &models.StudentQuiz{
+		ID:               quiz.ID,
+		Title:            quiz.Title,
+		Description:      quiz.Description,
+		TimeLimitMinutes: quiz.TimeLimitMinutes,
+		MaxAttempts:      quiz.MaxAttempts,
+		ScorePolicy:      quiz.ScorePolicy,
+		PassPercent:      quiz.PassPercent,
+		OpensAt:          quiz.OpensAt,
+		ClosesAt:         quiz.ClosesAt,
+		Open:             quiz.IsOpen(now),
+		QuestionCount:    len(quiz.Questions),
+		MaxScore:         maxScore(quiz.Questions),
+		Attempts:         attempts,
+	}
+	if counted := countedAttempt(quiz.ScorePolicy, attempts); counted != nil {
+		score := counted.Score
+		studentQuiz.Score = &score
+	}
+	for i := range attempts {
+		if quiz.Passes(&attempts[i]) {
+			studentQuiz.Passed = true
+			break
+		}
+	}
+
+	return studentQuiz, nil
+}
+
+// review pairs the attempt with the questions in the order the student saw them
+func (s *QuizService) review(quiz *entities.Quiz, attempt *entities.QuizAttempt) (*models.QuizAttemptReview, error) {
+	if err := s.finishIfExpired(quiz, attempt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	student, err := s.repo.GetStudent(attempt.StudentID)
+	if err != nil {
+		return nil, err
+	}
+
+	review := &models.QuizAttemptReview{
+		StudentID: student.ID,
+		Name:      student.Name,
+		Attempt:   *attempt,
+		Questions: dealQuestions(quiz, attempt),
+	}
+	if review.Attempt.Responses == nil {
+		review.Attempt.Responses = []entities.QuizResponse{}
+	}
+
+	return review, nil
+}
+
+// ownAttempt loads one of the student's attempts at a quiz in a lesson they are enrolled in
+func (s *QuizService) ownAttempt(quizID uint64, attemptID uint64, studentID uint) (*entities.Quiz, *entities.QuizAttempt, error) {
+	quiz, err := s.repo.GetQuiz(uint(quizID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(quiz.LessonID), studentID); err != nil {
+		return nil, nil, err
+	}
+
+	attempt, err := s.quizAttempt(&quiz, attemptID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attempt.StudentID != studentID {
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+
+	return &quiz, attempt, nil
+}
+
+// studentQuizEntity loads a published quiz from a lesson the student is enrolled in
+func (s *QuizService) studentQuizEntity(quizID uint64, studentID uint) (*entities.Quiz, error) {
+	quiz, err := s.repo.GetQuiz(uint(quizID))
+	if err != nil {
+		return nil, err
+	}
+	if !quiz.Published {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := s.lessonService.RequireEnrollment(uint64(quiz.LessonID), studentID); err != nil {
+		return nil, err
+	}
+
+	return &quiz, nil
+}
+
+// quizAttempt loads an attempt with its responses, treating one at another quiz as not found
+func (s *QuizService) quizAttempt(quiz *entities.Quiz, attemptID uint64) (*entities.QuizAttempt, error) {
+	attempt, err := s.repo.GetAttempt(uint(attemptID))
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.QuizID != quiz.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &attempt, nil
+}
+
+// quizQuestion finds a question of the quiz, treating one from another quiz as not found
+func (s *QuizService) quizQuestion(quiz *entities.Quiz, questionID uint64) (*entities.QuizQuestion, error) {
+	for i := range quiz.Questions {
+		if uint64(quiz.Questions[i].ID) == questionID {
+			return &quiz.Questions[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// changeableQuiz loads a quiz whose questions may still be changed, which is only
+// until the first student starts an attempt
+func (s *QuizService) changeableQuiz(lessonID uint64, quizID uint64, teacherID uint) (*entities.Quiz, error) {
+	lesson, err := s.lessonService.EditableLesson(lessonID, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	quiz, err := s.lessonQuiz(lesson, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempted, err := s.repo.HasAttempts(quiz.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attempted {
+		return nil, ErrQuizHasAttempts
+	}
+
+	return quiz, nil
+}
+
+// lessonQuiz loads a quiz with its questions, treating one from another lesson as not found
+func (s *QuizService) lessonQuiz(lesson *entities.Lesson, quizID uint64) (*entities.Quiz, error) {
+	quiz, err := s.repo.GetQuiz(uint(quizID))
+	if err != nil {
+		return nil, err
+	}
+
+	if quiz.LessonID != lesson.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &quiz, nil
+}
+
+// validateQuiz checks the quiz's settings and that a linked grade item belongs to the same lesson and feeds nothing else
+func (s *QuizService) validateQuiz(quiz *entities.Quiz) error {
+	if quiz.Title == "" {
+		return ErrTitleRequired
+	}
+	if quiz.TimeLimitMinutes < 0 {
+		return ErrInvalidTimeLimit
+	}
+	if quiz.MaxAttempts < 0 {
+		return ErrInvalidMaxAttempts
+	}
+	if quiz.ScorePolicy != entities.QuizScoreBest && quiz.ScorePolicy != entities.QuizScoreLatest {
+		return ErrInvalidScorePolicy
+	}
+	if quiz.PassPercent < 0 || quiz.PassPercent > 100 {
+		return ErrInvalidPassPercent
+	}
+	if quiz.OpensAt != nil && quiz.ClosesAt != nil && !quiz.ClosesAt.After(*quiz.OpensAt) {
+		return ErrInvalidWindow
+	}
+
+	if quiz.GradeItemID != nil {
+		if _, err := s.gradebookService.GetLessonItem(quiz.LessonID, *quiz.GradeItemID); err != nil {
+			return err
+		}
+
+		linked, err := s.repo.GradeItemLinked(*quiz.GradeItemID, quiz.ID)
+		if err != nil {
+			return err
+		}
+		if linked {
+			return ErrGradeItemLinked
+		}
+	}
+
+	return nil
+}
+
+// applyQuestion validates the request and copies it onto the question, replacing its options
+func applyQuestion(question *entities.QuizQuestion, request *models.QuizQuestionRequest) error {
+	if !questionKinds[request.Kind] {
+		return ErrInvalidKind
+	}
+	prompt := strings.TrimSpace(request.Prompt)
+	if prompt == "" {
+		return ErrPromptRequired
+	}
+	if request.Points <= 0 {
+		return ErrInvalidPoints
+	}
+
+	question.Kind = request.Kind
+	question.Prompt = prompt
+	question.Points = request.Points
+	question.Answer = nil
+	question.Tolerance = 0
+	question.CaseSensitive = false
+	question.Options = nil
+
+	switch request.Kind {
+	case entities.QuestionSingleChoice, entities.QuestionMultipleChoice:
+		if len(request.Options) < 2 {
+			return ErrInvalidOptions
+		}
+		correct := 0
+		for _, option := range request.Options {
+			text := strings.TrimSpace(option.Text)
+			if text == "" {
+				return ErrInvalidOptions
+			}
+			if option.Correct {
+				correct++
+			}
+			question.Options = append(question.Options, entities.QuizOption{Text: text, Correct: option.Correct})
+		}
+		if correct == 0 || (request.Kind == entities.QuestionSingleChoice && correct != 1) {
+			return ErrInvalidCorrectOptions
+		}
+	case entities.QuestionNumeric:
+		if request.Answer == nil || request.Tolerance < 0 {
+			return ErrAnswerRequired
+		}
+		question.Answer = request.Answer
+		question.Tolerance = request.Tolerance
+	case entities.QuestionShortText:
+		for _, accepted := range request.AcceptedAnswers {
+			text := strings.TrimSpace(accepted)
+			if text != "" {
+				question.Options = append(question.Options, entities.QuizOption{Text: text, Correct: true})
+			}
+		}
+		if len(question.Options) == 0 {
+			return ErrAcceptedAnswerRequired
+		}
+		question.CaseSensitive = request.CaseSensitive
+	}
+
+	return nil
+}
+
+// buildResponses checks each answer against its question and turns it into a response of the attempt
+func buildResponses(quiz *entities.Quiz, attempt *entities.QuizAttempt, answers []models.QuizAnswerRequest) ([]entities.QuizResponse, error) {
+	questions := make(map[uint]*entities.QuizQuestion, len(quiz.Questions))
+	for i := range quiz.Questions {
+		questions[quiz.Questions[i].ID] = &quiz.Questions[i]
+	}
+
+	responses := make([]entities.QuizResponse, 0, len(answers))
+	seen := make(map[uint]bool, len(answers))
+	for _, answer := range answers {
+		question, ok := questions[answer.QuestionID]
+		if !ok || seen[answer.QuestionID] {
+			return nil, ErrInvalidAnswer
+		}
+		seen[answer.QuestionID] = true
+
+		response := entities.QuizResponse{
+			LessonID:   attempt.LessonID,
+			AttemptID:  attempt.ID,
+			QuestionID: question.ID,
+		}
+		switch question.Kind {
+		case entities.QuestionSingleChoice, entities.QuestionMultipleChoice:
+			if question.Kind == entities.QuestionSingleChoice && len(answer.OptionIDs) > 1 {
+				return nil, ErrInvalidAnswer
+			}
+			for _, optionID := range answer.OptionIDs {
+				if !hasOption(question, optionID) {
+					return nil, ErrInvalidAnswer
+				}
+			}
+			response.OptionIDs = answer.OptionIDs
+		case entities.QuestionNumeric:
+			response.Number = answer.Number
+		case entities.QuestionShortText:
+			response.Text = strings.TrimSpace(answer.Text)
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func hasOption(question *entities.QuizQuestion, optionID uint) bool {
+	for _, option := range question.Options {
+		if option.ID == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+// attemptDeadline ends the attempt when the time limit runs out or the quiz closes, whichever is first
+func attemptDeadline(quiz *entities.Quiz, startedAt time.Time) *time.Time {
+	var deadline *time.Time
+	if quiz.TimeLimitMinutes > 0 {
+		limit := startedAt.Add(time.Duration(quiz.TimeLimitMinutes) * time.Minute)
+		deadline = &limit
+	}
+	if quiz.ClosesAt != nil && (deadline == nil || quiz.ClosesAt.Before(*deadline)) {
+		closes := *quiz.ClosesAt
+		deadline = &closes
+	}
+	return deadline
+}
+
+// attemptView shows the attempt to the student without revealing the correct answers
+func attemptView(quiz *entities.Quiz, attempt *entities.QuizAttempt) *models.QuizAttemptView {
+	view := &models.QuizAttemptView{
+		Attempt:   *attempt,
+		Questions: make([]models.QuizQuestionView, 0, len(quiz.Questions)),
+	}
+	if view.Attempt.Responses == nil {
+		view.Attempt.Responses = []entities.QuizResponse{}
+	}
+
+	for _, question := range dealQuestions(quiz, attempt) {
+		questionView := models.QuizQuestionView{
+			ID:     question.ID,
+			Kind:   question.Kind,
+			Prompt: question.Prompt,
+			Points: question.Points,
+		}
+		// A short text question's options are its accepted answers
+		if question.Kind != entities.QuestionShortText {
+			for _, option := range question.Options {
+				questionView.Options = append(questionView.Options, models.QuizOptionView{ID: option.ID, Text: option.Text})
+			}
+		}
+		view.Questions = append(view.Questions, questionView)
+	}
+
+	return view
+}
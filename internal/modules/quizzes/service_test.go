@@ -0,0 +1,51 @@
+package quizzes
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/gradebook"
+	"testing"
+)
+
+type linkRepo struct {
+	IQuizRepository
+	linked bool
+}
+
+func (r *linkRepo) GradeItemLinked(itemID uint, quizID uint) (bool, error) {
+	return r.linked, nil
+}
+
+type lessonItems struct {
+	gradebook.IGradebookService
+}
+
+func (g *lessonItems) GetLessonItem(lessonID uint, itemID uint) (*entities.GradeItem, error) {
+	return &entities.GradeItem{ID: itemID, LessonID: lessonID}, nil
+}
+
+func TestValidateQuizGradeItem(t *testing.T) {
+	itemID := uint(7)
+	quiz := entities.Quiz{
+		LessonID:    1,
+		Title:       "Week 1",
+		ScorePolicy: entities.QuizScoreBest,
+		GradeItemID: &itemID,
+	}
+
+	for _, test := range []struct {
+		name   string
+		linked bool
+		want   error
+	}{
+		{"unlinked item", false, nil},
+		{"item linked elsewhere", true, ErrGradeItemLinked},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			service := &QuizService{repo: &linkRepo{linked: test.linked}, gradebookService: &lessonItems{}}
+			if err := service.validateQuiz(&quiz); !errors.Is(err, test.want) {
+				t.Errorf("validateQuiz() = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package roster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+// maxUploadBytes bounds the request body of a roster upload
+const maxUploadBytes = 10 << 20
+
+type RosterHandler struct {
+	service IRosterService
+}
+
+func NewRosterHandler(service IRosterService) *RosterHandler {
+	return &RosterHandler{
+		service: service,
+	}
+}
+
+// Import accepts the CSV either as a multipart "file" field or as a text/csv body.
+// With dry_run=true only the validation report is returned
+func (h *RosterHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "Invalid dry_run value", http.StatusBadRequest)
+			return
+		}
+		dryRun = parsed
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	input, closeInput, status, message := rosterInput(r)
+	if message != "" {
+		http.Error(w, message, status)
+		return
+	}
+	defer closeInput()
+
+	report, err := h.service.Import(input, dryRun)
+	if err != nil && !errors.Is(err, ErrInvalidRoster) {
+		if status := rosterErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to import roster", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while importing roster: ", err)
+		return
+	}
+
+	status = http.StatusOK
+	if errors.Is(err, ErrInvalidRoster) {
+		status = http.StatusUnprocessableEntity
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// rosterInput returns the uploaded CSV along with a function that releases it,
+// or an error status and message
+func rosterInput(r *http.Request) (io.Reader, func(), int, string) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or multipart/form-data"
+	}
+
+	switch mediaType {
+	case "text/csv":
+		return r.Body, func() {}, 0, ""
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxUploadBytes); err != nil {
+			return nil, nil, http.StatusBadRequest, "Invalid multipart upload"
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, nil, http.StatusBadRequest, "Multipart upload must include a file field"
+		}
+		return file, func() {
+			file.Close()
+			r.MultipartForm.RemoveAll()
+		}, 0, ""
+	default:
+		return nil, nil, http.StatusUnsupportedMediaType, "Content-Type must be text/csv or multipart/form-data"
+	}
+}
+
+// rosterErrorStatus maps service errors to HTTP status codes
+func rosterErrorStatus(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidRosterFile),
+		errors.Is(err, ErrTooManyRows):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,118 @@
+package roster
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+)
+
+// rosterEnrollment enrolls the student with the given email, who may be created by the same import.
+// Row is the index of the row in the import report
+type rosterEnrollment struct {
+	Row      int
+	LessonID uint
+	Email    string
+}
+
+// rowError is the roster row whose enrollment failed and rolled the import back
+type rowError struct {
+	row int
+	err error
+}
+
+func (e *rowError) Error() string {
+	return fmt.Sprintf("roster row %d: %v", e.row, e.err)
+}
+
+func (e *rowError) Unwrap() error {
+	return e.err
+}
+
+type IRosterRepository interface {
+	GetStudentsByEmail(emails []string) ([]entities.Student, error)
+	GetLessons(ids []uint) ([]entities.Lesson, error)
+	GetEnrollments(lessonIDs []uint, studentIDs []uint) ([]entities.Enrollment, error)
+	CountActiveEnrollments(lessonIDs []uint) (map[uint]int64, error)
+	ApplyRoster(newStudents []*entities.Student, studentIDs map[string]uint, enrollments []rosterEnrollment) error
+}
+
+type RosterRepository struct{}
+
+func NewRosterRepository() IRosterRepository {
+	return &RosterRepository{}
+}
+
+// GetStudentsByEmail matches emails case-insensitively; callers pass lowercased emails
+func (r *RosterRepository) GetStudentsByEmail(emails []string) ([]entities.Student, error) {
+	var students []entities.Student
+	if len(emails) == 0 {
+		return students, nil
+	}
+	result := common.DB.Where("LOWER(email) IN ?", emails).Find(&students)
+	return students, result.Error
+}
+
+func (r *RosterRepository) GetLessons(ids []uint) ([]entities.Lesson, error) {
+	var lessons []entities.Lesson
+	if len(ids) == 0 {
+		return lessons, nil
+	}
+	result := common.DB.Preload("Prerequisites").Find(&lessons, ids)
+	return lessons, result.Error
+}
+
+// GetEnrollments returns every enrollment of the given students in the given lessons
+func (r *RosterRepository) GetEnrollments(lessonIDs []uint, studentIDs []uint) ([]entities.Enrollment, error) {
+	var enrollments []entities.Enrollment
+	if len(lessonIDs) == 0 || len(studentIDs) == 0 {
+		return enrollments, nil
+	}
+	result := common.DB.Where("lesson_id IN ? AND student_id IN ?", lessonIDs, studentIDs).Find(&enrollments)
+	return enrollments, result.Error
+}
+
+// CountActiveEnrollments returns the number of active enrollments per lesson
+func (r *RosterRepository) CountActiveEnrollments(lessonIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(lessonIDs))
+	if len(lessonIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		LessonID uint
+		Count    int64
+	}
+	result := common.DB.Model(&entities.Enrollment{}).
+		Select("lesson_id, COUNT(*) AS count").
+		Where("lesson_id IN ? AND status = ?", lessonIDs, entities.EnrollmentActive).
+		Group("lesson_id").
+		Scan(&rows)
+	for _, row := range rows {
+		counts[row.LessonID] = row.Count
+	}
+	return counts, result.Error
+}
+
+// ApplyRoster creates the new students and all enrollments in one transaction. Enrollments take
+// the same locked capacity check as any other, so a lesson that filled up since validation fails
+// the import with a rowError
+func (r *RosterRepository) ApplyRoster(newStudents []*entities.Student, studentIDs map[string]uint, enrollments []rosterEnrollment) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		for _, student := range newStudents {
+			if err := tx.Create(student).Error; err != nil {
+				return err
+			}
+			studentIDs[normalizeEmail(student.Email)] = student.ID
+		}
+
+		for _, enrollment := range enrollments {
+			if err := lessons.EnrollInTransaction(tx, enrollment.LessonID, studentIDs[enrollment.Email]); err != nil {
+				return &rowError{row: enrollment.Row, err: err}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,20 @@
+package roster
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *RosterHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Admin-only endpoints
+	adminRoutes := router.PathPrefix("/api/admin/roster").Subrouter()
+	adminRoutes.Use(authMiddleware)
+	adminRoutes.Use(middleware.RequireRole("admin"))
+	adminRoutes.HandleFunc("/import", handler.Import).Methods(http.MethodPost)
+}
@@ -0,0 +1,344 @@
+package roster
+
+import (
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxRosterRows caps the size of a single import
+const MaxRosterRows = 5000
+
+const (
+	ActionCreateAndEnroll = "create_student_and_enroll"
+	ActionEnroll          = "enroll"
+	ActionAlreadyEnrolled = "already_enrolled"
+	ActionInvalid         = "invalid"
+)
+
+// Row error codes reported for invalid rows
+const (
+	RowInvalidEmail       = "invalid_email"
+	RowNameRequired       = "name_required"
+	RowInvalidLessonID    = "invalid_lesson_id"
+	RowUnknownLesson      = "unknown_lesson"
+	RowLessonArchived     = "lesson_archived"
+	RowDuplicate          = "duplicate_row"
+	RowUnmetPrerequisites = "unmet_prerequisites"
+	RowLessonFull         = "lesson_full"
+)
+
+var (
+	ErrInvalidRosterFile = errors.New("invalid roster file")
+	ErrTooManyRows       = fmt.Errorf("roster files are limited to %d rows", MaxRosterRows)
+	ErrInvalidRoster     = errors.New("roster has invalid rows; nothing was imported")
+)
+
+type IRosterService interface {
+	Import(input io.Reader, dryRun bool) (*models.RosterImportReport, error)
+}
+
+type RosterService struct {
+	repo IRosterRepository
+}
+
+func NewRosterService(repo IRosterRepository) IRosterService {
+	return &RosterService{
+		repo: repo,
+	}
+}
+
+type rosterRow struct {
+	line     int
+	email    string
+	name     string
+	lessonID string
+}
+
+// Import validates every row and, unless dryRun is set, applies the whole roster in one
+// transaction. A roster with any invalid row is never applied; the report says why
+func (s *RosterService) Import(input io.Reader, dryRun bool) (*models.RosterImportReport, error) {
+	rows, err := parseRoster(input)
+	if err != nil {
+		return nil, err
+	}
+
+	report, plan, err := s.validate(rows)
+	if err != nil {
+		return nil, err
+	}
+	report.DryRun = dryRun
+
+	if dryRun {
+		return report, nil
+	}
+	if !report.Valid {
+		return report, ErrInvalidRoster
+	}
+
+	err = s.repo.ApplyRoster(plan.newStudents, plan.studentIDs, plan.enrollments)
+	var failed *rowError
+	if errors.As(err, &failed) && errors.Is(err, lessons.ErrLessonFull) {
+		// Another enrollment took the last seat after validation; nothing was imported
+		row := &report.Rows[failed.row]
+		row.Action = ActionInvalid
+		row.Errors = append(row.Errors, RowLessonFull)
+		report.Valid = false
+		report.Summary.InvalidRows++
+		report.Summary.Enrollments--
+		return report, ErrInvalidRoster
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Applied = true
+
+	return report, nil
+}
+
+type rosterPlan struct {
+	newStudents []*entities.Student
+	studentIDs  map[string]uint
+	enrollments []rosterEnrollment
+}
+
+func (s *RosterService) validate(rows []rosterRow) (*models.RosterImportReport, *rosterPlan, error) {
+	emails := make([]string, 0, len(rows))
+	lessonIDs := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		emails = append(emails, row.email)
+		if id, err := strconv.ParseUint(row.lessonID, 10, 64); err == nil {
+			lessonIDs = append(lessonIDs, uint(id))
+		}
+	}
+
+	students, err := s.repo.GetStudentsByEmail(emails)
+	if err != nil {
+		return nil, nil, err
+	}
+	studentIDs := make(map[string]uint, len(students))
+	existingIDs := make([]uint, 0, len(students))
+	for _, student := range students {
+		studentIDs[normalizeEmail(student.Email)] = student.ID
+		existingIDs = append(existingIDs, student.ID)
+	}
+
+	lessons, err := s.repo.GetLessons(lessonIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	lessonsByID := make(map[uint]*entities.Lesson, len(lessons))
+	prerequisiteIDs := make([]uint, 0)
+	for i := range lessons {
+		lessonsByID[lessons[i].ID] = &lessons[i]
+		for _, prerequisite := range lessons[i].Prerequisites {
+			prerequisiteIDs = append(prerequisiteIDs, prerequisite.ID)
+		}
+	}
+
+	// Existing enrollments tell us who is already in a lesson and who completed its prerequisites
+	enrollments, err := s.repo.GetEnrollments(append(lessonIDs, prerequisiteIDs...), existingIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	enrolled := make(map[[2]uint]string, len(enrollments))
+	for _, enrollment := range enrollments {
+		enrolled[[2]uint{enrollment.LessonID, enrollment.StudentID}] = enrollment.Status
+	}
+
+	// Seats taken so far, counting rows earlier in the file
+	seats, err := s.repo.CountActiveEnrollments(lessonIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &models.RosterImportReport{Valid: true, Rows: make([]models.RosterRowResult, 0, len(rows))}
+	plan := &rosterPlan{studentIDs: studentIDs}
+	seen := make(map[string]bool, len(rows))
+	creating := make(map[string]bool)
+
+	for _, row := range rows {
+		result := models.RosterRowResult{Row: row.line, Email: row.email, Name: row.name}
+
+		if !validEmail(row.email) {
+			result.Errors = append(result.Errors, RowInvalidEmail)
+		}
+
+		studentID, exists := studentIDs[row.email]
+		if !exists && row.name == "" && !creating[row.email] {
+			result.Errors = append(result.Errors, RowNameRequired)
+		}
+
+		var lesson *entities.Lesson
+		if id, err := strconv.ParseUint(row.lessonID, 10, 64); err != nil || id == 0 {
+			result.Errors = append(result.Errors, RowInvalidLessonID)
+		} else {
+			result.LessonID = uint(id)
+			lesson = lessonsByID[uint(id)]
+			switch {
+			case lesson == nil:
+				result.Errors = append(result.Errors, RowUnknownLesson)
+			case lesson.Status == entities.LessonArchived:
+				result.Errors = append(result.Errors, RowLessonArchived)
+			}
+		}
+
+		key := row.email + "|" + row.lessonID
+		if seen[key] {
+			result.Errors = append(result.Errors, RowDuplicate)
+		}
+		seen[key] = true
+
+		if lesson != nil {
+			for _, prerequisite := range lesson.Prerequisites {
+				if !exists || enrolled[[2]uint{prerequisite.ID, studentID}] != entities.EnrollmentCompleted {
+					result.Errors = append(result.Errors, RowUnmetPrerequisites)
+					break
+				}
+			}
+		}
+
+		alreadyEnrolled := exists && lesson != nil && enrolled[[2]uint{lesson.ID, studentID}] != ""
+		if len(result.Errors) == 0 && !alreadyEnrolled && lesson.Capacity > 0 {
+			if seats[lesson.ID] >= int64(lesson.Capacity) {
+				result.Errors = append(result.Errors, RowLessonFull)
+			} else {
+				seats[lesson.ID]++
+			}
+		}
+
+		switch {
+		case len(result.Errors) > 0:
+			result.Action = ActionInvalid
+			report.Valid = false
+			report.Summary.InvalidRows++
+		case alreadyEnrolled:
+			result.Action = ActionAlreadyEnrolled
+			report.Summary.AlreadyEnrolled++
+		case exists:
+			result.Action = ActionEnroll
+			report.Summary.Enrollments++
+			plan.enrollments = append(plan.enrollments, rosterEnrollment{Row: len(report.Rows), LessonID: lesson.ID, Email: row.email})
+		default:
+			result.Action = ActionCreateAndEnroll
+			report.Summary.Enrollments++
+			if !creating[row.email] {
+				creating[row.email] = true
+				report.Summary.StudentsToCreate++
+				student, err := newStudent(row.name, row.email)
+				if err != nil {
+					return nil, nil, err
+				}
+				plan.newStudents = append(plan.newStudents, student)
+			}
+			plan.enrollments = append(plan.enrollments, rosterEnrollment{Row: len(report.Rows), LessonID: lesson.ID, Email: row.email})
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+	report.Summary.TotalRows = len(rows)
+
+	return report, plan, nil
+}
+
+// parseRoster reads a CSV with a header row naming email, name and lesson_id in any order
+func parseRoster(input io.Reader) ([]rosterRow, error) {
+	reader := csv.NewReader(input)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidRosterFile)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	for _, required := range []string{"email", "lesson_id"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("%w: missing %s column", ErrInvalidRosterFile, required)
+		}
+	}
+
+	field := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []rosterRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRosterFile, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := rosterRow{
+			line:     line,
+			email:    normalizeEmail(field(record, "email")),
+			name:     field(record, "name"),
+			lessonID: field(record, "lesson_id"),
+		}
+		if row.email == "" && row.name == "" && row.lessonID == "" {
+			continue
+		}
+
+		rows = append(rows, row)
+		if len(rows) > MaxRosterRows {
+			return nil, ErrTooManyRows
+		}
+	}
+
+	return rows, nil
+}
+
+// newStudent creates a student with an unguessable random password, so imported students
+// cannot sign in until a password is set for them. The secret is never used, so the
+// minimum bcrypt cost keeps large imports fast without weakening anything
+func newStudent(name, email string) (*entities.Student, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.MinCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Student{
+		Name:     name,
+		Email:    email,
+		Password: string(hashedPassword),
+		Role:     "student",
+	}, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validEmail accepts a bare address only, rejecting display names like "Ann <ann@example.com>"
+func validEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	return err == nil && address.Address == email
+}
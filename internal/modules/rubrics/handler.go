@@ -0,0 +1,210 @@
+package rubrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type RubricHandler struct {
+	service IRubricService
+}
+
+func NewRubricHandler(service IRubricService) *RubricHandler {
+	return &RubricHandler{
+		service: service,
+	}
+}
+
+func (h *RubricHandler) List(w http.ResponseWriter, r *http.Request) {
+	rubrics, err := h.service.GetRubrics()
+	if err != nil {
+		if status := rubricErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch rubrics", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching rubrics: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rubrics)
+}
+
+func (h *RubricHandler) Get(w http.ResponseWriter, r *http.Request) {
+	rubricID, ok := pathID(w, r, "rubricID", "Invalid rubric ID")
+	if !ok {
+		return
+	}
+
+	rubric, err := h.service.GetRubric(rubricID)
+	if err != nil {
+		if status := rubricErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch rubric", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching rubric: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rubric)
+}
+
+func (h *RubricHandler) Create(w http.ResponseWriter, r *http.Request) {
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.RubricRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rubric, err := h.service.CreateRubric(teacherID, &requestBody)
+	if err != nil {
+		if status := rubricErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create rubric", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating rubric: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rubric)
+}
+
+func (h *RubricHandler) Update(w http.ResponseWriter, r *http.Request) {
+	rubricID, ok := pathID(w, r, "rubricID", "Invalid rubric ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.RubricRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rubric, err := h.service.UpdateRubric(rubricID, teacherID, &requestBody)
+	if err != nil {
+		if status := rubricErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update rubric", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating rubric: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(rubric)
+}
+
+func (h *RubricHandler) Copy(w http.ResponseWriter, r *http.Request) {
+	rubricID, ok := pathID(w, r, "rubricID", "Invalid rubric ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	rubric, err := h.service.CopyRubric(rubricID, teacherID)
+	if err != nil {
+		if status := rubricErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to copy rubric", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while copying rubric: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rubric)
+}
+
+func (h *RubricHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	rubricID, ok := pathID(w, r, "rubricID", "Invalid rubric ID")
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.service.DeleteRubric(rubricID, teacherID); err != nil {
+		if status := rubricErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete rubric", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting rubric: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathID parses a numeric route variable, answering 400 with message when it is invalid
+func pathID(w http.ResponseWriter, r *http.Request, name string, message string) (uint64, bool) {
+	id, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		http.Error(w, message, http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
+// rubricErrorStatus maps service errors to HTTP status codes
+func rubricErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNotRubricOwner):
+		return http.StatusForbidden
+	case errors.Is(err, ErrNameRequired),
+		errors.Is(err, ErrNoCriteria),
+		errors.Is(err, ErrInvalidCriterion),
+		errors.Is(err, ErrInvalidLevel),
+		errors.Is(err, ErrRubricHasNoPoints):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrRubricAssessed),
+		errors.Is(err, ErrRubricInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
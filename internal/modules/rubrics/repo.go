@@ -0,0 +1,117 @@
+package rubrics
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IRubricRepository interface {
+	GetRubrics() ([]entities.Rubric, error)
+	GetRubric(id uint) (entities.Rubric, error)
+	CreateRubric(rubric *entities.Rubric) error
+	UpdateRubric(rubric *entities.Rubric) error
+	DeleteRubric(id uint) error
+	IsRubricAssessed(id uint) (bool, error)
+}
+
+type RubricRepository struct{}
+
+func NewRubricRepository() IRubricRepository {
+	return &RubricRepository{}
+}
+
+func (r *RubricRepository) GetRubrics() ([]entities.Rubric, error) {
+	var rubrics []entities.Rubric
+	result := withCriteria(common.DB).Order("name, id").Find(&rubrics)
+	return rubrics, result.Error
+}
+
+func (r *RubricRepository) GetRubric(id uint) (entities.Rubric, error) {
+	var rubric entities.Rubric
+	result := withCriteria(common.DB).First(&rubric, id)
+	return rubric, result.Error
+}
+
+// CreateRubric stores the rubric together with its criteria and levels
+func (r *RubricRepository) CreateRubric(rubric *entities.Rubric) error {
+	return common.DB.Create(rubric).Error
+}
+
+// UpdateRubric saves the rubric and replaces its criteria and levels in one transaction
+func (r *RubricRepository) UpdateRubric(rubric *entities.Rubric) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Save(rubric)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no rows affected")
+		}
+
+		if err := deleteCriteria(tx, rubric.ID); err != nil {
+			return err
+		}
+		for i := range rubric.Criteria {
+			rubric.Criteria[i].ID = 0
+			rubric.Criteria[i].RubricID = rubric.ID
+			for j := range rubric.Criteria[i].Levels {
+				rubric.Criteria[i].Levels[j].ID = 0
+			}
+		}
+		return tx.Create(&rubric.Criteria).Error
+	})
+}
+
+// DeleteRubric refuses to delete a rubric that is attached to assignments
+func (r *RubricRepository) DeleteRubric(id uint) error {
+	var count int64
+	if err := common.DB.Model(&entities.Assignment{}).Where("rubric_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRubricInUse
+	}
+
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := deleteCriteria(tx, id); err != nil {
+			return err
+		}
+		result := tx.Delete(&entities.Rubric{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// IsRubricAssessed reports whether any student has been marked with the rubric
+func (r *RubricRepository) IsRubricAssessed(id uint) (bool, error) {
+	var count int64
+	result := common.DB.Model(&entities.RubricAssessment{}).Where("rubric_id = ?", id).Count(&count)
+	return count > 0, result.Error
+}
+
+func deleteCriteria(tx *gorm.DB, rubricID uint) error {
+	criteria := tx.Model(&entities.RubricCriterion{}).Select("id").Where("rubric_id = ?", rubricID)
+	if err := tx.Where("criterion_id IN (?)", criteria).Delete(&entities.RubricLevel{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("rubric_id = ?", rubricID).Delete(&entities.RubricCriterion{}).Error
+}
+
+func withCriteria(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Criteria", orderByPosition).
+		Preload("Criteria.Levels", orderByPosition)
+}
+
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
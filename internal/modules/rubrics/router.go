@@ -0,0 +1,25 @@
+package rubrics
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *RubricHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/rubrics").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.List).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("", handler.Create).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/{rubricID:[0-9]+}", handler.Get).Methods(http.MethodGet)
+	teacherRoutes.HandleFunc("/{rubricID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{rubricID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
+	teacherRoutes.HandleFunc("/{rubricID:[0-9]+}/copy", handler.Copy).Methods(http.MethodPost)
+}
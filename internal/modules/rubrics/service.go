@@ -0,0 +1,197 @@
+package rubrics
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/models"
+	"strings"
+)
+
+var (
+	ErrNameRequired      = errors.New("rubric name is required")
+	ErrNoCriteria        = errors.New("a rubric needs at least one criterion")
+	ErrInvalidCriterion  = errors.New("each criterion needs a title and at least one level")
+	ErrInvalidLevel      = errors.New("each level needs a title and points that are not negative")
+	ErrNotRubricOwner    = errors.New("only the teacher who created the rubric can change it")
+	ErrRubricAssessed    = errors.New("rubric has been used to mark students and can no longer be changed")
+	ErrRubricInUse       = errors.New("rubric is attached to assignments")
+	ErrRubricHasNoPoints = errors.New("rubric levels must be worth some points")
+)
+
+type IRubricService interface {
+	GetRubrics() ([]entities.Rubric, error)
+	GetRubric(id uint64) (*entities.Rubric, error)
+	CreateRubric(teacherID uint, request *models.RubricRequest) (*entities.Rubric, error)
+	UpdateRubric(id uint64, teacherID uint, request *models.RubricRequest) (*entities.Rubric, error)
+	CopyRubric(id uint64, teacherID uint) (*entities.Rubric, error)
+	DeleteRubric(id uint64, teacherID uint) error
+}
+
+type RubricService struct {
+	repo IRubricRepository
+}
+
+func NewRubricService(repo IRubricRepository) IRubricService {
+	return &RubricService{
+		repo: repo,
+	}
+}
+
+// GetRubrics lists every teacher's rubrics, so any of them can be reused
+func (s *RubricService) GetRubrics() ([]entities.Rubric, error) {
+	return s.repo.GetRubrics()
+}
+
+func (s *RubricService) GetRubric(id uint64) (*entities.Rubric, error) {
+	rubric, err := s.repo.GetRubric(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &rubric, nil
+}
+
+func (s *RubricService) CreateRubric(teacherID uint, request *models.RubricRequest) (*entities.Rubric, error) {
+	rubric := &entities.Rubric{CreatedBy: teacherID}
+	if err := applyRubric(rubric, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateRubric(rubric); err != nil {
+		return nil, err
+	}
+
+	return rubric, nil
+}
+
+// UpdateRubric replaces the rubric. Once students are marked with it, it is frozen so their
+// scores keep their meaning; a copy can be changed instead
+func (s *RubricService) UpdateRubric(id uint64, teacherID uint, request *models.RubricRequest) (*entities.Rubric, error) {
+	rubric, err := s.ownRubric(id, teacherID)
+	if err != nil {
+		return nil, err
+	}
+
+	assessed, err := s.repo.IsRubricAssessed(rubric.ID)
+	if err != nil {
+		return nil, err
+	}
+	if assessed {
+		return nil, ErrRubricAssessed
+	}
+
+	if err := applyRubric(rubric, request); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateRubric(rubric); err != nil {
+		return nil, err
+	}
+
+	return rubric, nil
+}
+
+// CopyRubric makes the teacher their own copy of any rubric
+func (s *RubricService) CopyRubric(id uint64, teacherID uint) (*entities.Rubric, error) {
+	original, err := s.repo.GetRubric(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	rubric := &entities.Rubric{
+		Name:        original.Name + " (copy)",
+		Description: original.Description,
+		CreatedBy:   teacherID,
+	}
+	for _, criterion := range original.Criteria {
+		copied := entities.RubricCriterion{
+			Title:       criterion.Title,
+			Description: criterion.Description,
+			Position:    criterion.Position,
+		}
+		for _, level := range criterion.Levels {
+			copied.Levels = append(copied.Levels, entities.RubricLevel{
+				Title:       level.Title,
+				Description: level.Description,
+				Points:      level.Points,
+				Position:    level.Position,
+			})
+		}
+		rubric.Criteria = append(rubric.Criteria, copied)
+	}
+
+	if err := s.repo.CreateRubric(rubric); err != nil {
+		return nil, err
+	}
+
+	return rubric, nil
+}
+
+func (s *RubricService) DeleteRubric(id uint64, teacherID uint) error {
+	rubric, err := s.ownRubric(id, teacherID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.DeleteRubric(rubric.ID)
+}
+
+// ownRubric loads a rubric, failing unless the teacher created it
+func (s *RubricService) ownRubric(id uint64, teacherID uint) (*entities.Rubric, error) {
+	rubric, err := s.repo.GetRubric(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if rubric.CreatedBy != teacherID {
+		return nil, ErrNotRubricOwner
+	}
+
+	return &rubric, nil
+}
+
+// applyRubric validates the request and copies it onto the rubric, replacing its criteria
+func applyRubric(rubric *entities.Rubric, request *models.RubricRequest) error {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return ErrNameRequired
+	}
+	if len(request.Criteria) == 0 {
+		return ErrNoCriteria
+	}
+
+	criteria := make([]entities.RubricCriterion, 0, len(request.Criteria))
+	for i, criterionRequest := range request.Criteria {
+		criterion := entities.RubricCriterion{
+			Title:       strings.TrimSpace(criterionRequest.Title),
+			Description: criterionRequest.Description,
+			Position:    i,
+		}
+		if criterion.Title == "" || len(criterionRequest.Levels) == 0 {
+			return ErrInvalidCriterion
+		}
+
+		for j, levelRequest := range criterionRequest.Levels {
+			level := entities.RubricLevel{
+				Title:       strings.TrimSpace(levelRequest.Title),
+				Description: levelRequest.Description,
+				Points:      levelRequest.Points,
+				Position:    j,
+			}
+			if level.Title == "" || level.Points < 0 {
+				return ErrInvalidLevel
+			}
+			criterion.Levels = append(criterion.Levels, level)
+		}
+		criteria = append(criteria, criterion)
+	}
+
+	rubric.Name = name
+	rubric.Description = request.Description
+	rubric.Criteria = criteria
+	if rubric.MaxPoints() <= 0 {
+		return ErrRubricHasNoPoints
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"lesson-management/pkg/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type SessionHandler struct {
+	service ISessionService
+}
+
+func NewSessionHandler(service ISessionService) *SessionHandler {
+	return &SessionHandler{
+		service: service,
+	}
+}
+
+func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	sessions, err := h.service.GetPublishedLessonSessions(lessonID)
+	if err != nil {
+		if status := sessionErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to fetch sessions", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching sessions: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(sessions)
+}
+
+func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	lessonIDStr := mux.Vars(r)["lessonID"]
+	lessonID, err := strconv.ParseUint(lessonIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.CreateLessonSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.CreateSession(lessonID, teacherID, &requestBody)
+	if err != nil {
+		if status := sessionErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create session", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating session: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+func (h *SessionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var requestBody models.PatchLessonSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.service.UpdateSession(lessonID, sessionID, teacherID, &requestBody)
+	if err != nil {
+		if status := sessionErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating session: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(session)
+}
+
+func (h *SessionHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	lessonID, sessionID, ok := sessionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	teacherID, ok := middleware.GetUserID(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	session, err := h.service.CancelSession(lessonID, sessionID, teacherID)
+	if err != nil {
+		if status := sessionErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to cancel session", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while cancelling session: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(session)
+}
+
+// sessionIDs parses the lesson and session IDs from the URL
+func sessionIDs(w http.ResponseWriter, r *http.Request) (uint64, uint64, bool) {
+	vars := mux.Vars(r)
+	lessonID, err := strconv.ParseUint(vars["lessonID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid lesson ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	sessionID, err := strconv.ParseUint(vars["sessionID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+
+	return lessonID, sessionID, true
+}
+
+// sessionErrorStatus maps service errors to HTTP status codes
+func sessionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, lessons.ErrNotLessonTeacher):
+		return http.StatusForbidden
+	case errors.Is(err, ErrInvalidSessionTime):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSessionCancelled),
+		errors.Is(err, lessons.ErrLessonArchived):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
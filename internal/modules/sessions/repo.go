@@ -0,0 +1,60 @@
+package sessions
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+)
+
+type ISessionRepository interface {
+	GetSession(id uint) (entities.LessonSession, error)
+	GetLessonSessions(lessonID uint) ([]*entities.LessonSession, error)
+	GetSessionsForLessons(lessonIDs []uint) ([]*entities.LessonSession, error)
+	CreateSession(session *entities.LessonSession) error
+	UpdateSession(session *entities.LessonSession) error
+}
+
+type SessionRepository struct{}
+
+func NewSessionRepository() ISessionRepository {
+	return &SessionRepository{}
+}
+
+func (r *SessionRepository) GetSession(id uint) (entities.LessonSession, error) {
+	var session entities.LessonSession
+	result := common.DB.First(&session, id)
+	return session, result.Error
+}
+
+func (r *SessionRepository) GetLessonSessions(lessonID uint) ([]*entities.LessonSession, error) {
+	var sessions []*entities.LessonSession
+	result := common.DB.Where("lesson_id = ?", lessonID).Order("starts_at, id").Find(&sessions)
+	return sessions, result.Error
+}
+
+func (r *SessionRepository) GetSessionsForLessons(lessonIDs []uint) ([]*entities.LessonSession, error) {
+	var sessions []*entities.LessonSession
+	if len(lessonIDs) == 0 {
+		return sessions, nil
+	}
+	result := common.DB.Where("lesson_id IN ?", lessonIDs).Order("starts_at, id").Find(&sessions)
+	return sessions, result.Error
+}
+
+func (r *SessionRepository) CreateSession(session *entities.LessonSession) error {
+	return common.DB.Create(session).Error
+}
+
+func (r *SessionRepository) UpdateSession(session *entities.LessonSession) error {
+	result := common.DB.Save(session)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
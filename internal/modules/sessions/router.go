@@ -0,0 +1,25 @@
+package sessions
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *SessionHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Public endpoints (no auth required)
+	router.HandleFunc("/api/lessons/{lessonID:[0-9]+}/sessions", handler.List).Methods(http.MethodGet)
+
+	// Teacher-only endpoints
+	teacherRoutes := router.PathPrefix("/api/lessons/{lessonID:[0-9]+}/sessions").Subrouter()
+	teacherRoutes.Use(authMiddleware)
+	teacherRoutes.Use(middleware.RequireRole("teacher"))
+	teacherRoutes.HandleFunc("", handler.Create).Methods(http.MethodPost)
+	teacherRoutes.HandleFunc("/{sessionID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	teacherRoutes.HandleFunc("/{sessionID:[0-9]+}/cancel", handler.Cancel).Methods(http.MethodPost)
+}
@@ -0,0 +1,148 @@
+package sessions
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSessionTime = errors.New("session must end after it starts")
+	ErrSessionCancelled   = errors.New("session is cancelled")
+)
+
+type ISessionService interface {
+	GetPublishedLessonSessions(lessonID uint64) ([]*entities.LessonSession, error)
+	GetSessionsForLessons(lessonIDs []uint) ([]*entities.LessonSession, error)
+	GetLessonSession(lessonID uint64, sessionID uint64) (*entities.LessonSession, error)
+	CreateSession(lessonID uint64, teacherID uint, request *models.CreateLessonSessionRequest) (*entities.LessonSession, error)
+	UpdateSession(lessonID uint64, sessionID uint64, teacherID uint, request *models.PatchLessonSessionRequest) (*entities.LessonSession, error)
+	CancelSession(lessonID uint64, sessionID uint64, teacherID uint) (*entities.LessonSession, error)
+}
+
+type SessionService struct {
+	repo          ISessionRepository
+	lessonService lessons.ILessonService
+}
+
+func NewSessionService(repo ISessionRepository, lessonService lessons.ILessonService) ISessionService {
+	return &SessionService{
+		repo:          repo,
+		lessonService: lessonService,
+	}
+}
+
+// GetPublishedLessonSessions returns the schedule of a lesson that is visible to the public
+func (s *SessionService) GetPublishedLessonSessions(lessonID uint64) ([]*entities.LessonSession, error) {
+	lesson, err := s.lessonService.GetPublishedLesson(lessonID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetLessonSessions(lesson.ID)
+}
+
+func (s *SessionService) GetSessionsForLessons(lessonIDs []uint) ([]*entities.LessonSession, error) {
+	return s.repo.GetSessionsForLessons(lessonIDs)
+}
+
+// GetLessonSession returns the session, treating a session of another lesson as not found
+func (s *SessionService) GetLessonSession(lessonID uint64, sessionID uint64) (*entities.LessonSession, error) {
+	session, err := s.repo.GetSession(uint(sessionID))
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(session.LessonID) != lessonID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &session, nil
+}
+
+func (s *SessionService) CreateSession(lessonID uint64, teacherID uint, request *models.CreateLessonSessionRequest) (*entities.LessonSession, error) {
+	if _, err := s.lessonService.EditableLesson(lessonID, teacherID); err != nil {
+		return nil, err
+	}
+
+	if !request.EndsAt.After(request.StartsAt) {
+		return nil, ErrInvalidSessionTime
+	}
+
+	session := &entities.LessonSession{
+		LessonID: uint(lessonID),
+		StartsAt: request.StartsAt,
+		EndsAt:   request.EndsAt,
+		Location: request.Location,
+		Status:   entities.SessionScheduled,
+	}
+
+	if err := s.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (s *SessionService) UpdateSession(lessonID uint64, sessionID uint64, teacherID uint, request *models.PatchLessonSessionRequest) (*entities.LessonSession, error) {
+	if _, err := s.lessonService.EditableLesson(lessonID, teacherID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.GetLessonSession(lessonID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status == entities.SessionCancelled {
+		return nil, ErrSessionCancelled
+	}
+
+	if request.StartsAt != nil {
+		session.StartsAt = *request.StartsAt
+	}
+	if request.EndsAt != nil {
+		session.EndsAt = *request.EndsAt
+	}
+	if request.Location != nil {
+		session.Location = *request.Location
+	}
+
+	if !session.EndsAt.After(session.StartsAt) {
+		return nil, ErrInvalidSessionTime
+	}
+
+	session.Sequence++
+	if err := s.repo.UpdateSession(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// CancelSession keeps the session so calendar feeds can publish the cancellation
+func (s *SessionService) CancelSession(lessonID uint64, sessionID uint64, teacherID uint) (*entities.LessonSession, error) {
+	if _, err := s.lessonService.EditableLesson(lessonID, teacherID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.GetLessonSession(lessonID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status == entities.SessionCancelled {
+		return nil, ErrSessionCancelled
+	}
+
+	session.Status = entities.SessionCancelled
+	session.Sequence++
+	if err := s.repo.UpdateSession(session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
@@ -2,12 +2,16 @@ package students
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"lesson-management/models"
+	"lesson-management/pkg/pagination"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
+	"gorm.io/gorm"
 )
 
 type StudentHandler struct {
@@ -48,7 +52,25 @@ func (h *StudentHandler) Get(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *StudentHandler) List(w http.ResponseWriter, r *http.Request) {
-	// Implement logic to get students by lesson
+	page, err := pagination.FromRequest(r, StudentSorts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := StudentFilter{Query: strings.TrimSpace(r.URL.Query().Get("q"))}
+
+	students, pageInfo, err := h.service.GetAllStudents(filter, page)
+	if err != nil {
+		http.Error(w, "Failed to fetch students", http.StatusInternalServerError)
+		fmt.Println("Error while fetching students: ", err)
+		return
+	}
+
+	pagination.WriteHeaders(w, r, page, pageInfo)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(students)
 }
 
 func (h *StudentHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -92,3 +114,60 @@ func (h *StudentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *StudentHandler) LinkGuardian(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.ParseUint(mux.Vars(r)["studentID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid Student ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.LinkGuardianRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.GuardianID == 0 {
+		http.Error(w, "guardian_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.LinkGuardian(studentID, req.GuardianID); err != nil {
+		writeGuardianError(w, err, "Failed to link guardian")
+		fmt.Println("Error while linking guardian: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *StudentHandler) UnlinkGuardian(w http.ResponseWriter, r *http.Request) {
+	studentID, err := strconv.ParseUint(mux.Vars(r)["studentID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid Student ID", http.StatusBadRequest)
+		return
+	}
+
+	guardianID, err := strconv.ParseUint(mux.Vars(r)["guardianID"], 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid Guardian ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.UnlinkGuardian(studentID, uint(guardianID)); err != nil {
+		writeGuardianError(w, err, "Failed to unlink guardian")
+		fmt.Println("Error while unlinking guardian: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeGuardianError reports a missing student or guardian as not found and anything else generically
+func writeGuardianError(w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		http.Error(w, "Student or guardian not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, message, http.StatusInternalServerError)
+}
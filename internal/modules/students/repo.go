@@ -4,14 +4,58 @@ import (
 	"fmt"
 	"lesson-management/entities"
 	"lesson-management/pkg/common"
+	"lesson-management/pkg/pagination"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
+// StudentFilter narrows student lists; Query matches name or email
+type StudentFilter struct {
+	Query string
+}
+
+// StudentSorts lists the sort keys accepted by student list endpoints
+var StudentSorts = pagination.Sorts{
+	ID:      "students.id",
+	Default: "name",
+	Fields: map[string]pagination.SortField{
+		"id":         {Column: "students.id", Type: "bigint"},
+		"name":       {Column: "students.name", Type: "text"},
+		"email":      {Column: "students.email", Type: "text"},
+		"created_at": {Column: "students.created_at", Type: "timestamptz"},
+	},
+}
+
+// StudentCursor returns the cursor for a student under the given sort key
+func StudentCursor(student entities.Student, key string) pagination.Cursor {
+	cursor := pagination.Cursor{ID: student.ID}
+	switch key {
+	case "name":
+		cursor.Value = student.Name
+	case "email":
+		cursor.Value = student.Email
+	case "created_at":
+		cursor.Value = student.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		cursor.Value = strconv.FormatUint(uint64(student.ID), 10)
+	}
+	return cursor
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type IStudentRepository interface {
 	GetStudentByID(id uint) (entities.Student, error)
-	GetAllStudents() ([]entities.Student, error)
+	GetAllStudents(filter StudentFilter, page pagination.Params) ([]entities.Student, pagination.Page, error)
 	CreateStudent(student *entities.Student) error
 	DeleteStudent(id uint) error
 	UpdateStudent(student *entities.Student) error
+	GetGuardian(id uint) (entities.Guardian, error)
+	LinkGuardian(guardian *entities.Guardian, student *entities.Student) error
+	UnlinkGuardian(guardian *entities.Guardian, student *entities.Student) error
 }
 type StudentRepository struct{}
 
@@ -25,10 +69,28 @@ func (r *StudentRepository) GetStudentByID(id uint) (entities.Student, error) {
 	return student, result.Error
 }
 
-func (r *StudentRepository) GetAllStudents() ([]entities.Student, error) {
+func (r *StudentRepository) GetAllStudents(filter StudentFilter, page pagination.Params) ([]entities.Student, pagination.Page, error) {
+	query := common.DB.Model(&entities.Student{})
+	if filter.Query != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Query) + "%"
+		query = query.Where("students.name ILIKE ? OR students.email ILIKE ?", pattern, pattern)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
 	var students []entities.Student
-	result := common.DB.Find(&students)
-	return students, result.Error
+	if err := page.Apply(query).Find(&students).Error; err != nil {
+		return nil, pagination.Page{}, err
+	}
+
+	students, next := pagination.Trim(students, page, func(student entities.Student) pagination.Cursor {
+		return StudentCursor(student, page.Key)
+	})
+	return students, pagination.Page{Total: total, NextCursor: next}, nil
 }
 
 func (r *StudentRepository) CreateStudent(student *entities.Student) error {
@@ -51,3 +113,17 @@ func (r *StudentRepository) UpdateStudent(student *entities.Student) error {
 
 	return nil
 }
+
+func (r *StudentRepository) GetGuardian(id uint) (entities.Guardian, error) {
+	var guardian entities.Guardian
+	result := common.DB.First(&guardian, id)
+	return guardian, result.Error
+}
+
+func (r *StudentRepository) LinkGuardian(guardian *entities.Guardian, student *entities.Student) error {
+	return common.DB.Model(guardian).Association("Students").Append(student)
+}
+
+func (r *StudentRepository) UnlinkGuardian(guardian *entities.Guardian, student *entities.Student) error {
+	return common.DB.Model(guardian).Association("Students").Delete(student)
+}
@@ -2,6 +2,7 @@ package students
 
 import (
 	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -13,4 +14,11 @@ func InitRoutes(router *mux.Router, handler *StudentHandler, authService auth.IA
 	router.HandleFunc("/api/students", handler.List).Methods(http.MethodGet)
 	router.HandleFunc("/api/students", handler.Create).Methods(http.MethodPost)
 	router.HandleFunc("/api/students/{studentID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+
+	// Admin-only guardian links
+	adminRoutes := router.PathPrefix("/api/admin/students/{studentID:[0-9]+}/guardians").Subrouter()
+	adminRoutes.Use(middleware.AuthMiddleware(authService))
+	adminRoutes.Use(middleware.RequireRole("admin"))
+	adminRoutes.HandleFunc("", handler.LinkGuardian).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{guardianID:[0-9]+}", handler.UnlinkGuardian).Methods(http.MethodDelete)
 }
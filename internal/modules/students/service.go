@@ -3,13 +3,16 @@ package students
 import (
 	"lesson-management/entities"
 	"lesson-management/models"
+	"lesson-management/pkg/pagination"
 )
 
 type IStudentService interface {
 	GetStudentByID(id uint64) (*entities.Student, error)
-	GetAllStudents() ([]entities.Student, error)
+	GetAllStudents(filter StudentFilter, page pagination.Params) ([]entities.Student, pagination.Page, error)
 	CreateStudent(student *models.CreateStudentRequest) (*entities.Student, error)
 	UpdateStudent(student *models.PatchStudentRequest, id uint64) (*entities.Student, error)
+	LinkGuardian(studentID uint64, guardianID uint) error
+	UnlinkGuardian(studentID uint64, guardianID uint) error
 }
 
 type StudentService struct {
@@ -31,13 +34,8 @@ func (s *StudentService) GetStudentByID(id uint64) (*entities.Student, error) {
 	return &student, nil
 }
 
-func (s *StudentService) GetAllStudents() ([]entities.Student, error) {
-	students, err := s.repo.GetAllStudents()
-	if err != nil {
-		return nil, err
-	}
-
-	return students, nil
+func (s *StudentService) GetAllStudents(filter StudentFilter, page pagination.Params) ([]entities.Student, pagination.Page, error) {
+	return s.repo.GetAllStudents(filter, page)
 }
 
 func (s *StudentService) CreateStudent(request *models.CreateStudentRequest) (*entities.Student, error) {
@@ -74,3 +72,36 @@ func (s *StudentService) UpdateStudent(request *models.PatchStudentRequest, id u
 
 	return &student, nil
 }
+
+// LinkGuardian gives the guardian read access to the student's records; linking twice is a no-op
+func (s *StudentService) LinkGuardian(studentID uint64, guardianID uint) error {
+	guardian, student, err := s.guardianAndStudent(studentID, guardianID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.LinkGuardian(guardian, student)
+}
+
+func (s *StudentService) UnlinkGuardian(studentID uint64, guardianID uint) error {
+	guardian, student, err := s.guardianAndStudent(studentID, guardianID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UnlinkGuardian(guardian, student)
+}
+
+func (s *StudentService) guardianAndStudent(studentID uint64, guardianID uint) (*entities.Guardian, *entities.Student, error) {
+	student, err := s.repo.GetStudentByID(uint(studentID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	guardian, err := s.repo.GetGuardian(guardianID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &guardian, &student, nil
+}
@@ -0,0 +1,246 @@
+package subjects
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type SubjectHandler struct {
+	service ISubjectService
+}
+
+func NewSubjectHandler(service ISubjectService) *SubjectHandler {
+	return &SubjectHandler{
+		service: service,
+	}
+}
+
+// Subject handlers
+func (h *SubjectHandler) ListSubjects(w http.ResponseWriter, r *http.Request) {
+	subjects, err := h.service.GetSubjectTree()
+	if err != nil {
+		http.Error(w, "Failed to fetch subjects", http.StatusInternalServerError)
+		fmt.Println("Error while fetching subjects: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(subjects)
+}
+
+func (h *SubjectHandler) GetSubject(w http.ResponseWriter, r *http.Request) {
+	subjectIDStr := mux.Vars(r)["subjectID"]
+	subjectID, err := strconv.ParseUint(subjectIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid subject ID", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.GetSubject(subjectID)
+	if err != nil {
+		http.Error(w, "Subject not found", http.StatusNotFound)
+		fmt.Println("Error while fetching subject: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(subject)
+}
+
+func (h *SubjectHandler) CreateSubject(w http.ResponseWriter, r *http.Request) {
+	var requestBody models.CreateSubjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(requestBody.Name) == "" {
+		http.Error(w, "Subject name is required", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.CreateSubject(&requestBody)
+	if err != nil {
+		if status := subjectErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create subject", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating subject: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subject)
+}
+
+func (h *SubjectHandler) UpdateSubject(w http.ResponseWriter, r *http.Request) {
+	subjectIDStr := mux.Vars(r)["subjectID"]
+	subjectID, err := strconv.ParseUint(subjectIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid subject ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.PatchSubjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Name != nil && strings.TrimSpace(*requestBody.Name) == "" {
+		http.Error(w, "Subject name is required", http.StatusBadRequest)
+		return
+	}
+
+	subject, err := h.service.UpdateSubject(&requestBody, subjectID)
+	if err != nil {
+		if status := subjectErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update subject", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating subject: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(subject)
+}
+
+func (h *SubjectHandler) DeleteSubject(w http.ResponseWriter, r *http.Request) {
+	subjectIDStr := mux.Vars(r)["subjectID"]
+	subjectID, err := strconv.ParseUint(subjectIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid subject ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteSubject(subjectID); err != nil {
+		if status := subjectErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete subject", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting subject: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Tag handlers
+func (h *SubjectHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.GetTags()
+	if err != nil {
+		http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+		fmt.Println("Error while fetching tags: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tags)
+}
+
+func (h *SubjectHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	var requestBody models.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := h.service.CreateTag(&requestBody)
+	if err != nil {
+		if status := subjectErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create tag", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating tag: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(tag)
+}
+
+func (h *SubjectHandler) UpdateTag(w http.ResponseWriter, r *http.Request) {
+	tagIDStr := mux.Vars(r)["tagID"]
+	tagID, err := strconv.ParseUint(tagIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.TagRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tag, err := h.service.UpdateTag(&requestBody, tagID)
+	if err != nil {
+		if status := subjectErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update tag", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating tag: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tag)
+}
+
+func (h *SubjectHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	tagIDStr := mux.Vars(r)["tagID"]
+	tagID, err := strconv.ParseUint(tagIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid tag ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTag(tagID); err != nil {
+		if status := subjectErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete tag", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting tag: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// subjectErrorStatus maps service errors to HTTP status codes
+func subjectErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrTagNameRequired):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrSubjectCycle),
+		errors.Is(err, ErrSubjectHasChildren),
+		errors.Is(err, ErrSubjectInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
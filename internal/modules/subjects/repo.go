@@ -0,0 +1,133 @@
+package subjects
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ISubjectRepository interface {
+	GetSubjects() ([]*entities.Subject, error)
+	GetSubject(id uint) (entities.Subject, error)
+	GetSubtreeIDs(id uint) ([]uint, error)
+	CreateSubject(subject *entities.Subject) error
+	UpdateSubject(subject *entities.Subject) error
+	DeleteSubject(id uint) error
+	GetTags() ([]*entities.Tag, error)
+	GetTag(id uint) (entities.Tag, error)
+	CreateTag(tag *entities.Tag) error
+	UpdateTag(tag *entities.Tag) error
+	DeleteTag(id uint) error
+}
+
+type SubjectRepository struct{}
+
+func NewSubjectRepository() ISubjectRepository {
+	return &SubjectRepository{}
+}
+
+func (r *SubjectRepository) GetSubjects() ([]*entities.Subject, error) {
+	var subjects []*entities.Subject
+	result := common.DB.Order("name").Find(&subjects)
+	return subjects, result.Error
+}
+
+func (r *SubjectRepository) GetSubject(id uint) (entities.Subject, error) {
+	var subject entities.Subject
+	result := common.DB.
+		Preload("Children", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		First(&subject, id)
+	return subject, result.Error
+}
+
+// GetSubtreeIDs returns the subject and all of its descendants
+func (r *SubjectRepository) GetSubtreeIDs(id uint) ([]uint, error) {
+	var ids []uint
+	result := common.DB.Raw(`WITH RECURSIVE subtree AS (
+	SELECT id FROM subjects WHERE id = ?
+	UNION ALL
+	SELECT subjects.id FROM subjects JOIN subtree ON subjects.parent_id = subtree.id
+)
+SELECT id FROM subtree`, id).Scan(&ids)
+	return ids, result.Error
+}
+
+func (r *SubjectRepository) CreateSubject(subject *entities.Subject) error {
+	return common.DB.Omit(clause.Associations).Create(subject).Error
+}
+
+func (r *SubjectRepository) UpdateSubject(subject *entities.Subject) error {
+	result := common.DB.Omit(clause.Associations).Save(subject)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+func (r *SubjectRepository) DeleteSubject(id uint) error {
+	var count int64
+	if err := common.DB.Model(&entities.Subject{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSubjectHasChildren
+	}
+
+	if err := common.DB.Unscoped().Model(&entities.Lesson{}).Where("subject_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSubjectInUse
+	}
+
+	return common.DB.Delete(&entities.Subject{}, id).Error
+}
+
+func (r *SubjectRepository) GetTags() ([]*entities.Tag, error) {
+	var tags []*entities.Tag
+	result := common.DB.Order("name").Find(&tags)
+	return tags, result.Error
+}
+
+func (r *SubjectRepository) GetTag(id uint) (entities.Tag, error) {
+	var tag entities.Tag
+	result := common.DB.First(&tag, id)
+	return tag, result.Error
+}
+
+func (r *SubjectRepository) CreateTag(tag *entities.Tag) error {
+	return common.DB.Create(tag).Error
+}
+
+func (r *SubjectRepository) UpdateTag(tag *entities.Tag) error {
+	result := common.DB.Save(tag)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteTag removes the tag from every lesson before deleting it
+func (r *SubjectRepository) DeleteTag(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM lesson_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entities.Tag{}, id).Error
+	})
+}
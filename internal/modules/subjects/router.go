@@ -0,0 +1,34 @@
+package subjects
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *SubjectHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Public endpoints (no auth required)
+	router.HandleFunc("/api/subjects", handler.ListSubjects).Methods(http.MethodGet)
+	router.HandleFunc("/api/subjects/{subjectID:[0-9]+}", handler.GetSubject).Methods(http.MethodGet)
+	router.HandleFunc("/api/tags", handler.ListTags).Methods(http.MethodGet)
+
+	// Admin-only endpoints
+	adminSubjectRoutes := router.PathPrefix("/api/subjects").Subrouter()
+	adminSubjectRoutes.Use(authMiddleware)
+	adminSubjectRoutes.Use(middleware.RequireRole("admin"))
+	adminSubjectRoutes.HandleFunc("", handler.CreateSubject).Methods(http.MethodPost)
+	adminSubjectRoutes.HandleFunc("/{subjectID:[0-9]+}", handler.UpdateSubject).Methods(http.MethodPut)
+	adminSubjectRoutes.HandleFunc("/{subjectID:[0-9]+}", handler.DeleteSubject).Methods(http.MethodDelete)
+
+	adminTagRoutes := router.PathPrefix("/api/tags").Subrouter()
+	adminTagRoutes.Use(authMiddleware)
+	adminTagRoutes.Use(middleware.RequireRole("admin"))
+	adminTagRoutes.HandleFunc("", handler.CreateTag).Methods(http.MethodPost)
+	adminTagRoutes.HandleFunc("/{tagID:[0-9]+}", handler.UpdateTag).Methods(http.MethodPut)
+	adminTagRoutes.HandleFunc("/{tagID:[0-9]+}", handler.DeleteTag).Methods(http.MethodDelete)
+}
@@ -0,0 +1,187 @@
+package subjects
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/models"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrSubjectCycle       = errors.New("a subject cannot be moved under itself or its descendants")
+	ErrSubjectHasChildren = errors.New("subject still has child subjects")
+	ErrSubjectInUse       = errors.New("subject is still assigned to lessons")
+	ErrTagNameRequired    = errors.New("tag name is required")
+)
+
+type ISubjectService interface {
+	GetSubjectTree() ([]*entities.Subject, error)
+	GetSubject(id uint64) (*entities.Subject, error)
+	CreateSubject(request *models.CreateSubjectRequest) (*entities.Subject, error)
+	UpdateSubject(request *models.PatchSubjectRequest, id uint64) (*entities.Subject, error)
+	DeleteSubject(id uint64) error
+	GetTags() ([]*entities.Tag, error)
+	CreateTag(request *models.TagRequest) (*entities.Tag, error)
+	UpdateTag(request *models.TagRequest, id uint64) (*entities.Tag, error)
+	DeleteTag(id uint64) error
+}
+
+type SubjectService struct {
+	repo ISubjectRepository
+}
+
+func NewSubjectService(repo ISubjectRepository) ISubjectService {
+	return &SubjectService{
+		repo: repo,
+	}
+}
+
+// GetSubjectTree returns the root subjects with their descendants nested under Children
+func (s *SubjectService) GetSubjectTree() ([]*entities.Subject, error) {
+	subjects, err := s.repo.GetSubjects()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[uint][]*entities.Subject)
+	var roots []*entities.Subject
+	for _, subject := range subjects {
+		if subject.ParentID == nil {
+			roots = append(roots, subject)
+			continue
+		}
+		children[*subject.ParentID] = append(children[*subject.ParentID], subject)
+	}
+
+	var attach func(subject *entities.Subject) entities.Subject
+	attach = func(subject *entities.Subject) entities.Subject {
+		node := *subject
+		for _, child := range children[subject.ID] {
+			node.Children = append(node.Children, attach(child))
+		}
+		return node
+	}
+
+	tree := make([]*entities.Subject, 0, len(roots))
+	for _, root := range roots {
+		node := attach(root)
+		tree = append(tree, &node)
+	}
+	return tree, nil
+}
+
+func (s *SubjectService) GetSubject(id uint64) (*entities.Subject, error) {
+	subject, err := s.repo.GetSubject(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &subject, nil
+}
+
+func (s *SubjectService) CreateSubject(request *models.CreateSubjectRequest) (*entities.Subject, error) {
+	if request.ParentID != nil {
+		if _, err := s.repo.GetSubject(*request.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	subject := &entities.Subject{
+		Name:     strings.TrimSpace(request.Name),
+		ParentID: request.ParentID,
+	}
+
+	if err := s.repo.CreateSubject(subject); err != nil {
+		return nil, err
+	}
+
+	return subject, nil
+}
+
+func (s *SubjectService) UpdateSubject(request *models.PatchSubjectRequest, id uint64) (*entities.Subject, error) {
+	subject, err := s.repo.GetSubject(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		subject.Name = strings.TrimSpace(*request.Name)
+	}
+
+	if request.MakeRoot {
+		subject.ParentID = nil
+	} else if request.ParentID != nil {
+		if _, err := s.repo.GetSubject(*request.ParentID); err != nil {
+			return nil, err
+		}
+
+		subtree, err := s.repo.GetSubtreeIDs(subject.ID)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(subtree, *request.ParentID) {
+			return nil, ErrSubjectCycle
+		}
+		subject.ParentID = request.ParentID
+	}
+
+	if err := s.repo.UpdateSubject(&subject); err != nil {
+		return nil, err
+	}
+
+	return &subject, nil
+}
+
+func (s *SubjectService) DeleteSubject(id uint64) error {
+	if _, err := s.repo.GetSubject(uint(id)); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteSubject(uint(id))
+}
+
+func (s *SubjectService) GetTags() ([]*entities.Tag, error) {
+	return s.repo.GetTags()
+}
+
+func (s *SubjectService) CreateTag(request *models.TagRequest) (*entities.Tag, error) {
+	name := entities.NormalizeTagName(request.Name)
+	if name == "" {
+		return nil, ErrTagNameRequired
+	}
+
+	tag := &entities.Tag{Name: name}
+	if err := s.repo.CreateTag(tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+func (s *SubjectService) UpdateTag(request *models.TagRequest, id uint64) (*entities.Tag, error) {
+	name := entities.NormalizeTagName(request.Name)
+	if name == "" {
+		return nil, ErrTagNameRequired
+	}
+
+	tag, err := s.repo.GetTag(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	tag.Name = name
+	if err := s.repo.UpdateTag(&tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (s *SubjectService) DeleteTag(id uint64) error {
+	if _, err := s.repo.GetTag(uint(id)); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteTag(uint(id))
+}
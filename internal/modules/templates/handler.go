@@ -0,0 +1,175 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type TemplateHandler struct {
+	service ITemplateService
+}
+
+func NewTemplateHandler(service ITemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		service: service,
+	}
+}
+
+func (h *TemplateHandler) List(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.GetAllTemplates()
+	if err != nil {
+		http.Error(w, "Failed to fetch lesson templates", http.StatusInternalServerError)
+		fmt.Println("Error while fetching lesson templates: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(templates)
+}
+
+func (h *TemplateHandler) Get(w http.ResponseWriter, r *http.Request) {
+	templateIDStr := mux.Vars(r)["templateID"]
+	templateID, err := strconv.ParseUint(templateIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.service.GetTemplate(templateID)
+	if err != nil {
+		http.Error(w, "Lesson template not found", http.StatusNotFound)
+		fmt.Println("Error while fetching lesson template: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplateHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var requestBody models.CreateLessonTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Name == "" || requestBody.Title == "" {
+		http.Error(w, "Template name and title are required", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.service.CreateTemplate(&requestBody)
+	if err != nil {
+		if status := templateErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create lesson template", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating lesson template: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplateHandler) Update(w http.ResponseWriter, r *http.Request) {
+	templateIDStr := mux.Vars(r)["templateID"]
+	templateID, err := strconv.ParseUint(templateIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.PatchLessonTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(&requestBody, templateID)
+	if err != nil {
+		if status := templateErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update lesson template", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating lesson template: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplateHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	templateIDStr := mux.Vars(r)["templateID"]
+	templateID, err := strconv.ParseUint(templateIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTemplate(templateID); err != nil {
+		http.Error(w, "Failed to delete lesson template", http.StatusInternalServerError)
+		fmt.Println("Error while deleting lesson template: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *TemplateHandler) Instantiate(w http.ResponseWriter, r *http.Request) {
+	templateIDStr := mux.Vars(r)["templateID"]
+	templateID, err := strconv.ParseUint(templateIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid template ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.InstantiateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lessons, err := h.service.Instantiate(templateID, &requestBody)
+	if err != nil {
+		if status := templateErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to instantiate lesson template", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while instantiating lesson template: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(lessons)
+}
+
+// templateErrorStatus maps service errors to HTTP status codes
+func templateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNoInstances),
+		errors.Is(err, ErrTooManyInstances),
+		errors.Is(err, ErrTeacherIDRequired):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
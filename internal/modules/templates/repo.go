@@ -0,0 +1,88 @@
+package templates
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ITemplateRepository interface {
+	GetTemplate(id uint) (entities.LessonTemplate, error)
+	GetAllTemplates() ([]*entities.LessonTemplate, error)
+	CreateTemplate(template *entities.LessonTemplate, prerequisiteIDs []uint) error
+	UpdateTemplate(template *entities.LessonTemplate, prerequisiteIDs *[]uint) error
+	DeleteTemplate(id uint) error
+}
+
+type TemplateRepository struct{}
+
+func NewTemplateRepository() ITemplateRepository {
+	return &TemplateRepository{}
+}
+
+func (r *TemplateRepository) GetTemplate(id uint) (entities.LessonTemplate, error) {
+	var template entities.LessonTemplate
+	result := common.DB.Preload("Prerequisites").First(&template, id)
+	return template, result.Error
+}
+
+func (r *TemplateRepository) GetAllTemplates() ([]*entities.LessonTemplate, error) {
+	var templates []*entities.LessonTemplate
+	result := common.DB.Preload("Prerequisites").Order("name").Find(&templates)
+	return templates, result.Error
+}
+
+func (r *TemplateRepository) CreateTemplate(template *entities.LessonTemplate, prerequisiteIDs []uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(template).Error; err != nil {
+			return err
+		}
+		return replacePrerequisites(tx, template, prerequisiteIDs)
+	})
+}
+
+// UpdateTemplate saves the template; prerequisites are only replaced when prerequisiteIDs is set
+func (r *TemplateRepository) UpdateTemplate(template *entities.LessonTemplate, prerequisiteIDs *[]uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Save(template)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("no rows affected")
+		}
+
+		if prerequisiteIDs == nil {
+			return nil
+		}
+		return replacePrerequisites(tx, template, *prerequisiteIDs)
+	})
+}
+
+func (r *TemplateRepository) DeleteTemplate(id uint) error {
+	return common.DB.Transaction(func(tx *gorm.DB) error {
+		template := &entities.LessonTemplate{ID: id}
+		if err := tx.Model(template).Association("Prerequisites").Clear(); err != nil {
+			return err
+		}
+		return tx.Delete(template).Error
+	})
+}
+
+func replacePrerequisites(tx *gorm.DB, template *entities.LessonTemplate, prerequisiteIDs []uint) error {
+	prerequisites := make([]entities.Lesson, 0, len(prerequisiteIDs))
+	if len(prerequisiteIDs) > 0 {
+		if err := tx.Find(&prerequisites, prerequisiteIDs).Error; err != nil {
+			return err
+		}
+		if len(prerequisites) != len(prerequisiteIDs) {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	template.Prerequisites = prerequisites
+	return tx.Model(template).Association("Prerequisites").Replace(prerequisites)
+}
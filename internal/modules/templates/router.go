@@ -0,0 +1,25 @@
+package templates
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *TemplateHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Admin-only endpoints
+	adminRoutes := router.PathPrefix("/api/lesson-templates").Subrouter()
+	adminRoutes.Use(authMiddleware)
+	adminRoutes.Use(middleware.RequireRole("admin"))
+	adminRoutes.HandleFunc("", handler.List).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("", handler.Create).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/{templateID:[0-9]+}", handler.Get).Methods(http.MethodGet)
+	adminRoutes.HandleFunc("/{templateID:[0-9]+}", handler.Update).Methods(http.MethodPut)
+	adminRoutes.HandleFunc("/{templateID:[0-9]+}", handler.Delete).Methods(http.MethodDelete)
+	adminRoutes.HandleFunc("/{templateID:[0-9]+}/instantiate", handler.Instantiate).Methods(http.MethodPost)
+}
@@ -0,0 +1,163 @@
+package templates
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+)
+
+// MaxInstancesPerRequest caps how many lessons one instantiate call may create
+const MaxInstancesPerRequest = 100
+
+var (
+	ErrNoInstances       = errors.New("at least one lesson is required")
+	ErrTooManyInstances  = errors.New("too many lessons in one request")
+	ErrTeacherIDRequired = errors.New("teacher_id is required for every lesson")
+)
+
+type ITemplateService interface {
+	GetTemplate(id uint64) (*entities.LessonTemplate, error)
+	GetAllTemplates() ([]*entities.LessonTemplate, error)
+	CreateTemplate(request *models.CreateLessonTemplateRequest) (*entities.LessonTemplate, error)
+	UpdateTemplate(request *models.PatchLessonTemplateRequest, id uint64) (*entities.LessonTemplate, error)
+	DeleteTemplate(id uint64) error
+	Instantiate(id uint64, request *models.InstantiateTemplateRequest) ([]*entities.Lesson, error)
+}
+
+type TemplateService struct {
+	repo          ITemplateRepository
+	lessonService lessons.ILessonService
+}
+
+func NewTemplateService(repo ITemplateRepository, lessonService lessons.ILessonService) ITemplateService {
+	return &TemplateService{
+		repo:          repo,
+		lessonService: lessonService,
+	}
+}
+
+func (s *TemplateService) GetTemplate(id uint64) (*entities.LessonTemplate, error) {
+	template, err := s.repo.GetTemplate(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (s *TemplateService) GetAllTemplates() ([]*entities.LessonTemplate, error) {
+	return s.repo.GetAllTemplates()
+}
+
+func (s *TemplateService) CreateTemplate(request *models.CreateLessonTemplateRequest) (*entities.LessonTemplate, error) {
+	template := &entities.LessonTemplate{
+		Name:             request.Name,
+		Title:            request.Title,
+		Description:      request.Description,
+		SelfEnrollment:   request.SelfEnrollment,
+		RequiresApproval: request.RequiresApproval,
+	}
+
+	if err := s.repo.CreateTemplate(template, request.PrerequisiteIDs); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *TemplateService) UpdateTemplate(request *models.PatchLessonTemplateRequest, id uint64) (*entities.LessonTemplate, error) {
+	template, err := s.repo.GetTemplate(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		template.Name = *request.Name
+	}
+	if request.Title != nil {
+		template.Title = *request.Title
+	}
+	if request.Description != nil {
+		template.Description = *request.Description
+	}
+	if request.SelfEnrollment != nil {
+		template.SelfEnrollment = *request.SelfEnrollment
+	}
+	if request.RequiresApproval != nil {
+		template.RequiresApproval = *request.RequiresApproval
+	}
+
+	if err := s.repo.UpdateTemplate(&template, request.PrerequisiteIDs); err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+func (s *TemplateService) DeleteTemplate(id uint64) error {
+	return s.repo.DeleteTemplate(uint(id))
+}
+
+// Instantiate creates one draft lesson per instance, all or nothing
+func (s *TemplateService) Instantiate(id uint64, request *models.InstantiateTemplateRequest) ([]*entities.Lesson, error) {
+	if len(request.Lessons) == 0 {
+		return nil, ErrNoInstances
+	}
+	if len(request.Lessons) > MaxInstancesPerRequest {
+		return nil, ErrTooManyInstances
+	}
+
+	template, err := s.repo.GetTemplate(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instance := range request.Lessons {
+		if instance.TeacherID == 0 {
+			return nil, ErrTeacherIDRequired
+		}
+	}
+
+	prerequisiteIDs := make([]uint, 0, len(template.Prerequisites))
+	for _, prerequisite := range template.Prerequisites {
+		prerequisiteIDs = append(prerequisiteIDs, prerequisite.ID)
+	}
+
+	// Lessons are created the way the lessons module creates them, so an unknown teacher
+	// rolls back every lesson of the request with gorm.ErrRecordNotFound
+	created := make([]*entities.Lesson, 0, len(request.Lessons))
+	err = s.lessonService.Transaction(func(lessonService lessons.ILessonService) error {
+		for _, instance := range request.Lessons {
+			lessonRequest := &models.CreateLessonRequest{
+				Title:            template.Title,
+				Description:      template.Description,
+				TermID:           instance.TermID,
+				SelfEnrollment:   template.SelfEnrollment,
+				RequiresApproval: template.RequiresApproval,
+			}
+			if instance.Title != "" {
+				lessonRequest.Title = instance.Title
+			}
+
+			lesson, err := lessonService.CreateLesson(lessonRequest, instance.TeacherID)
+			if err != nil {
+				return err
+			}
+
+			if len(prerequisiteIDs) > 0 {
+				if lesson, err = lessonService.SetPrerequisites(uint64(lesson.ID), prerequisiteIDs); err != nil {
+					return err
+				}
+			}
+
+			created = append(created, lesson)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
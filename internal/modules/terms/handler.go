@@ -0,0 +1,307 @@
+package terms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lesson-management/models"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"gorm.io/gorm"
+)
+
+type TermHandler struct {
+	service ITermService
+}
+
+func NewTermHandler(service ITermService) *TermHandler {
+	return &TermHandler{
+		service: service,
+	}
+}
+
+// Academic year handlers
+func (h *TermHandler) ListAcademicYears(w http.ResponseWriter, r *http.Request) {
+	years, err := h.service.GetAcademicYears()
+	if err != nil {
+		http.Error(w, "Failed to fetch academic years", http.StatusInternalServerError)
+		fmt.Println("Error while fetching academic years: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(years)
+}
+
+func (h *TermHandler) GetAcademicYear(w http.ResponseWriter, r *http.Request) {
+	yearIDStr := mux.Vars(r)["yearID"]
+	yearID, err := strconv.ParseUint(yearIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid academic year ID", http.StatusBadRequest)
+		return
+	}
+
+	year, err := h.service.GetAcademicYear(yearID)
+	if err != nil {
+		http.Error(w, "Academic year not found", http.StatusNotFound)
+		fmt.Println("Error while fetching academic year: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(year)
+}
+
+func (h *TermHandler) CreateAcademicYear(w http.ResponseWriter, r *http.Request) {
+	var requestBody models.CreateAcademicYearRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Name == "" {
+		http.Error(w, "Academic year name is required", http.StatusBadRequest)
+		return
+	}
+
+	year, err := h.service.CreateAcademicYear(&requestBody)
+	if err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create academic year", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating academic year: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(year)
+}
+
+func (h *TermHandler) UpdateAcademicYear(w http.ResponseWriter, r *http.Request) {
+	yearIDStr := mux.Vars(r)["yearID"]
+	yearID, err := strconv.ParseUint(yearIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid academic year ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.PatchAcademicYearRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	year, err := h.service.UpdateAcademicYear(&requestBody, yearID)
+	if err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update academic year", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating academic year: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(year)
+}
+
+func (h *TermHandler) DeleteAcademicYear(w http.ResponseWriter, r *http.Request) {
+	yearIDStr := mux.Vars(r)["yearID"]
+	yearID, err := strconv.ParseUint(yearIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid academic year ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteAcademicYear(yearID); err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete academic year", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting academic year: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// Term handlers
+func (h *TermHandler) GetCurrentTerm(w http.ResponseWriter, r *http.Request) {
+	term, err := h.service.GetCurrentTerm()
+	if err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, "No current term", status)
+		} else {
+			http.Error(w, "Failed to fetch current term", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while fetching current term: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(term)
+}
+
+func (h *TermHandler) GetTerm(w http.ResponseWriter, r *http.Request) {
+	termIDStr := mux.Vars(r)["termID"]
+	termID, err := strconv.ParseUint(termIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+
+	term, err := h.service.GetTerm(termID)
+	if err != nil {
+		http.Error(w, "Term not found", http.StatusNotFound)
+		fmt.Println("Error while fetching term: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(term)
+}
+
+func (h *TermHandler) CreateTerm(w http.ResponseWriter, r *http.Request) {
+	yearIDStr := mux.Vars(r)["yearID"]
+	yearID, err := strconv.ParseUint(yearIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid academic year ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.CreateTermRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if requestBody.Name == "" {
+		http.Error(w, "Term name is required", http.StatusBadRequest)
+		return
+	}
+
+	term, err := h.service.CreateTerm(yearID, &requestBody)
+	if err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to create term", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while creating term: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(term)
+}
+
+func (h *TermHandler) UpdateTerm(w http.ResponseWriter, r *http.Request) {
+	termIDStr := mux.Vars(r)["termID"]
+	termID, err := strconv.ParseUint(termIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.PatchTermRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	term, err := h.service.UpdateTerm(&requestBody, termID)
+	if err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to update term", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while updating term: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(term)
+}
+
+func (h *TermHandler) DeleteTerm(w http.ResponseWriter, r *http.Request) {
+	termIDStr := mux.Vars(r)["termID"]
+	termID, err := strconv.ParseUint(termIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.DeleteTerm(termID); err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to delete term", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while deleting term: ", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *TermHandler) Rollover(w http.ResponseWriter, r *http.Request) {
+	termIDStr := mux.Vars(r)["termID"]
+	termID, err := strconv.ParseUint(termIDStr, 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid term ID", http.StatusBadRequest)
+		return
+	}
+
+	var requestBody models.RolloverTermRequest
+	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	lessons, err := h.service.Rollover(termID, requestBody.TargetTermID)
+	if err != nil {
+		if status := termErrorStatus(err); status != http.StatusInternalServerError {
+			http.Error(w, err.Error(), status)
+		} else {
+			http.Error(w, "Failed to roll over term", http.StatusInternalServerError)
+		}
+		fmt.Println("Error while rolling over term: ", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(lessons)
+}
+
+// termErrorStatus maps service errors to HTTP status codes
+func termErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidDateRange),
+		errors.Is(err, ErrTermOutsideYear),
+		errors.Is(err, ErrSameRolloverTarget):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrAcademicYearInUse),
+		errors.Is(err, ErrTermInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,215 @@
+package terms
+
+import (
+	"fmt"
+	"lesson-management/entities"
+	"lesson-management/pkg/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ITermRepository interface {
+	GetAcademicYears() ([]*entities.AcademicYear, error)
+	GetAcademicYear(id uint) (entities.AcademicYear, error)
+	CreateAcademicYear(year *entities.AcademicYear) error
+	UpdateAcademicYear(year *entities.AcademicYear) error
+	DeleteAcademicYear(id uint) error
+	GetTerm(id uint) (entities.Term, error)
+	CreateTerm(term *entities.Term) error
+	UpdateTerm(term *entities.Term) error
+	DeleteTerm(id uint) error
+	RolloverLessons(sourceTermID uint, targetTermID uint) ([]*entities.Lesson, error)
+}
+
+type TermRepository struct{}
+
+func NewTermRepository() ITermRepository {
+	return &TermRepository{}
+}
+
+func (r *TermRepository) GetAcademicYears() ([]*entities.AcademicYear, error) {
+	var years []*entities.AcademicYear
+	result := common.DB.
+		Preload("Terms", func(db *gorm.DB) *gorm.DB { return db.Order("starts_on") }).
+		Order("starts_on DESC").
+		Find(&years)
+	return years, result.Error
+}
+
+func (r *TermRepository) GetAcademicYear(id uint) (entities.AcademicYear, error) {
+	var year entities.AcademicYear
+	result := common.DB.
+		Preload("Terms", func(db *gorm.DB) *gorm.DB { return db.Order("starts_on") }).
+		First(&year, id)
+	return year, result.Error
+}
+
+func (r *TermRepository) CreateAcademicYear(year *entities.AcademicYear) error {
+	return common.DB.Create(year).Error
+}
+
+func (r *TermRepository) UpdateAcademicYear(year *entities.AcademicYear) error {
+	result := common.DB.Omit(clause.Associations).Save(year)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+func (r *TermRepository) DeleteAcademicYear(id uint) error {
+	var count int64
+	if err := common.DB.Model(&entities.Term{}).Where("academic_year_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrAcademicYearInUse
+	}
+
+	return common.DB.Delete(&entities.AcademicYear{}, id).Error
+}
+
+func (r *TermRepository) GetTerm(id uint) (entities.Term, error) {
+	var term entities.Term
+	result := common.DB.First(&term, id)
+	return term, result.Error
+}
+
+func (r *TermRepository) CreateTerm(term *entities.Term) error {
+	return common.DB.Create(term).Error
+}
+
+func (r *TermRepository) UpdateTerm(term *entities.Term) error {
+	result := common.DB.Save(term)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no rows affected")
+	}
+
+	return nil
+}
+
+// DeleteTerm refuses to delete terms still referenced by lessons, including lessons in the trash
+func (r *TermRepository) DeleteTerm(id uint) error {
+	var count int64
+	if err := common.DB.Unscoped().Model(&entities.Lesson{}).Where("term_id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrTermInUse
+	}
+
+	return common.DB.Delete(&entities.Term{}, id).Error
+}
+
+// RolloverLessons copies the source term's lessons into the target term as drafts, leaving archived lessons behind.
+// Teachers, prerequisites, subject and tags are copied; enrollments, requests and schedules are not.
+// Lessons already rolled into the target term are skipped, so running it again only copies lessons added since.
+func (r *TermRepository) RolloverLessons(sourceTermID uint, targetTermID uint) ([]*entities.Lesson, error) {
+	var copies []*entities.Lesson
+
+	err := common.DB.Transaction(func(tx *gorm.DB) error {
+		var sources []*entities.Lesson
+		if err := tx.Where("term_id = ? AND status <> ?", sourceTermID, entities.LessonArchived).
+			Preload("Teachers").
+			Preload("Prerequisites").
+			Preload("Tags").
+			Order("id").
+			Find(&sources).Error; err != nil {
+			return err
+		}
+
+		// Copies in the trash count as well, so restoring one can't produce a duplicate
+		var earlier []*entities.Lesson
+		if err := tx.Unscoped().
+			Where("term_id = ? AND rolled_over_from_id IS NOT NULL", targetTermID).
+			Find(&earlier).Error; err != nil {
+			return err
+		}
+
+		copiedIDs := make(map[uint]uint, len(sources))
+		for _, lesson := range earlier {
+			copiedIDs[*lesson.RolledOverFromID] = lesson.ID
+		}
+
+		var rolled []*entities.Lesson
+		for _, source := range sources {
+			if _, ok := copiedIDs[source.ID]; ok {
+				continue
+			}
+
+			sourceID := source.ID
+			lesson := &entities.Lesson{
+				Title:             source.Title,
+				Description:       source.Description,
+				TeacherID:         source.TeacherID,
+				TermID:            &targetTermID,
+				SubjectID:         source.SubjectID,
+				SelfEnrollment:    source.SelfEnrollment,
+				RequiresApproval:  source.RequiresApproval,
+				Capacity:          source.Capacity,
+				GradingScaleID:    source.GradingScaleID,
+				CompletionPercent: source.CompletionPercent,
+				Status:            entities.LessonDraft,
+				RolledOverFromID:  &sourceID,
+			}
+			if err := tx.Omit(clause.Associations).Create(lesson).Error; err != nil {
+				return err
+			}
+			copiedIDs[source.ID] = lesson.ID
+			rolled = append(rolled, source)
+
+			for _, teacher := range source.Teachers {
+				lessonTeacher := &entities.LessonTeacher{
+					LessonID:  lesson.ID,
+					TeacherID: teacher.TeacherID,
+					Role:      teacher.Role,
+				}
+				if err := tx.Create(lessonTeacher).Error; err != nil {
+					return err
+				}
+			}
+
+			if len(source.Tags) > 0 {
+				if err := tx.Model(lesson).Association("Tags").Append(source.Tags); err != nil {
+					return err
+				}
+			}
+
+			copies = append(copies, lesson)
+		}
+
+		// Prerequisites inside the rolled-over set point at the copies in the target term
+		for _, source := range rolled {
+			for _, prerequisite := range source.Prerequisites {
+				prerequisiteID := prerequisite.ID
+				if copiedID, ok := copiedIDs[prerequisiteID]; ok {
+					prerequisiteID = copiedID
+				}
+				if err := tx.Exec(
+					"INSERT INTO lesson_prerequisites (lesson_id, prerequisite_id) VALUES (?, ?) ON CONFLICT DO NOTHING",
+					copiedIDs[source.ID], prerequisiteID,
+				).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return copies, nil
+}
@@ -0,0 +1,36 @@
+package terms
+
+import (
+	"lesson-management/internal/modules/auth"
+	"lesson-management/pkg/middleware"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func InitRoutes(router *mux.Router, handler *TermHandler, authService auth.IAuthService) {
+	// Authentication middleware
+	authMiddleware := middleware.AuthMiddleware(authService)
+
+	// Public endpoints (no auth required)
+	router.HandleFunc("/api/academic-years", handler.ListAcademicYears).Methods(http.MethodGet)
+	router.HandleFunc("/api/academic-years/{yearID:[0-9]+}", handler.GetAcademicYear).Methods(http.MethodGet)
+	router.HandleFunc("/api/terms/current", handler.GetCurrentTerm).Methods(http.MethodGet)
+	router.HandleFunc("/api/terms/{termID:[0-9]+}", handler.GetTerm).Methods(http.MethodGet)
+
+	// Admin-only endpoints
+	adminYearRoutes := router.PathPrefix("/api/academic-years").Subrouter()
+	adminYearRoutes.Use(authMiddleware)
+	adminYearRoutes.Use(middleware.RequireRole("admin"))
+	adminYearRoutes.HandleFunc("", handler.CreateAcademicYear).Methods(http.MethodPost)
+	adminYearRoutes.HandleFunc("/{yearID:[0-9]+}", handler.UpdateAcademicYear).Methods(http.MethodPut)
+	adminYearRoutes.HandleFunc("/{yearID:[0-9]+}", handler.DeleteAcademicYear).Methods(http.MethodDelete)
+	adminYearRoutes.HandleFunc("/{yearID:[0-9]+}/terms", handler.CreateTerm).Methods(http.MethodPost)
+
+	adminTermRoutes := router.PathPrefix("/api/terms").Subrouter()
+	adminTermRoutes.Use(authMiddleware)
+	adminTermRoutes.Use(middleware.RequireRole("admin"))
+	adminTermRoutes.HandleFunc("/{termID:[0-9]+}", handler.UpdateTerm).Methods(http.MethodPut)
+	adminTermRoutes.HandleFunc("/{termID:[0-9]+}", handler.DeleteTerm).Methods(http.MethodDelete)
+	adminTermRoutes.HandleFunc("/{termID:[0-9]+}/rollover", handler.Rollover).Methods(http.MethodPost)
+}
@@ -0,0 +1,222 @@
+package terms
+
+import (
+	"errors"
+	"lesson-management/entities"
+	"lesson-management/internal/modules/lessons"
+	"lesson-management/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidDateRange   = errors.New("end date must be after start date")
+	ErrTermOutsideYear    = errors.New("term must fall within its academic year")
+	ErrAcademicYearInUse  = errors.New("academic year still has terms")
+	ErrTermInUse          = errors.New("term still has lessons")
+	ErrSameRolloverTarget = errors.New("rollover target must be a different term")
+)
+
+type ITermService interface {
+	GetAcademicYears() ([]*entities.AcademicYear, error)
+	GetAcademicYear(id uint64) (*entities.AcademicYear, error)
+	CreateAcademicYear(request *models.CreateAcademicYearRequest) (*entities.AcademicYear, error)
+	UpdateAcademicYear(request *models.PatchAcademicYearRequest, id uint64) (*entities.AcademicYear, error)
+	DeleteAcademicYear(id uint64) error
+	GetTerm(id uint64) (*entities.Term, error)
+	GetCurrentTerm() (*entities.Term, error)
+	CreateTerm(academicYearID uint64, request *models.CreateTermRequest) (*entities.Term, error)
+	UpdateTerm(request *models.PatchTermRequest, id uint64) (*entities.Term, error)
+	DeleteTerm(id uint64) error
+	Rollover(sourceTermID uint64, targetTermID uint) ([]*entities.Lesson, error)
+}
+
+type TermService struct {
+	repo          ITermRepository
+	lessonService lessons.ILessonService
+}
+
+func NewTermService(repo ITermRepository, lessonService lessons.ILessonService) ITermService {
+	return &TermService{
+		repo:          repo,
+		lessonService: lessonService,
+	}
+}
+
+func (s *TermService) GetAcademicYears() ([]*entities.AcademicYear, error) {
+	return s.repo.GetAcademicYears()
+}
+
+func (s *TermService) GetAcademicYear(id uint64) (*entities.AcademicYear, error) {
+	year, err := s.repo.GetAcademicYear(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &year, nil
+}
+
+func (s *TermService) CreateAcademicYear(request *models.CreateAcademicYearRequest) (*entities.AcademicYear, error) {
+	if !request.EndsOn.After(request.StartsOn) {
+		return nil, ErrInvalidDateRange
+	}
+
+	year := &entities.AcademicYear{
+		Name:     request.Name,
+		StartsOn: request.StartsOn,
+		EndsOn:   request.EndsOn,
+	}
+	if err := s.repo.CreateAcademicYear(year); err != nil {
+		return nil, err
+	}
+
+	return year, nil
+}
+
+func (s *TermService) UpdateAcademicYear(request *models.PatchAcademicYearRequest, id uint64) (*entities.AcademicYear, error) {
+	year, err := s.repo.GetAcademicYear(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		year.Name = *request.Name
+	}
+	if request.StartsOn != nil {
+		year.StartsOn = *request.StartsOn
+	}
+	if request.EndsOn != nil {
+		year.EndsOn = *request.EndsOn
+	}
+
+	if !year.EndsOn.After(year.StartsOn) {
+		return nil, ErrInvalidDateRange
+	}
+	for _, term := range year.Terms {
+		if !withinYear(&year, term.StartsOn, term.EndsOn) {
+			return nil, ErrTermOutsideYear
+		}
+	}
+
+	if err := s.repo.UpdateAcademicYear(&year); err != nil {
+		return nil, err
+	}
+
+	return &year, nil
+}
+
+func (s *TermService) DeleteAcademicYear(id uint64) error {
+	return s.repo.DeleteAcademicYear(uint(id))
+}
+
+func (s *TermService) GetTerm(id uint64) (*entities.Term, error) {
+	term, err := s.repo.GetTerm(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &term, nil
+}
+
+// GetCurrentTerm returns the running term or gorm.ErrRecordNotFound when none is running
+func (s *TermService) GetCurrentTerm() (*entities.Term, error) {
+	term, err := s.lessonService.GetCurrentTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	if term == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return term, nil
+}
+
+func (s *TermService) CreateTerm(academicYearID uint64, request *models.CreateTermRequest) (*entities.Term, error) {
+	year, err := s.repo.GetAcademicYear(uint(academicYearID))
+	if err != nil {
+		return nil, err
+	}
+
+	if !request.EndsOn.After(request.StartsOn) {
+		return nil, ErrInvalidDateRange
+	}
+	if !withinYear(&year, request.StartsOn, request.EndsOn) {
+		return nil, ErrTermOutsideYear
+	}
+
+	term := &entities.Term{
+		AcademicYearID: year.ID,
+		Name:           request.Name,
+		StartsOn:       request.StartsOn,
+		EndsOn:         request.EndsOn,
+	}
+	if err := s.repo.CreateTerm(term); err != nil {
+		return nil, err
+	}
+
+	return term, nil
+}
+
+func (s *TermService) UpdateTerm(request *models.PatchTermRequest, id uint64) (*entities.Term, error) {
+	term, err := s.repo.GetTerm(uint(id))
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Name != nil {
+		term.Name = *request.Name
+	}
+	if request.StartsOn != nil {
+		term.StartsOn = *request.StartsOn
+	}
+	if request.EndsOn != nil {
+		term.EndsOn = *request.EndsOn
+	}
+
+	if !term.EndsOn.After(term.StartsOn) {
+		return nil, ErrInvalidDateRange
+	}
+
+	year, err := s.repo.GetAcademicYear(term.AcademicYearID)
+	if err != nil {
+		return nil, err
+	}
+	if !withinYear(&year, term.StartsOn, term.EndsOn) {
+		return nil, ErrTermOutsideYear
+	}
+
+	if err := s.repo.UpdateTerm(&term); err != nil {
+		return nil, err
+	}
+
+	return &term, nil
+}
+
+func (s *TermService) DeleteTerm(id uint64) error {
+	return s.repo.DeleteTerm(uint(id))
+}
+
+// Rollover copies every lesson of the source term into the target term without enrollments
+func (s *TermService) Rollover(sourceTermID uint64, targetTermID uint) ([]*entities.Lesson, error) {
+	source, err := s.repo.GetTerm(uint(sourceTermID))
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.repo.GetTerm(targetTermID)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.ID == target.ID {
+		return nil, ErrSameRolloverTarget
+	}
+
+	return s.repo.RolloverLessons(source.ID, target.ID)
+}
+
+func withinYear(year *entities.AcademicYear, startsOn time.Time, endsOn time.Time) bool {
+	return !startsOn.Before(year.StartsOn) && !endsOn.After(year.EndsOn)
+}
@@ -0,0 +1,5 @@
+package models
+
+type AddModuleLessonRequest struct {
+	LessonID uint `json:"lesson_id"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type AssignLessonTeacherRequest struct {
+	TeacherID uint   `json:"teacher_id"`
+	Role      string `json:"role"`
+}
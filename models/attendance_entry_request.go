@@ -0,0 +1,7 @@
+package models
+
+type AttendanceEntryRequest struct {
+	StudentID uint   `json:"student_id"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type BatchEnrollmentRequest struct {
+	Atomic     bool                  `json:"atomic"`
+	Operations []EnrollmentOperation `json:"operations"`
+}
@@ -0,0 +1,9 @@
+package models
+
+type BatchEnrollmentResponse struct {
+	Atomic    bool                        `json:"atomic"`
+	Applied   bool                        `json:"applied"`
+	Succeeded int                         `json:"succeeded"`
+	Failed    int                         `json:"failed"`
+	Results   []EnrollmentOperationResult `json:"results"`
+}
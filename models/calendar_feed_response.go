@@ -0,0 +1,6 @@
+package models
+
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// CategoryTotal is a student's percentage in one category; nil until something is graded
+type CategoryTotal struct {
+	CategoryID uint     `json:"category_id"`
+	Name       string   `json:"name"`
+	Weight     float64  `json:"weight"`
+	Percent    *float64 `json:"percent"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type ChangeLessonStatusRequest struct {
+	Status string `json:"status"`
+}
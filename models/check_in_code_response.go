@@ -0,0 +1,8 @@
+package models
+
+import "time"
+
+type CheckInCodeResponse struct {
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type CheckInRequest struct {
+	Code string `json:"code"`
+}
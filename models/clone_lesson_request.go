@@ -0,0 +1,9 @@
+package models
+
+type CloneLessonRequest struct {
+	Title         *string `json:"title"`
+	Description   *string `json:"description"`
+	TeacherID     *uint   `json:"teacher_id"`
+	TermID        *uint   `json:"term_id"`
+	IncludeRoster bool    `json:"include_roster"`
+}
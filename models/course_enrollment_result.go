@@ -0,0 +1,10 @@
+package models
+
+// CourseEnrollmentResult lists the course lessons a student is now enrolled in and the ones
+// they were left out of
+type CourseEnrollmentResult struct {
+	CourseID          uint                  `json:"course_id"`
+	StudentID         uint                  `json:"student_id"`
+	EnrolledLessonIDs []uint                `json:"enrolled_lesson_ids"`
+	Skipped           []SkippedCourseLesson `json:"skipped"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "lesson-management/entities"
+
+// CourseLesson is a lesson listed in curriculum order
+type CourseLesson struct {
+	ModuleID       uint             `json:"module_id"`
+	ModuleTitle    string           `json:"module_title"`
+	ModulePosition int              `json:"module_position"`
+	Position       int              `json:"position"`
+	Lesson         *entities.Lesson `json:"lesson"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type CreateAcademicYearRequest struct {
+	Name     string    `json:"name"`
+	StartsOn time.Time `json:"starts_on"`
+	EndsOn   time.Time `json:"ends_on"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type CreateAssignmentRequest struct {
+	Title              string     `json:"title"`
+	Instructions       string     `json:"instructions"`
+	DueAt              time.Time  `json:"due_at"`
+	LatePolicy         string     `json:"late_policy"`
+	LateUntil          *time.Time `json:"late_until"`
+	LatePenaltyPercent float64    `json:"late_penalty_percent"`
+	RubricID           *uint      `json:"rubric_id"`
+	GradeItemID        *uint      `json:"grade_item_id"`
+}
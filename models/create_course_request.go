@@ -0,0 +1,6 @@
+package models
+
+type CreateCourseRequest struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// CreateFileMaterialRequest carries the form fields sent along with an uploaded material file
+type CreateFileMaterialRequest struct {
+	SectionID   *uint
+	Title       string
+	Description string
+	ReleaseAt   *time.Time
+}
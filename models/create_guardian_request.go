@@ -0,0 +1,7 @@
+package models
+
+type CreateGuardianRequest struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
@@ -1,7 +1,18 @@
 package models
 
+import "time"
+
 type CreateLessonRequest struct {
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	TeacherID   uint   `json:"teacher_id"`
+	Title              string     `json:"title"`
+	Description        string     `json:"description"`
+	TermID             *uint      `json:"term_id"`
+	SubjectID          *uint      `json:"subject_id"`
+	TeacherID          uint       `json:"teacher_id"`
+	SelfEnrollment     bool       `json:"self_enrollment"`
+	RequiresApproval   bool       `json:"requires_approval"`
+	EnrollmentOpensAt  *time.Time `json:"enrollment_opens_at"`
+	EnrollmentClosesAt *time.Time `json:"enrollment_closes_at"`
+	Capacity           int        `json:"capacity"`
+	PublishAt          *time.Time `json:"publish_at"`
+	UnpublishAt        *time.Time `json:"unpublish_at"`
 }
@@ -0,0 +1,10 @@
+package models
+
+type CreateLessonTemplateRequest struct {
+	Name             string `json:"name"`
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	SelfEnrollment   bool   `json:"self_enrollment"`
+	RequiresApproval bool   `json:"requires_approval"`
+	PrerequisiteIDs  []uint `json:"prerequisite_ids"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type CreateLinkMaterialRequest struct {
+	SectionID   *uint      `json:"section_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"`
+	ReleaseAt   *time.Time `json:"release_at"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type CreateModuleRequest struct {
+	Title string `json:"title"`
+}
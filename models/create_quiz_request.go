@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type CreateQuizRequest struct {
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	GradeItemID      *uint      `json:"grade_item_id"`
+	TimeLimitMinutes int        `json:"time_limit_minutes"`
+	MaxAttempts      int        `json:"max_attempts"`
+	ShuffleQuestions bool       `json:"shuffle_questions"`
+	ScorePolicy      string     `json:"score_policy"`
+	PassPercent      float64    `json:"pass_percent"`
+	OpensAt          *time.Time `json:"opens_at"`
+	ClosesAt         *time.Time `json:"closes_at"`
+}
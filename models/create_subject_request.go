@@ -0,0 +1,6 @@
+package models
+
+type CreateSubjectRequest struct {
+	Name     string `json:"name"`
+	ParentID *uint  `json:"parent_id"`
+}
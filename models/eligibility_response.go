@@ -0,0 +1,6 @@
+package models
+
+type EligibilityResponse struct {
+	Eligible           bool                `json:"eligible"`
+	UnmetPrerequisites []UnmetPrerequisite `json:"unmet_prerequisites"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type EnrollStudentRequest struct {
+	StudentID             uint   `json:"student_id"`
+	OverridePrerequisites bool   `json:"override_prerequisites"`
+	OverrideReason        string `json:"override_reason"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type EnrollmentOperation struct {
+	Op         string `json:"op"`
+	StudentID  uint   `json:"student_id"`
+	LessonID   uint   `json:"lesson_id"`
+	ToLessonID uint   `json:"to_lesson_id,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+type EnrollmentOperationResult struct {
+	Index              int                 `json:"index"`
+	Op                 string              `json:"op"`
+	StudentID          uint                `json:"student_id"`
+	LessonID           uint                `json:"lesson_id"`
+	ToLessonID         uint                `json:"to_lesson_id,omitempty"`
+	Status             string              `json:"status"`
+	Error              string              `json:"error,omitempty"`
+	UnmetPrerequisites []UnmetPrerequisite `json:"unmet_prerequisites,omitempty"`
+}
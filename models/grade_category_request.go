@@ -0,0 +1,6 @@
+package models
+
+type GradeCategoryRequest struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type GradeEntryRequest struct {
+	StudentID uint    `json:"student_id"`
+	Points    float64 `json:"points"`
+	Excused   bool    `json:"excused"`
+	Feedback  string  `json:"feedback"`
+}
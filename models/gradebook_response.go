@@ -0,0 +1,11 @@
+package models
+
+import "lesson-management/entities"
+
+type GradebookResponse struct {
+	LessonID     uint                     `json:"lesson_id"`
+	GradingScale *entities.GradingScale   `json:"grading_scale,omitempty"`
+	Categories   []entities.GradeCategory `json:"categories"`
+	Items        []entities.GradeItem     `json:"items"`
+	Students     []StudentGradeSummary    `json:"students"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type GradingScaleBandRequest struct {
+	Letter     string  `json:"letter"`
+	MinPercent float64 `json:"min_percent"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type GradingScaleRequest struct {
+	Name  string                    `json:"name"`
+	Bands []GradingScaleBandRequest `json:"bands"`
+}
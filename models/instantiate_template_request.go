@@ -0,0 +1,12 @@
+package models
+
+type InstantiateTemplateRequest struct {
+	Lessons []TemplateInstance `json:"lessons"`
+}
+
+// TemplateInstance overrides template fields for one created lesson
+type TemplateInstance struct {
+	Title     string `json:"title"`
+	TeacherID uint   `json:"teacher_id"`
+	TermID    *uint  `json:"term_id"`
+}
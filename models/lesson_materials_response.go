@@ -0,0 +1,10 @@
+package models
+
+import "lesson-management/entities"
+
+// LessonMaterialsResponse lists a lesson's sections in order, followed by materials outside any section
+type LessonMaterialsResponse struct {
+	LessonID  uint                       `json:"lesson_id"`
+	Sections  []entities.MaterialSection `json:"sections"`
+	Materials []entities.Material        `json:"materials"`
+}
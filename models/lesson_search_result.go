@@ -0,0 +1,11 @@
+package models
+
+import "lesson-management/entities"
+
+// LessonSearchResult is a ranked search hit. Highlights are HTML-escaped with matches wrapped in <mark>
+type LessonSearchResult struct {
+	Lesson         *entities.Lesson `json:"lesson"`
+	Rank           float32          `json:"rank"`
+	TitleHighlight string           `json:"title_highlight"`
+	Snippet        string           `json:"snippet"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type LinkGuardianRequest struct {
+	GuardianID uint `json:"guardian_id"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// MaterialDownloadStat summarizes how often one enrolled student opened a material
+type MaterialDownloadStat struct {
+	StudentID         uint       `json:"student_id"`
+	Name              string     `json:"name"`
+	Downloads         int64      `json:"downloads"`
+	FirstDownloadedAt *time.Time `json:"first_downloaded_at,omitempty"`
+	LastDownloadedAt  *time.Time `json:"last_downloaded_at,omitempty"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type MaterialSectionRequest struct {
+	Title string `json:"title"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "lesson-management/entities"
+
+// ModuleLessonResult is the course after a lesson was added to one of its modules, with the
+// course students the lesson could not take
+type ModuleLessonResult struct {
+	Course  *entities.Course       `json:"course"`
+	Skipped []SkippedCourseStudent `json:"skipped"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type OpenCheckInRequest struct {
+	DurationMinutes int `json:"duration_minutes"`
+	RotationSeconds int `json:"rotation_seconds"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// PatchAssignmentRequest updates the given fields. A rubric_id or grade_item_id of 0 detaches it
+type PatchAssignmentRequest struct {
+	Title              *string    `json:"title"`
+	Instructions       *string    `json:"instructions"`
+	DueAt              *time.Time `json:"due_at"`
+	LatePolicy         *string    `json:"late_policy"`
+	LateUntil          *time.Time `json:"late_until"`
+	LatePenaltyPercent *float64   `json:"late_penalty_percent"`
+	RubricID           *uint      `json:"rubric_id"`
+	GradeItemID        *uint      `json:"grade_item_id"`
+}
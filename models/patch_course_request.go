@@ -0,0 +1,6 @@
+package models
+
+type PatchCourseRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+}
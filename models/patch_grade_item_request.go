@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type PatchGradeItemRequest struct {
+	CategoryID *uint      `json:"category_id"`
+	Title      *string    `json:"title"`
+	MaxPoints  *float64   `json:"max_points"`
+	DueAt      *time.Time `json:"due_at"`
+}
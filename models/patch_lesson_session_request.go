@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type PatchLessonSessionRequest struct {
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Location *string    `json:"location"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// PatchMaterialRequest updates the given fields. A section_id of 0 takes the material out of its section
+type PatchMaterialRequest struct {
+	SectionID   *uint      `json:"section_id"`
+	Title       *string    `json:"title"`
+	Description *string    `json:"description"`
+	URL         *string    `json:"url"`
+	ReleaseAt   *time.Time `json:"release_at"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type PatchModuleRequest struct {
+	Title *string `json:"title"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type PatchSubjectRequest struct {
+	Name     *string `json:"name"`
+	ParentID *uint   `json:"parent_id"`
+	MakeRoot bool    `json:"make_root"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ProgressItem is one material, quiz or assignment counted towards a student's progress
+type ProgressItem struct {
+	Kind        string     `json:"kind"`
+	ItemID      uint       `json:"item_id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
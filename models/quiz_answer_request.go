@@ -0,0 +1,10 @@
+package models
+
+// QuizAnswerRequest answers one question: option_ids for choice questions, number for
+// numeric questions and text for short text questions
+type QuizAnswerRequest struct {
+	QuestionID uint     `json:"question_id"`
+	OptionIDs  []uint   `json:"option_ids"`
+	Number     *float64 `json:"number"`
+	Text       string   `json:"text"`
+}
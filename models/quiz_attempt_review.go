@@ -0,0 +1,11 @@
+package models
+
+import "lesson-management/entities"
+
+// QuizAttemptReview is an attempt as teachers review it, with the full questions in the order the student saw them
+type QuizAttemptReview struct {
+	StudentID uint                    `json:"student_id"`
+	Name      string                  `json:"name"`
+	Attempt   entities.QuizAttempt    `json:"attempt"`
+	Questions []entities.QuizQuestion `json:"questions"`
+}
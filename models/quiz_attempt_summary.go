@@ -0,0 +1,12 @@
+package models
+
+import "lesson-management/entities"
+
+// QuizAttemptSummary is one enrolled student's attempts at a quiz with the score that counts
+type QuizAttemptSummary struct {
+	StudentID uint                   `json:"student_id"`
+	Name      string                 `json:"name"`
+	Attempts  []entities.QuizAttempt `json:"attempts"`
+	Score     *float64               `json:"score,omitempty"`
+	MaxScore  float64                `json:"max_score"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "lesson-management/entities"
+
+// QuizAttemptView is a student's attempt with its questions in the order they were dealt
+type QuizAttemptView struct {
+	Attempt   entities.QuizAttempt `json:"attempt"`
+	Questions []QuizQuestionView   `json:"questions"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type QuizOptionRequest struct {
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+}
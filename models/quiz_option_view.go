@@ -0,0 +1,6 @@
+package models
+
+type QuizOptionView struct {
+	ID   uint   `json:"id"`
+	Text string `json:"text"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// QuizQuestionRequest describes a whole question. Choice questions use options, numeric
+// questions answer and tolerance, and short text questions accepted_answers
+type QuizQuestionRequest struct {
+	Kind            string              `json:"kind"`
+	Prompt          string              `json:"prompt"`
+	Points          float64             `json:"points"`
+	Options         []QuizOptionRequest `json:"options"`
+	Answer          *float64            `json:"answer"`
+	Tolerance       float64             `json:"tolerance"`
+	AcceptedAnswers []string            `json:"accepted_answers"`
+	CaseSensitive   bool                `json:"case_sensitive"`
+}
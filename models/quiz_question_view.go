@@ -0,0 +1,10 @@
+package models
+
+// QuizQuestionView is a question as students see it, without the correct answers
+type QuizQuestionView struct {
+	ID      uint             `json:"id"`
+	Kind    string           `json:"kind"`
+	Prompt  string           `json:"prompt"`
+	Points  float64          `json:"points"`
+	Options []QuizOptionView `json:"options,omitempty"`
+}
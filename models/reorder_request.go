@@ -0,0 +1,5 @@
+package models
+
+type ReorderRequest struct {
+	IDs []uint `json:"ids"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type ReviewQuizResponseRequest struct {
+	Points   float64 `json:"points"`
+	Feedback string  `json:"feedback"`
+}
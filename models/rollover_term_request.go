@@ -0,0 +1,5 @@
+package models
+
+type RolloverTermRequest struct {
+	TargetTermID uint `json:"target_term_id"`
+}
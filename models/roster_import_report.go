@@ -0,0 +1,9 @@
+package models
+
+type RosterImportReport struct {
+	DryRun  bool                `json:"dry_run"`
+	Valid   bool                `json:"valid"`
+	Applied bool                `json:"applied"`
+	Summary RosterImportSummary `json:"summary"`
+	Rows    []RosterRowResult   `json:"rows"`
+}
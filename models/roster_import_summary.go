@@ -0,0 +1,9 @@
+package models
+
+type RosterImportSummary struct {
+	TotalRows        int `json:"total_rows"`
+	InvalidRows      int `json:"invalid_rows"`
+	StudentsToCreate int `json:"students_to_create"`
+	Enrollments      int `json:"enrollments"`
+	AlreadyEnrolled  int `json:"already_enrolled"`
+}
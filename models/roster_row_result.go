@@ -0,0 +1,10 @@
+package models
+
+type RosterRowResult struct {
+	Row      int      `json:"row"`
+	Email    string   `json:"email"`
+	Name     string   `json:"name,omitempty"`
+	LessonID uint     `json:"lesson_id,omitempty"`
+	Action   string   `json:"action"`
+	Errors   []string `json:"errors,omitempty"`
+}
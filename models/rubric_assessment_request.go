@@ -0,0 +1,7 @@
+package models
+
+// RubricAssessmentRequest scores every criterion of the assignment's rubric for one student
+type RubricAssessmentRequest struct {
+	Comment string               `json:"comment"`
+	Scores  []RubricScoreRequest `json:"scores"`
+}
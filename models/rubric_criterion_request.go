@@ -0,0 +1,7 @@
+package models
+
+type RubricCriterionRequest struct {
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Levels      []RubricLevelRequest `json:"levels"`
+}
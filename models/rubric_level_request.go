@@ -0,0 +1,7 @@
+package models
+
+type RubricLevelRequest struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Points      float64 `json:"points"`
+}
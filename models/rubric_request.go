@@ -0,0 +1,8 @@
+package models
+
+// RubricRequest describes a whole rubric. Updating a rubric replaces all of its criteria and levels
+type RubricRequest struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Criteria    []RubricCriterionRequest `json:"criteria"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "lesson-management/entities"
+
+// RubricResult is an assignment's rubric with a student's assessment, which is absent until they are marked
+type RubricResult struct {
+	StudentID  uint                       `json:"student_id"`
+	Rubric     *entities.Rubric           `json:"rubric"`
+	Assessment *entities.RubricAssessment `json:"assessment,omitempty"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// RubricScoreRequest scores one criterion. Without points the chosen level's points are given
+type RubricScoreRequest struct {
+	CriterionID uint     `json:"criterion_id"`
+	LevelID     *uint    `json:"level_id"`
+	Points      *float64 `json:"points"`
+	Comment     string   `json:"comment"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type SaveQuizAnswersRequest struct {
+	Answers []QuizAnswerRequest `json:"answers"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SessionAttendanceEntry is one line of a session's attendance sheet; Status is empty
+// until attendance has been taken for the student
+type SessionAttendanceEntry struct {
+	StudentID  uint       `json:"student_id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status,omitempty"`
+	Note       string     `json:"note,omitempty"`
+	RecordedAt *time.Time `json:"recorded_at,omitempty"`
+}
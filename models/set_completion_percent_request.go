@@ -0,0 +1,7 @@
+package models
+
+// SetCompletionPercentRequest sets how much of a lesson a student must work through before
+// their enrollment is completed automatically; 0 turns automatic completion off
+type SetCompletionPercentRequest struct {
+	CompletionPercent int `json:"completion_percent"`
+}
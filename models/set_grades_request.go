@@ -0,0 +1,5 @@
+package models
+
+type SetGradesRequest struct {
+	Grades []GradeEntryRequest `json:"grades"`
+}
@@ -0,0 +1,6 @@
+package models
+
+// SetLessonGradingScaleRequest selects the lesson's scale; null removes it
+type SetLessonGradingScaleRequest struct {
+	GradingScaleID *uint `json:"grading_scale_id"`
+}
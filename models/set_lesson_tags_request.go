@@ -0,0 +1,5 @@
+package models
+
+type SetLessonTagsRequest struct {
+	Tags []string `json:"tags"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type SetPrerequisitesRequest struct {
+	PrerequisiteIDs []uint `json:"prerequisite_ids"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// SkippedCourseLesson is a course lesson a student could not be enrolled in, with the reason
+type SkippedCourseLesson struct {
+	LessonID           uint                `json:"lesson_id"`
+	Title              string              `json:"title"`
+	Reason             string              `json:"reason"`
+	UnmetPrerequisites []UnmetPrerequisite `json:"unmet_prerequisites,omitempty"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// SkippedCourseStudent is a course student who could not be enrolled in a lesson added to the course, with the reason
+type SkippedCourseStudent struct {
+	StudentID uint   `json:"student_id"`
+	Reason    string `json:"reason"`
+}
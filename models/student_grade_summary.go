@@ -0,0 +1,14 @@
+package models
+
+import "lesson-management/entities"
+
+// StudentGradeSummary is a student's grades in a lesson with running totals over what has
+// been graded so far
+type StudentGradeSummary struct {
+	StudentID  uint             `json:"student_id"`
+	Name       string           `json:"name"`
+	Grades     []entities.Grade `json:"grades"`
+	Categories []CategoryTotal  `json:"categories"`
+	Percent    *float64         `json:"percent"`
+	Letter     string           `json:"letter,omitempty"`
+}
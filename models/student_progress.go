@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// StudentProgress is how far a student has worked through a lesson's released materials,
+// published quizzes and assignments. Items lists each of them when viewing a single student
+type StudentProgress struct {
+	StudentID            uint           `json:"student_id"`
+	Name                 string         `json:"name"`
+	EnrollmentStatus     string         `json:"enrollment_status"`
+	CompletedAt          *time.Time     `json:"completed_at,omitempty"`
+	MaterialsViewed      int            `json:"materials_viewed"`
+	MaterialsTotal       int            `json:"materials_total"`
+	QuizzesPassed        int            `json:"quizzes_passed"`
+	QuizzesTotal         int            `json:"quizzes_total"`
+	AssignmentsSubmitted int            `json:"assignments_submitted"`
+	AssignmentsTotal     int            `json:"assignments_total"`
+	Percent              float64        `json:"percent"`
+	Items                []ProgressItem `json:"items,omitempty"`
+}
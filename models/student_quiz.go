@@ -0,0 +1,25 @@
+package models
+
+import (
+	"lesson-management/entities"
+	"time"
+)
+
+// StudentQuiz is a published quiz as listed for a student, with their own attempts
+type StudentQuiz struct {
+	ID               uint                   `json:"id"`
+	Title            string                 `json:"title"`
+	Description      string                 `json:"description"`
+	TimeLimitMinutes int                    `json:"time_limit_minutes"`
+	MaxAttempts      int                    `json:"max_attempts"`
+	ScorePolicy      string                 `json:"score_policy"`
+	PassPercent      float64                `json:"pass_percent"`
+	OpensAt          *time.Time             `json:"opens_at,omitempty"`
+	ClosesAt         *time.Time             `json:"closes_at,omitempty"`
+	Open             bool                   `json:"open"`
+	QuestionCount    int                    `json:"question_count"`
+	MaxScore         float64                `json:"max_score"`
+	Attempts         []entities.QuizAttempt `json:"attempts"`
+	Score            *float64               `json:"score,omitempty"`
+	Passed           bool                   `json:"passed"`
+}
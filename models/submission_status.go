@@ -0,0 +1,12 @@
+package models
+
+import "lesson-management/entities"
+
+// SubmissionStatus is where one enrolled student stands on an assignment
+type SubmissionStatus struct {
+	StudentID uint                           `json:"student_id"`
+	Name      string                         `json:"name"`
+	Status    string                         `json:"status"`
+	Versions  int                            `json:"versions"`
+	Latest    *entities.AssignmentSubmission `json:"latest,omitempty"`
+}
@@ -0,0 +1,5 @@
+package models
+
+type TagRequest struct {
+	Name string `json:"name"`
+}
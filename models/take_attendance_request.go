@@ -0,0 +1,5 @@
+package models
+
+type TakeAttendanceRequest struct {
+	Records []AttendanceEntryRequest `json:"records"`
+}
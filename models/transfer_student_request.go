@@ -0,0 +1,7 @@
+package models
+
+type TransferStudentRequest struct {
+	StudentID    uint `json:"student_id"`
+	FromLessonID uint `json:"from_lesson_id"`
+	ToLessonID   uint `json:"to_lesson_id"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type UnmetPrerequisite struct {
+	LessonID uint   `json:"lesson_id"`
+	Title    string `json:"title"`
+	Reason   string `json:"reason"`
+}
@@ -0,0 +1,21 @@
+package common
+
+// SameIDs reports whether both slices contain exactly the same IDs, as when a reorder
+// request must list every existing row once
+func SameIDs(existing []uint, requested []uint) bool {
+	if len(existing) != len(requested) {
+		return false
+	}
+
+	seen := make(map[uint]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = true
+	}
+	for _, id := range requested {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package common
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration is a one-off SQL change applied after AutoMigrate
+type Migration struct {
+	Name string
+	SQL  string
+}
+
+type schemaMigration struct {
+	Name      string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+var migrations = []Migration{
+	{
+		// Lessons created before the publication lifecycle were already public
+		Name: "001_publish_existing_lessons",
+		SQL:  "UPDATE lessons SET status = 'published', published_at = created_at WHERE status = 'draft'",
+	},
+	{
+		// Hard deletes used to leave enrollment rows behind
+		Name: "002_remove_orphaned_enrollments",
+		SQL: `DELETE FROM lesson_students WHERE lesson_id NOT IN (SELECT id FROM lessons);
+DELETE FROM enrollment_requests WHERE lesson_id NOT IN (SELECT id FROM lessons);
+DELETE FROM prerequisite_overrides WHERE lesson_id NOT IN (SELECT id FROM lessons);
+DELETE FROM lesson_prerequisites WHERE lesson_id NOT IN (SELECT id FROM lessons) OR prerequisite_id NOT IN (SELECT id FROM lessons)`,
+	},
+	{
+		// Existing single-teacher lessons become lessons with one lead teacher
+		Name: "003_backfill_lesson_teachers",
+		SQL: `INSERT INTO lesson_teachers (lesson_id, teacher_id, role, created_at)
+SELECT id, teacher_id, 'lead', NOW() FROM lessons WHERE teacher_id IS NOT NULL AND teacher_id <> 0
+ON CONFLICT DO NOTHING`,
+	},
+	{
+		// Progress tracking started after students had already opened materials, submitted
+		// assignments and finished quizzes; only attempts that reached the quiz's pass_percent count
+		Name: "005_backfill_progress_records",
+		SQL: `INSERT INTO progress_records (lesson_id, student_id, kind, item_id, recorded_at)
+SELECT lesson_id, student_id, 'material_viewed', material_id, MIN(downloaded_at) FROM material_downloads
+GROUP BY lesson_id, student_id, material_id
+ON CONFLICT DO NOTHING;
+INSERT INTO progress_records (lesson_id, student_id, kind, item_id, recorded_at)
+SELECT lesson_id, student_id, 'assignment_submitted', assignment_id, MIN(submitted_at) FROM assignment_submissions
+GROUP BY lesson_id, student_id, assignment_id
+ON CONFLICT DO NOTHING;
+INSERT INTO progress_records (lesson_id, student_id, kind, item_id, recorded_at)
+SELECT a.lesson_id, a.student_id, 'quiz_passed', a.quiz_id, MIN(a.submitted_at) FROM quiz_attempts a
+JOIN quizzes q ON q.id = a.quiz_id
+WHERE a.submitted_at IS NOT NULL AND a.max_score > 0 AND a.score * 100 >= q.pass_percent * a.max_score
+GROUP BY a.lesson_id, a.student_id, a.quiz_id
+ON CONFLICT DO NOTHING`,
+	},
+}
+
+// RunMigrations applies every migration that has not been recorded yet
+func RunMigrations() error {
+	if err := DB.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	language, err := SearchLanguage()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range append(migrations, lessonSearchIndexMigration(language)) {
+		var count int64
+		if err := DB.Model(&schemaMigration{}).Where("name = ?", migration.Name).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(migration.SQL).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return err
+		}
+		log.Println("✅ Applied migration", migration.Name)
+	}
+
+	return nil
+}
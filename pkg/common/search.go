@@ -0,0 +1,59 @@
+package common
+
+import (
+	"fmt"
+	"os"
+)
+
+const defaultSearchLanguage = "english"
+
+// searchLanguages are the built-in PostgreSQL text search configurations SEARCH_LANGUAGE may name
+var searchLanguages = map[string]bool{
+	"simple":     true,
+	"danish":     true,
+	"dutch":      true,
+	"english":    true,
+	"finnish":    true,
+	"french":     true,
+	"german":     true,
+	"hungarian":  true,
+	"italian":    true,
+	"norwegian":  true,
+	"portuguese": true,
+	"russian":    true,
+	"spanish":    true,
+	"swedish":    true,
+	"turkish":    true,
+}
+
+// SearchLanguage returns the text search configuration lessons are indexed with
+func SearchLanguage() (string, error) {
+	language := os.Getenv("SEARCH_LANGUAGE")
+	if language == "" {
+		return defaultSearchLanguage, nil
+	}
+	if !searchLanguages[language] {
+		return "", fmt.Errorf("unsupported SEARCH_LANGUAGE %q", language)
+	}
+	return language, nil
+}
+
+// LessonSearchDocument is the weighted tsvector expression over a lesson's title and description.
+// Queries must use exactly this expression for PostgreSQL to pick the GIN index
+func LessonSearchDocument(language string) string {
+	return fmt.Sprintf(
+		"(setweight(to_tsvector('%[1]s'::regconfig, coalesce(lessons.title, '')), 'A') || "+
+			"setweight(to_tsvector('%[1]s'::regconfig, coalesce(lessons.description, '')), 'B'))",
+		language,
+	)
+}
+
+// lessonSearchIndexMigration builds the index for the configured language; switching
+// languages adds a new migration so the matching index is created on the next start
+func lessonSearchIndexMigration(language string) Migration {
+	return Migration{
+		Name: "004_lesson_search_index_" + language,
+		SQL: fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_lessons_search_%s ON lessons USING GIN (%s)",
+			language, LessonSearchDocument(language)),
+	}
+}
@@ -0,0 +1,153 @@
+package markdown
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// MaterialScheme prefixes link destinations that refer to a lesson material, as in
+// [the slides](material:12) or ![diagram](material:7)
+const MaterialScheme = "material:"
+
+// Reference is what a material reference renders to
+type Reference struct {
+	URL   string
+	Title string
+}
+
+// Resolver looks up a referenced material; references it cannot resolve lose their link
+// and keep only their text
+type Resolver func(materialID uint) (Reference, bool)
+
+var (
+	converter = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		// Raw HTML passes through so descriptions written as HTML keep working;
+		// the sanitizer below decides what survives
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	// policy is an allowlist for user-written content: formatting, links, images and
+	// tables, but no scripts, styles, iframes, forms or event handlers
+	policy = bluemonday.UGCPolicy().AddTargetBlankToFullyQualifiedLinks(true)
+)
+
+// ToHTML renders Markdown to sanitized HTML, resolving material references with resolve
+func ToHTML(source string, resolve Resolver) (string, error) {
+	content := []byte(source)
+	document := converter.Parser().Parse(text.NewReader(content), parser.WithContext(parser.NewContext()))
+	resolveReferences(document, content, resolve)
+
+	var rendered bytes.Buffer
+	if err := converter.Renderer().Render(&rendered, content, document); err != nil {
+		return "", err
+	}
+
+	return policy.Sanitize(rendered.String()), nil
+}
+
+// References lists the IDs of the materials the Markdown refers to, without duplicates
+func References(source string) []uint {
+	document := converter.Parser().Parse(text.NewReader([]byte(source)))
+
+	var ids []uint
+	seen := make(map[uint]bool)
+	for _, node := range referenceNodes(document) {
+		id, _ := materialID(destination(node))
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func resolveReferences(document ast.Node, source []byte, resolve Resolver) {
+	for _, node := range referenceNodes(document) {
+		id, _ := materialID(destination(node))
+
+		reference, ok := Reference{}, false
+		if resolve != nil {
+			reference, ok = resolve(id)
+		}
+		if !ok {
+			unwrap(node)
+			continue
+		}
+
+		switch node := node.(type) {
+		case *ast.Link:
+			node.Destination = []byte(reference.URL)
+		case *ast.Image:
+			node.Destination = []byte(reference.URL)
+		}
+		if node.ChildCount() == 0 || len(bytes.TrimSpace(node.Text(source))) == 0 {
+			node.RemoveChildren(node)
+			node.AppendChild(node, ast.NewString([]byte(reference.Title)))
+		}
+	}
+}
+
+// referenceNodes collects the links and images pointing at materials. They are collected
+// first because rewriting the tree while walking it would skip nodes
+func referenceNodes(document ast.Node) []ast.Node {
+	var nodes []ast.Node
+	ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		if _, ok := materialID(destination(node)); ok {
+			nodes = append(nodes, node)
+		}
+		return ast.WalkContinue, nil
+	})
+	return nodes
+}
+
+func destination(node ast.Node) string {
+	switch node := node.(type) {
+	case *ast.Link:
+		return string(node.Destination)
+	case *ast.Image:
+		return string(node.Destination)
+	default:
+		return ""
+	}
+}
+
+func materialID(destination string) (uint, bool) {
+	if !strings.HasPrefix(destination, MaterialScheme) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(destination, MaterialScheme), 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// unwrap replaces the node with its children, keeping a link's text without the link
+func unwrap(node ast.Node) {
+	parent := node.Parent()
+	if parent == nil {
+		return
+	}
+
+	for child := node.FirstChild(); child != nil; {
+		next := child.NextSibling()
+		node.RemoveChild(node, child)
+		parent.InsertBefore(parent, node, child)
+		child = next
+	}
+	parent.RemoveChild(parent, node)
+}
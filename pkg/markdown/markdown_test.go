@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func render(t *testing.T, source string, resolve Resolver) string {
+	t.Helper()
+	rendered, err := ToHTML(source, resolve)
+	if err != nil {
+		t.Fatalf("ToHTML(%q) error = %v", source, err)
+	}
+	return rendered
+}
+
+func TestToHTMLFormatting(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"emphasis", "**bold** and _italic_", "<p><strong>bold</strong> and <em>italic</em></p>"},
+		{"list", "- one\n- two", "<ul>\n<li>one</li>\n<li>two</li>\n</ul>"},
+		{"table", "| a |\n|---|\n| 1 |", "<td>1</td>"},
+		{"external link", "[docs](https://example.com)", `href="https://example.com" rel="nofollow noopener" target="_blank"`},
+		{"raw html kept", "<b>bold</b>", "<b>bold</b>"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := render(t, test.source, nil); !strings.Contains(got, test.want) {
+				t.Errorf("ToHTML(%q) = %q, want it to contain %q", test.source, got, test.want)
+			}
+		})
+	}
+}
+
+func TestToHTMLStripsUnsafeMarkup(t *testing.T) {
+	for _, source := range []string{
+		"<script>alert(1)</script>",
+		`<img src="x.png" onerror="alert(1)">`,
+		`<a href="javascript:alert(1)">click</a>`,
+		"[click](javascript:alert(1))",
+		`<iframe src="https://example.com"></iframe>`,
+		`<style>body { display: none }</style>`,
+		`<form action="/steal"><input name="password"></form>`,
+		`<p style="position: fixed">overlay</p>`,
+	} {
+		t.Run(source, func(t *testing.T) {
+			got := strings.ToLower(render(t, source, nil))
+			for _, unsafe := range []string{"<script", "onerror", "javascript:", "<iframe", "<style", "<form", "<input", "style="} {
+				if strings.Contains(got, unsafe) {
+					t.Errorf("ToHTML(%q) = %q, still contains %q", source, got, unsafe)
+				}
+			}
+		})
+	}
+}
+
+func resolveReleased(materialID uint) (Reference, bool) {
+	if materialID != 12 {
+		return Reference{}, false
+	}
+	return Reference{URL: fmt.Sprintf("/api/student/materials/%d/open", materialID), Title: "Week 1 slides"}, true
+}
+
+func TestToHTMLResolvesReferences(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		source  string
+		resolve Resolver
+		want    string
+	}{
+		{"link", "See [the slides](material:12).", resolveReleased, `<p>See <a href="/api/student/materials/12/open" rel="nofollow">the slides</a>.</p>`},
+		{"empty link text takes the title", "[](material:12)", resolveReleased, `<a href="/api/student/materials/12/open" rel="nofollow">Week 1 slides</a>`},
+		{"image", "![diagram](material:12)", resolveReleased, `<img src="/api/student/materials/12/open" alt="diagram">`},
+		{"unresolved keeps text", "See [the notes](material:7).", resolveReleased, "<p>See the notes.</p>"},
+		{"no resolver keeps text", "See [the slides](material:12).", nil, "<p>See the slides.</p>"},
+		{"invalid ID is not linked", "[x](material:abc)", resolveReleased, `<p>x</p>`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := render(t, test.source, test.resolve); !strings.Contains(got, test.want) {
+				t.Errorf("ToHTML(%q) = %q, want it to contain %q", test.source, got, test.want)
+			}
+		})
+	}
+}
+
+func TestReferences(t *testing.T) {
+	source := "[a](material:3) ![b](material:1) [again](material:3) [web](https://example.com) [bad](material:0)"
+	if got, want := References(source), []uint{3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("References() = %v, want %v", got, want)
+	}
+	if got := References("no references"); got != nil {
+		t.Errorf("References() = %v, want none", got)
+	}
+}
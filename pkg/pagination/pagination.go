@@ -0,0 +1,253 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 200
+)
+
+var (
+	ErrInvalidLimit     = errors.New("limit must be between 1 and " + strconv.Itoa(MaxLimit))
+	ErrInvalidOffset    = errors.New("offset must not be negative")
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSort      = errors.New("invalid sort field")
+	ErrCursorWithOffset = errors.New("cursor and offset cannot be combined")
+)
+
+// SortField maps a public sort key to a column and the SQL type its cursor values are cast to
+type SortField struct {
+	Column string
+	Type   string
+}
+
+// Sorts describes the sort keys a list accepts; ID breaks ties so ordering is always stable
+type Sorts struct {
+	ID      string
+	Default string
+	Fields  map[string]SortField
+}
+
+// Cursor marks the last row of a page by its sort value and ID. Key and Desc record the
+// order it was cut from, so it can't be replayed against a different sort
+type Cursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+	Key   string `json:"k"`
+	Desc  bool   `json:"d,omitempty"`
+}
+
+// Params is a parsed page request; either Offset or Cursor positions the page
+type Params struct {
+	Limit  int
+	Offset int
+	Cursor *Cursor
+	Key    string
+	Desc   bool
+
+	field SortField
+	id    string
+}
+
+// Page describes the result set a page was cut from
+type Page struct {
+	Total      int64
+	NextCursor string
+}
+
+// FromRequest reads limit, offset, cursor and sort from the query string.
+// sort takes a key from sorts.Fields, prefixed with "-" for descending order
+func FromRequest(r *http.Request, sorts Sorts) (Params, error) {
+	query := r.URL.Query()
+	params := Params{Limit: DefaultLimit, id: sorts.ID}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return params, ErrInvalidLimit
+		}
+		params.Limit = limit
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return params, ErrInvalidOffset
+		}
+		params.Offset = offset
+	}
+
+	sort := query.Get("sort")
+	if sort == "" {
+		sort = sorts.Default
+	}
+	if strings.HasPrefix(sort, "-") {
+		params.Desc = true
+		sort = strings.TrimPrefix(sort, "-")
+	}
+	field, ok := sorts.Fields[sort]
+	if !ok {
+		return params, ErrInvalidSort
+	}
+	params.Key = sort
+	params.field = field
+
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		if params.Offset > 0 {
+			return params, ErrCursorWithOffset
+		}
+		cursor, err := params.decodeCursor(cursorStr)
+		if err != nil {
+			return params, err
+		}
+		params.Cursor = cursor
+	}
+
+	return params, nil
+}
+
+// New returns params for the first page of a list in its default order, for callers
+// that walk a whole list rather than serving a request
+func New(sorts Sorts, limit int) Params {
+	field, desc := sorts.Default, false
+	if strings.HasPrefix(field, "-") {
+		field, desc = strings.TrimPrefix(field, "-"), true
+	}
+	return Params{Limit: limit, Key: field, Desc: desc, field: sorts.Fields[field], id: sorts.ID}
+}
+
+// After returns params for the page following the given cursor
+func (p Params) After(cursor string) (Params, error) {
+	decoded, err := p.decodeCursor(cursor)
+	if err != nil {
+		return p, err
+	}
+	p.Offset = 0
+	p.Cursor = decoded
+	return p, nil
+}
+
+// Apply orders the query and restricts it to the page. One extra row is fetched so
+// Trim can tell whether another page follows
+func (p Params) Apply(query *gorm.DB) *gorm.DB {
+	direction, comparison := "ASC", ">"
+	if p.Desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if p.Cursor != nil {
+		query = query.Where(
+			fmt.Sprintf("(%s, %s) %s (CAST(? AS %s), ?)", p.field.Column, p.id, comparison, p.field.Type),
+			p.Cursor.Value, p.Cursor.ID,
+		)
+	}
+
+	return query.
+		Order(fmt.Sprintf("%s %s, %s %s", p.field.Column, direction, p.id, direction)).
+		Offset(p.Offset).
+		Limit(p.Limit + 1)
+}
+
+// Trim drops the look-ahead row fetched by Apply and returns the cursor for the next page,
+// or an empty string on the last page
+func Trim[T any](items []T, p Params, cursor func(T) Cursor) ([]T, string) {
+	if len(items) <= p.Limit {
+		return items, ""
+	}
+
+	items = items[:p.Limit]
+	next := cursor(items[len(items)-1])
+	next.Key, next.Desc = p.Key, p.Desc
+	return items, encodeCursor(next)
+}
+
+// WriteHeaders sets X-Total-Count, X-Next-Cursor and a Link header with first, prev, next
+// and last pages. Offset requests get offset links; cursor requests follow the cursor
+func WriteHeaders(w http.ResponseWriter, r *http.Request, p Params, page Page) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	if page.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
+
+	var links []string
+	link := func(rel string, set map[string]string) {
+		query := r.URL.Query()
+		query.Del("cursor")
+		query.Del("offset")
+		for key, value := range set {
+			query.Set(key, value)
+		}
+		target := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel))
+	}
+
+	link("first", nil)
+	if p.Cursor != nil {
+		if page.NextCursor != "" {
+			link("next", map[string]string{"cursor": page.NextCursor})
+		}
+	} else {
+		if p.Offset > 0 {
+			prev := max(p.Offset-p.Limit, 0)
+			link("prev", map[string]string{"offset": strconv.Itoa(prev)})
+		}
+		if page.NextCursor != "" {
+			link("next", map[string]string{"offset": strconv.Itoa(p.Offset + p.Limit)})
+		}
+		if page.Total > 0 {
+			last := (page.Total - 1) / int64(p.Limit) * int64(p.Limit)
+			link("last", map[string]string{"offset": strconv.FormatInt(last, 10)})
+		}
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+func encodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor rejects cursors cut from another sort order or whose value doesn't
+// parse as the sort field's type, which would otherwise fail in the database
+func (p Params) decodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Key != p.Key || cursor.Desc != p.Desc || !parses(cursor.Value, p.field.Type) {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// parses reports whether a cursor value can be cast to the SQL type of its sort field
+func parses(value string, sqlType string) bool {
+	var err error
+	switch sqlType {
+	case "bigint":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "real":
+		_, err = strconv.ParseFloat(value, 32)
+	case "timestamptz":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	}
+	return err == nil
+}
@@ -0,0 +1,162 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testSorts = Sorts{
+	ID:      "items.id",
+	Default: "id",
+	Fields: map[string]SortField{
+		"id":         {Column: "items.id", Type: "bigint"},
+		"title":      {Column: "items.title", Type: "text"},
+		"created_at": {Column: "items.created_at", Type: "timestamptz"},
+	},
+}
+
+type item struct {
+	ID    uint
+	Title string
+}
+
+func titleCursor(i item) Cursor {
+	return Cursor{Value: i.Title, ID: i.ID}
+}
+
+// nextCursor cuts a two-item page under the given sort and returns the cursor for the next one
+func nextCursor(t *testing.T, sort string) string {
+	t.Helper()
+	params, err := FromRequest(httptest.NewRequest("GET", "/items?limit=1&sort="+sort, nil), testSorts)
+	if err != nil {
+		t.Fatalf("FromRequest() error = %v", err)
+	}
+
+	items, cursor := Trim([]item{{1, "Algebra"}, {2, "Biology"}}, params, titleCursor)
+	if len(items) != 1 || cursor == "" {
+		t.Fatalf("Trim() = %v, %q, want one item and a cursor", items, cursor)
+	}
+	return cursor
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := nextCursor(t, "-title")
+
+	params, err := FromRequest(httptest.NewRequest("GET", "/items?limit=1&sort=-title&cursor="+cursor, nil), testSorts)
+	if err != nil {
+		t.Fatalf("FromRequest() error = %v", err)
+	}
+	want := Cursor{Value: "Algebra", ID: 1, Key: "title", Desc: true}
+	if params.Cursor == nil || *params.Cursor != want {
+		t.Errorf("Cursor = %+v, want %+v", params.Cursor, want)
+	}
+
+	if _, err := New(testSorts, 1).After(cursor); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("After() on the default sort: error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestCursorRejected(t *testing.T) {
+	titleCursor := nextCursor(t, "title")
+
+	for _, test := range []struct {
+		name  string
+		query string
+		want  error
+	}{
+		{"malformed", "sort=title&cursor=%25%25", ErrInvalidCursor},
+		{"other key", "sort=created_at&cursor=" + titleCursor, ErrInvalidCursor},
+		{"other direction", "sort=-title&cursor=" + titleCursor, ErrInvalidCursor},
+		{"value of the wrong type", "sort=id&cursor=" + encodeCursor(Cursor{Value: "Algebra", ID: 1, Key: "id"}), ErrInvalidCursor},
+		{"combined with offset", "sort=title&offset=5&cursor=" + titleCursor, ErrCursorWithOffset},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := FromRequest(httptest.NewRequest("GET", "/items?"+test.query, nil), testSorts)
+			if !errors.Is(err, test.want) {
+				t.Errorf("FromRequest() error = %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestWriteHeaders(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		target string
+		page   Page
+		total  string
+		next   string
+		links  []string
+	}{
+		{
+			name:   "first offset page",
+			target: "/items?limit=10",
+			page:   Page{Total: 25, NextCursor: "abc"},
+			total:  "25",
+			next:   "abc",
+			links: []string{
+				`</items?limit=10>; rel="first"`,
+				`</items?limit=10&offset=10>; rel="next"`,
+				`</items?limit=10&offset=20>; rel="last"`,
+			},
+		},
+		{
+			name:   "last offset page",
+			target: "/items?limit=10&offset=20",
+			page:   Page{Total: 25},
+			total:  "25",
+			links: []string{
+				`</items?limit=10>; rel="first"`,
+				`</items?limit=10&offset=10>; rel="prev"`,
+				`</items?limit=10&offset=20>; rel="last"`,
+			},
+		},
+		{
+			name:   "empty list",
+			target: "/items",
+			page:   Page{},
+			total:  "0",
+			links:  []string{`</items>; rel="first"`},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", test.target, nil)
+			params, err := FromRequest(r, testSorts)
+			if err != nil {
+				t.Fatalf("FromRequest() error = %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			WriteHeaders(w, r, params, test.page)
+
+			if got := w.Header().Get("X-Total-Count"); got != test.total {
+				t.Errorf("X-Total-Count = %q, want %q", got, test.total)
+			}
+			if got := w.Header().Get("X-Next-Cursor"); got != test.next {
+				t.Errorf("X-Next-Cursor = %q, want %q", got, test.next)
+			}
+			if got, want := w.Header().Get("Link"), strings.Join(test.links, ", "); got != want {
+				t.Errorf("Link = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteHeadersFollowsCursor(t *testing.T) {
+	cursor := nextCursor(t, "title")
+	r := httptest.NewRequest("GET", "/items?limit=1&sort=title&cursor="+cursor, nil)
+	params, err := FromRequest(r, testSorts)
+	if err != nil {
+		t.Fatalf("FromRequest() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	WriteHeaders(w, r, params, Page{Total: 3, NextCursor: "next"})
+
+	want := `</items?limit=1&sort=title>; rel="first", </items?cursor=next&limit=1&sort=title>; rel="next"`
+	if got := w.Header().Get("Link"); got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStorage(t, s, "materials/1")
+}
+
+func TestLocalStorageStaysInsideRoot(t *testing.T) {
+	parent := t.TempDir()
+	s, err := NewLocalStorage(filepath.Join(parent, "uploads"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put("../outside", strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Put returned %v, want ErrInvalidKey", err)
+	}
+	if _, err := os.Stat(filepath.Join(parent, "outside")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a file was written outside the storage root")
+	}
+}
+
+// failingReader returns some content and then an error, like a dropped upload
+type failingReader struct {
+	sent bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.sent {
+		return 0, errors.New("connection reset")
+	}
+	r.sent = true
+	return copy(p, "partial"), nil
+}
+
+func TestLocalStorageFailedPutLeavesNothing(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put("materials/1/file", &failingReader{}, "text/plain"); err == nil {
+		t.Fatal("Put succeeded with a failing reader")
+	}
+	if _, err := s.Open("materials/1/file"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after a failed Put returned %v, want ErrNotFound", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, "materials", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("a failed Put left %d files behind", len(entries))
+	}
+}
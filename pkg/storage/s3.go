@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Storage keeps files in a bucket of an S3-compatible service such as AWS S3 or MinIO
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3StorageFromEnv connects with S3_ENDPOINT (host[:port]), S3_BUCKET, S3_ACCESS_KEY,
+// S3_SECRET_KEY and optionally S3_REGION and S3_USE_SSL (default true).
+// Buckets are addressed by path, which every S3-compatible service understands
+func NewS3StorageFromEnv() (*S3Storage, error) {
+	endpoint := os.Getenv("S3_ENDPOINT")
+	bucket := os.Getenv("S3_BUCKET")
+	if endpoint == "" || bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 storage backend")
+	}
+
+	secure := true
+	if value := os.Getenv("S3_USE_SSL"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3_USE_SSL %q", value)
+		}
+		secure = parsed
+	}
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY"), ""),
+		Secure:       secure,
+		Region:       os.Getenv("S3_REGION"),
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewS3Storage(client, bucket, os.Getenv("S3_REGION"))
+}
+
+// NewS3Storage uses the bucket, creating it when it does not exist yet
+func NewS3Storage(client *minio.Client, bucket string, region string) (*S3Storage, error) {
+	ctx := context.Background()
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %q: %w", bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %q: %w", bucket, err)
+		}
+	}
+
+	return &S3Storage{client: client, bucket: bucket}, nil
+}
+
+// Put streams the content as a multipart upload; an error from the reader aborts it
+func (s *S3Storage) Put(key string, content io.Reader, contentType string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(context.Background(), s.bucket, cleaned, content, -1, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+// Open checks the object exists before returning it, since the client only contacts
+// the service on the first read
+func (s *S3Storage) Open(key string) (Object, error) {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := s.client.GetObject(context.Background(), s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return object, nil
+}
+
+// Delete removes the object; S3 treats deleting a missing object as success
+func (s *S3Storage) Delete(key string) error {
+	cleaned, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	return s.client.RemoveObject(context.Background(), s.bucket, cleaned, minio.RemoveObjectOptions{})
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// TestS3Storage runs against a real S3-compatible service, such as a local MinIO:
+//
+//	STORAGE_S3_TEST=1 S3_ENDPOINT=localhost:9000 S3_BUCKET=lesson-test \
+//	S3_ACCESS_KEY=minioadmin S3_SECRET_KEY=minioadmin S3_USE_SSL=false go test ./pkg/storage
+func TestS3Storage(t *testing.T) {
+	if os.Getenv("STORAGE_S3_TEST") == "" {
+		t.Skip("set STORAGE_S3_TEST and the S3_* settings to test against an S3 endpoint")
+	}
+
+	s, err := NewS3StorageFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix, err := RandomKey("storage-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStorage(t, s, prefix)
+}
+
+func TestS3StorageSave(t *testing.T) {
+	if os.Getenv("STORAGE_S3_TEST") == "" {
+		t.Skip("set STORAGE_S3_TEST and the S3_* settings to test against an S3 endpoint")
+	}
+
+	s, err := NewS3StorageFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := RandomKey("storage-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload, err := Save(s, key, bytes.NewReader(bytes.Repeat([]byte("a"), 4096)), 1024)
+	if !errors.Is(err, ErrFileTooLarge) {
+		t.Fatalf("Save returned %v, want ErrFileTooLarge", err)
+	}
+	if upload != nil {
+		t.Errorf("Save returned an upload for a file over the limit")
+	}
+	if _, err := s.Open(key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after an oversized Save returned %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"mime"
+	"net/http"
+	"time"
+)
+
+// Serve streams the object as a download under fileName. Range and conditional requests
+// are answered by http.ServeContent; a non-empty checksum becomes the ETag
+func Serve(w http.ResponseWriter, r *http.Request, object Object, fileName string, contentType string, modTime time.Time, checksum string) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if checksum != "" {
+		w.Header().Set("ETag", `"`+checksum+`"`)
+	}
+
+	http.ServeContent(w, r, fileName, modTime, object)
+}
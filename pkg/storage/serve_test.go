@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func serveTestFile(t *testing.T, header http.Header) *http.Response {
+	t.Helper()
+
+	s := newTestStorage(t)
+	if err := s.Put("materials/1/file", strings.NewReader("0123456789"), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	object, err := s.Open("materials/1/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Close()
+
+	request := httptest.NewRequest(http.MethodGet, "/api/files", nil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	recorder := httptest.NewRecorder()
+	Serve(recorder, request, object, "notes.txt", "text/plain", time.Unix(1700000000, 0), "abc123")
+	return recorder.Result()
+}
+
+func readBody(t *testing.T, response *http.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestServe(t *testing.T) {
+	response := serveTestFile(t, nil)
+
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("status %d, want 200", response.StatusCode)
+	}
+	if body := readBody(t, response); body != "0123456789" {
+		t.Errorf("body %q", body)
+	}
+
+	expected := map[string]string{
+		"Content-Type":           "text/plain",
+		"Content-Disposition":    `attachment; filename=notes.txt`,
+		"X-Content-Type-Options": "nosniff",
+		"ETag":                   `"abc123"`,
+		"Accept-Ranges":          "bytes",
+	}
+	for name, value := range expected {
+		if got := response.Header.Get(name); got != value {
+			t.Errorf("%s is %q, want %q", name, got, value)
+		}
+	}
+}
+
+func TestServeRanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		rangeHeader  string
+		status       int
+		body         string
+		contentRange string
+	}{
+		{"first bytes", "bytes=0-3", http.StatusPartialContent, "0123", "bytes 0-3/10"},
+		{"middle", "bytes=4-6", http.StatusPartialContent, "456", "bytes 4-6/10"},
+		{"open ended", "bytes=7-", http.StatusPartialContent, "789", "bytes 7-9/10"},
+		{"suffix", "bytes=-2", http.StatusPartialContent, "89", "bytes 8-9/10"},
+		{"past the end", "bytes=20-30", http.StatusRequestedRangeNotSatisfiable, "", "bytes */10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			response := serveTestFile(t, http.Header{"Range": {test.rangeHeader}})
+
+			if response.StatusCode != test.status {
+				t.Fatalf("status %d, want %d", response.StatusCode, test.status)
+			}
+			if got := response.Header.Get("Content-Range"); got != test.contentRange {
+				t.Errorf("Content-Range %q, want %q", got, test.contentRange)
+			}
+			if test.status == http.StatusPartialContent {
+				if body := readBody(t, response); body != test.body {
+					t.Errorf("body %q, want %q", body, test.body)
+				}
+			}
+		})
+	}
+}
+
+func TestServeMultipleRanges(t *testing.T) {
+	response := serveTestFile(t, http.Header{"Range": {"bytes=0-1,8-9"}})
+
+	if response.StatusCode != http.StatusPartialContent {
+		t.Fatalf("status %d, want 206", response.StatusCode)
+	}
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "multipart/byteranges") {
+		t.Errorf("Content-Type %q", response.Header.Get("Content-Type"))
+	}
+	body := readBody(t, response)
+	if !strings.Contains(body, "01") || !strings.Contains(body, "89") {
+		t.Errorf("body %q is missing a range", body)
+	}
+}
+
+func TestServeConditional(t *testing.T) {
+	response := serveTestFile(t, http.Header{"If-None-Match": {`"abc123"`}})
+	if response.StatusCode != http.StatusNotModified {
+		t.Errorf("If-None-Match with the checksum gave status %d, want 304", response.StatusCode)
+	}
+
+	// A stale If-Range ignores the range and sends the whole file
+	response = serveTestFile(t, http.Header{"Range": {"bytes=0-3"}, "If-Range": {`"stale"`}})
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("stale If-Range gave status %d, want 200", response.StatusCode)
+	}
+	if body := readBody(t, response); !bytes.Equal([]byte(body), []byte("0123456789")) {
+		t.Errorf("body %q", body)
+	}
+}
+
+func TestServeDefaultsContentType(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/api/files", nil)
+	recorder := httptest.NewRecorder()
+	Serve(recorder, request, nopObject{strings.NewReader("data")}, "data.bin", "", time.Time{}, "")
+
+	if got := recorder.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type %q, want application/octet-stream", got)
+	}
+	if got := recorder.Header().Get("ETag"); got != "" {
+		t.Errorf("ETag %q without a checksum", got)
+	}
+}
+
+type nopObject struct {
+	*strings.Reader
+}
+
+func (nopObject) Close() error {
+	return nil
+}
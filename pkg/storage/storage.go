@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultLocalDir       = "uploads"
+	defaultMaxUploadBytes = 25 << 20
+)
+
+var (
+	ErrNotFound   = errors.New("stored object not found")
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// Storage keeps uploaded files under slash-separated keys such as "assignments/4/17/3f9c..."
+type Storage interface {
+	Put(key string, content io.Reader, contentType string) error
+	Open(key string) (Object, error)
+	Delete(key string) error
+}
+
+// Object is an open stored file. Seeking lets downloads serve byte ranges
+type Object interface {
+	io.ReadSeekCloser
+}
+
+// Uploads bundles the configured storage with the settings every upload feature shares
+type Uploads struct {
+	Storage        Storage
+	Signer         *URLSigner
+	MaxUploadBytes int64
+}
+
+// NewUploads sets up storage, download link signing and the upload size limit from the environment
+func NewUploads() (*Uploads, error) {
+	fileStorage, err := New()
+	if err != nil {
+		return nil, err
+	}
+
+	maxUploadBytes, err := MaxUploadBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Uploads{
+		Storage:        fileStorage,
+		Signer:         NewURLSigner(),
+		MaxUploadBytes: maxUploadBytes,
+	}, nil
+}
+
+// New returns the storage selected by STORAGE_BACKEND: "local" (the default) keeps files in
+// STORAGE_DIR, or ./uploads when it is unset, and "s3" uses the S3_* settings
+func New() (Storage, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "local":
+		dir := os.Getenv("STORAGE_DIR")
+		if dir == "" {
+			dir = defaultLocalDir
+		}
+		return NewLocalStorage(dir)
+	case "s3":
+		return NewS3StorageFromEnv()
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE_BACKEND %q", backend)
+	}
+}
+
+// MaxUploadBytes returns the largest file STORAGE_MAX_UPLOAD_BYTES allows, 25 MiB by default
+func MaxUploadBytes() (int64, error) {
+	value := os.Getenv("STORAGE_MAX_UPLOAD_BYTES")
+	if value == "" {
+		return defaultMaxUploadBytes, nil
+	}
+
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid STORAGE_MAX_UPLOAD_BYTES %q", value)
+	}
+	return limit, nil
+}
+
+// RandomKey names a new object under prefix, as in "materials/12/3f9c...". The random part
+// keeps keys unguessable and unique, so two rows never share a stored file
+func RandomKey(prefix string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	return prefix + "/" + hex.EncodeToString(random), nil
+}
+
+// CleanFileName keeps only the base name of an uploaded file, or fallback when there is none
+func CleanFileName(name string, fallback string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return fallback
+	}
+	return name
+}
+
+// cleanKey rejects keys that are empty, absolute or escape the storage root
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+
+	cleaned := path.Clean(key)
+	if cleaned != key || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCleanKey(t *testing.T) {
+	valid := []string{
+		"materials/4/3f9c",
+		"assignments/4/17/abc",
+		"file.pdf",
+		"a/..b/c",
+	}
+	for _, key := range valid {
+		cleaned, err := cleanKey(key)
+		if err != nil {
+			t.Errorf("cleanKey(%q) returned %v", key, err)
+		}
+		if cleaned != key {
+			t.Errorf("cleanKey(%q) = %q", key, cleaned)
+		}
+	}
+
+	invalid := []string{
+		"",
+		".",
+		"..",
+		"../etc/passwd",
+		"../../secret",
+		"materials/../../secret",
+		"materials/./file",
+		"materials//file",
+		"materials/file/",
+		"/etc/passwd",
+		"materials\\..\\secret",
+		"..\\secret",
+	}
+	for _, key := range invalid {
+		if _, err := cleanKey(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("cleanKey(%q) returned %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	first, err := RandomKey("materials/4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := RandomKey("materials/4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(first, "materials/4/") {
+		t.Errorf("key %q is not under its prefix", first)
+	}
+	if first == second {
+		t.Errorf("two keys are both %q", first)
+	}
+	if _, err := cleanKey(first); err != nil {
+		t.Errorf("generated key %q is not valid: %v", first, err)
+	}
+}
+
+func TestCleanFileName(t *testing.T) {
+	tests := map[string]string{
+		"notes.pdf":               "notes.pdf",
+		"  notes.pdf ":            "notes.pdf",
+		"../../etc/passwd":        "passwd",
+		`C:\Users\ada\essay.docx`: "essay.docx",
+		"folder/":                 "folder",
+		"":                        "upload",
+		"/":                       "upload",
+		".":                       "upload",
+	}
+	for name, want := range tests {
+		if got := CleanFileName(name, "upload"); got != want {
+			t.Errorf("CleanFileName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// testStorage runs the behaviour every backend shares against s
+func testStorage(t *testing.T, s Storage, prefix string) {
+	t.Helper()
+
+	key := prefix + "/notes.txt"
+	content := []byte("lesson notes")
+	if err := s.Put(key, bytes.NewReader(content), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	object, err := s.Open(key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	read, err := io.ReadAll(object)
+	object.Close()
+	if err != nil {
+		t.Fatalf("reading the object: %v", err)
+	}
+	if !bytes.Equal(read, content) {
+		t.Errorf("read %q, want %q", read, content)
+	}
+
+	// Putting the same key again replaces the content
+	replaced := []byte("revised notes")
+	if err := s.Put(key, bytes.NewReader(replaced), "text/plain"); err != nil {
+		t.Fatalf("Put over an existing key: %v", err)
+	}
+	object, err = s.Open(key)
+	if err != nil {
+		t.Fatalf("Open after replacing: %v", err)
+	}
+	read, _ = io.ReadAll(object)
+	object.Close()
+	if !bytes.Equal(read, replaced) {
+		t.Errorf("read %q after replacing, want %q", read, replaced)
+	}
+
+	if err := s.Delete(key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Open(key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete returned %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(key); err != nil {
+		t.Errorf("deleting a missing object returned %v", err)
+	}
+
+	if _, err := s.Open(prefix + "/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of a missing key returned %v, want ErrNotFound", err)
+	}
+
+	for _, key := range []string{"../escape", "/absolute", ""} {
+		if err := s.Put(key, strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) returned %v, want ErrInvalidKey", key, err)
+		}
+		if _, err := s.Open(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Open(%q) returned %v, want ErrInvalidKey", key, err)
+		}
+		if err := s.Delete(key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Delete(%q) returned %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
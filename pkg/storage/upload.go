@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// sniffBytes is how much of a file content type detection looks at
+const sniffBytes = 512
+
+var (
+	ErrEmptyFile    = errors.New("file is empty")
+	ErrFileTooLarge = errors.New("file exceeds the upload size limit")
+)
+
+// Upload describes a file stored by Save
+type Upload struct {
+	Key         string
+	ContentType string
+	Size        int64
+	Checksum    string
+}
+
+// Save stores the content under key, refusing empty files and files over maxBytes.
+// The content type is detected from the bytes themselves rather than trusted from the
+// client, and the SHA-256 checksum is computed while streaming
+func Save(s Storage, key string, content io.Reader, maxBytes int64) (*Upload, error) {
+	buffered := bufio.NewReaderSize(content, sniffBytes)
+	head, err := buffered.Peek(sniffBytes)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(head) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	upload := &Upload{
+		Key:         key,
+		ContentType: http.DetectContentType(head),
+	}
+
+	hash := sha256.New()
+	counted := &limitedReader{reader: io.TeeReader(buffered, hash), remaining: maxBytes}
+	if err := s.Put(key, counted, upload.ContentType); err != nil {
+		// Backends may wrap the reader's error, so ask the reader whether it hit the limit
+		if counted.exceeded {
+			// Backends abort on a reader error, but make sure nothing partial is left behind
+			s.Delete(key)
+			return nil, ErrFileTooLarge
+		}
+		return nil, err
+	}
+
+	upload.Size = maxBytes - counted.remaining
+	upload.Checksum = hex.EncodeToString(hash.Sum(nil))
+	return upload, nil
+}
+
+// limitedReader fails with ErrFileTooLarge once more than the remaining bytes are read,
+// instead of stopping silently like io.LimitReader
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	if int64(n) > r.remaining {
+		r.exceeded = true
+		return 0, ErrFileTooLarge
+	}
+	r.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestStorage(t *testing.T) *LocalStorage {
+	t.Helper()
+	s, err := NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSave(t *testing.T) {
+	s := newTestStorage(t)
+	content := []byte("%PDF-1.4\nlesson handout")
+
+	upload, err := Save(s, "materials/1/handout", bytes.NewReader(content), 1024)
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	if upload.Checksum != hex.EncodeToString(sum[:]) {
+		t.Errorf("checksum %s, want %s", upload.Checksum, hex.EncodeToString(sum[:]))
+	}
+	if upload.Size != int64(len(content)) {
+		t.Errorf("size %d, want %d", upload.Size, len(content))
+	}
+	if upload.Key != "materials/1/handout" {
+		t.Errorf("key %q", upload.Key)
+	}
+
+	object, err := s.Open(upload.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer object.Close()
+	stored, _ := io.ReadAll(object)
+	if !bytes.Equal(stored, content) {
+		t.Errorf("stored %q, want %q", stored, content)
+	}
+}
+
+func TestSaveSniffsContentType(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     []byte
+		contentType string
+	}{
+		{"pdf", []byte("%PDF-1.7\n..."), "application/pdf"},
+		{"png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+		{"html", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), "text/html; charset=utf-8"},
+		{"text", []byte("plain notes"), "text/plain; charset=utf-8"},
+		{"binary", []byte{0x00, 0x01, 0x02, 0x03}, "application/octet-stream"},
+	}
+
+	s := newTestStorage(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upload, err := Save(s, "materials/1/"+test.name, bytes.NewReader(test.content), 1024)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if upload.ContentType != test.contentType {
+				t.Errorf("content type %q, want %q", upload.ContentType, test.contentType)
+			}
+		})
+	}
+}
+
+func TestSaveRejectsEmptyFiles(t *testing.T) {
+	s := newTestStorage(t)
+
+	if _, err := Save(s, "materials/1/empty", strings.NewReader(""), 1024); !errors.Is(err, ErrEmptyFile) {
+		t.Fatalf("Save returned %v, want ErrEmptyFile", err)
+	}
+	if _, err := s.Open("materials/1/empty"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("an empty file was stored")
+	}
+}
+
+func TestSaveSizeLimit(t *testing.T) {
+	const limit = 2048
+
+	tests := []struct {
+		name string
+		size int
+		err  error
+	}{
+		{"under the limit", limit - 1, nil},
+		{"at the limit", limit, nil},
+		{"one byte over", limit + 1, ErrFileTooLarge},
+		{"far over", limit * 10, ErrFileTooLarge},
+	}
+
+	s := newTestStorage(t)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key := "materials/1/" + strings.ReplaceAll(test.name, " ", "-")
+			upload, err := Save(s, key, bytes.NewReader(bytes.Repeat([]byte("a"), test.size)), limit)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("Save returned %v, want %v", err, test.err)
+			}
+
+			_, openErr := s.Open(key)
+			if test.err != nil {
+				if !errors.Is(openErr, ErrNotFound) {
+					t.Errorf("a file over the limit was stored")
+				}
+				return
+			}
+			if openErr != nil {
+				t.Errorf("Open: %v", openErr)
+			}
+			if upload.Size != int64(test.size) {
+				t.Errorf("size %d, want %d", upload.Size, test.size)
+			}
+		})
+	}
+}